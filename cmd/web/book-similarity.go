@@ -0,0 +1,85 @@
+package main
+
+import (
+	"time"
+
+	"github.com/ishanshre/Book-Review-Platform/internals/recommend"
+	"github.com/ishanshre/Book-Review-Platform/internals/repository"
+)
+
+// bookSimilarityInterval is the time between two computations of the book similarities
+const bookSimilarityInterval = 24 * time.Hour
+
+// bookSimilarityOptions tunes the item to item similarity of the recommendations
+var bookSimilarityOptions = recommend.Options{
+	MinSupport: 2,
+	TopK:       20,
+	MaxPerUser: 500,
+}
+
+// bookContentSimilarityOptions tunes the content similarity of the similar books
+var bookContentSimilarityOptions = recommend.ContentOptions{
+	MinScore: 0.05,
+	TopK:     20,
+}
+
+// startBookSimilarityJob starts a goroutine that recomputes the book similarities from the reviews, lists and shelves of the readers.
+// The content similarities are recomputed too since the frequencies of the words in the descriptions change as books are added.
+func startBookSimilarityJob(repo repository.DatabaseRepo) {
+	go func() {
+		ticker := time.NewTicker(bookSimilarityInterval)
+		defer ticker.Stop()
+		for {
+			computeBookSimilarities(repo)
+			computeBookContentSimilarities(repo)
+			<-ticker.C
+		}
+	}()
+}
+
+// computeBookSimilarities replaces the book similarities with the ones computed from the current interactions
+func computeBookSimilarities(repo repository.DatabaseRepo) {
+	interactions, err := repo.BookInteractions()
+	if err != nil {
+		errorLog.Println(err)
+		return
+	}
+	similarities := recommend.Similarities(interactions, bookSimilarityOptions, time.Now())
+	if err := repo.ReplaceBookSimilarities(similarities); err != nil {
+		errorLog.Println(err)
+		return
+	}
+	infoLog.Printf("computed %d book similarities from %d interactions", len(similarities), len(interactions))
+}
+
+// computeBookContentSimilarities replaces the content similarities of every book
+func computeBookContentSimilarities(repo repository.DatabaseRepo) {
+	books, err := repo.BookFeatures()
+	if err != nil {
+		errorLog.Println(err)
+		return
+	}
+	similarities := recommend.NewContentIndex(books).All(bookContentSimilarityOptions, time.Now())
+	if err := repo.ReplaceAllBookContentSimilarities(similarities); err != nil {
+		errorLog.Println(err)
+		return
+	}
+	infoLog.Printf("computed %d book content similarities from %d books", len(similarities), len(books))
+}
+
+// refreshBookContentSimilarities recomputes the content similarities of the created or changed books.
+// Inactive books are not indexed so their similarities are only removed.
+func refreshBookContentSimilarities(repo repository.DatabaseRepo, book_ids ...int) error {
+	books, err := repo.BookFeatures()
+	if err != nil {
+		return err
+	}
+	index := recommend.NewContentIndex(books)
+	now := time.Now()
+	for _, book_id := range book_ids {
+		if err := repo.ReplaceBookContentSimilarities(book_id, index.Similar(book_id, bookContentSimilarityOptions, now)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+	"github.com/ishanshre/Book-Review-Platform/internals/repository"
+)
+
+// digestInterval is the time between two checks for due digests
+const digestInterval = time.Hour
+
+// startDigestJob starts a goroutine that queues the digest emails of the users whose digest is due
+func startDigestJob(repo repository.DatabaseRepo) {
+	go func() {
+		ticker := time.NewTicker(digestInterval)
+		defer ticker.Stop()
+		for {
+			sendDigests(repo)
+			<-ticker.C
+		}
+	}()
+}
+
+// sendDigests queues the digest of every due recipient.
+// Users without any activity since their last digest do not receive an email.
+func sendDigests(repo repository.DatabaseRepo) {
+	recipients, err := repo.DueDigestRecipients()
+	if err != nil {
+		errorLog.Println(err)
+		return
+	}
+	for _, recipient := range recipients {
+		now := time.Now()
+		digest, err := repo.GetDigest(recipient.UserID, digestSince(recipient, now))
+		if err != nil {
+			errorLog.Println(err)
+			continue
+		}
+		if len(digest.Books) > 0 || len(digest.Reviews) > 0 || len(digest.Genres) > 0 {
+			msg := digestMail(recipient, digest)
+			if err := repo.EnqueueMail(msg); err != nil {
+				errorLog.Println(err)
+				continue
+			}
+		}
+		if err := repo.MarkDigestSent(recipient.UserID, now); err != nil {
+			errorLog.Println(err)
+		}
+	}
+}
+
+// digestSince returns the start of the digest period of the recipient
+func digestSince(recipient *models.DigestRecipient, now time.Time) time.Time {
+	if recipient.LastSentAt != nil {
+		return *recipient.LastSentAt
+	}
+	switch recipient.Frequency {
+	case models.DigestDaily:
+		return now.AddDate(0, 0, -1)
+	case models.DigestWeekly:
+		return now.AddDate(0, 0, -7)
+	}
+	return now.AddDate(0, -1, 0)
+}
+
+// digestMail composes the digest email of the recipient.
+// The template data only holds strings so that it is stored unchanged in the email outbox.
+func digestMail(recipient *models.DigestRecipient, digest *models.Digest) *models.MailData {
+	books := []map[string]string{}
+	for _, book := range digest.Books {
+		books = append(books, map[string]string{
+			"Title":  book.Title,
+			"Author": book.Author,
+			"Link":   fmt.Sprintf("%s/books/%d", app.BaseURL, book.Isbn),
+		})
+	}
+	reviews := []map[string]string{}
+	for _, review := range digest.Reviews {
+		reviews = append(reviews, map[string]string{
+			"BookTitle": review.BookTitle,
+			"Username":  review.Username,
+			"Rating":    fmt.Sprintf("%.1f", review.Rating),
+			"Link":      fmt.Sprintf("%s/books/%d", app.BaseURL, review.Isbn),
+		})
+	}
+	genres := []map[string]string{}
+	for _, genre := range digest.Genres {
+		genres = append(genres, map[string]string{
+			"Title": genre.Title,
+			"Link":  fmt.Sprintf("%s/genres/%s", app.BaseURL, url.PathEscape(genre.Title)),
+		})
+	}
+	unsubscribe := fmt.Sprintf("%s/digest/unsubscribe/%s", app.BaseURL, recipient.UnsubscribeToken)
+	return &models.MailData{
+		To:          recipient.Email,
+		From:        app.AdminEmail,
+		Subject:     fmt.Sprintf("Your %s BookWorm digest", recipient.Frequency),
+		Template:    "digest",
+		Unsubscribe: unsubscribe,
+		Data: map[string]interface{}{
+			"Username":        recipient.Username,
+			"Frequency":       recipient.Frequency,
+			"Books":           books,
+			"Reviews":         reviews,
+			"Genres":          genres,
+			"UnsubscribeLink": unsubscribe,
+			"PreferencesLink": fmt.Sprintf("%s/profile", app.BaseURL),
+		},
+	}
+}
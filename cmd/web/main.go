@@ -7,17 +7,23 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/alexedwards/scs/redisstore"
 	"github.com/alexedwards/scs/v2"
 	"github.com/gomodule/redigo/redis"
+	"github.com/ishanshre/Book-Review-Platform/internals/cache"
 	"github.com/ishanshre/Book-Review-Platform/internals/config"
 	"github.com/ishanshre/Book-Review-Platform/internals/driver"
+	"github.com/ishanshre/Book-Review-Platform/internals/events"
 	"github.com/ishanshre/Book-Review-Platform/internals/handler"
 	"github.com/ishanshre/Book-Review-Platform/internals/helpers"
+	"github.com/ishanshre/Book-Review-Platform/internals/mailer"
 	"github.com/ishanshre/Book-Review-Platform/internals/middleware"
 	"github.com/ishanshre/Book-Review-Platform/internals/models"
+	"github.com/ishanshre/Book-Review-Platform/internals/pubsub"
 	"github.com/ishanshre/Book-Review-Platform/internals/render"
 	"github.com/ishanshre/Book-Review-Platform/internals/router"
 	"github.com/joho/godotenv"
@@ -54,12 +60,36 @@ func main() {
 
 	defer db.SQL.Close()
 
-	// close the channel at last
-	defer close(app.MailChan)
+	// register the side effects of the domain events published by the handlers
+	registerSubscribers(eventBus, handler.Repo.DB)
+
+	app.InfoLog.Println("Starting the live updates hub")
+	// receive the live updates published by every instance
+	liveHub.Start()
 
 	app.InfoLog.Println("Starting the mail listener")
-	// starting the mail listener
-	listenForMail()
+	// starting the mail listener which sends the mails from the email outbox
+	listenForMail(handler.Repo.DB)
+
+	app.InfoLog.Println("Starting the webhook listener")
+	// starting the webhook listener which posts the queued events to the webhook endpoints
+	listenForWebhooks(handler.Repo.DB)
+
+	app.InfoLog.Println("Starting the digest job")
+	// starting the job which queues the digest emails
+	startDigestJob(handler.Repo.DB)
+
+	app.InfoLog.Println("Starting the reading goal reminder job")
+	// starting the job which reminds the readers behind their reading goal
+	startReadingGoalReminderJob(handler.Repo.DB)
+
+	app.InfoLog.Println("Starting the session cleanup job")
+	// starting the job which removes the expired sessions from the session index
+	startSessionCleanupJob(handler.Repo.DB, session.Lifetime)
+
+	app.InfoLog.Println("Starting the book similarity job")
+	// starting the job which computes the book similarities for the recommendations
+	startBookSimilarityJob(handler.Repo.DB)
 
 	// pass app config to middleware
 	middleware.NewMiddlewareApp(&app)
@@ -95,12 +125,68 @@ func Run() (*driver.DB, error) {
 	// Set default admin email
 	app.AdminEmail = "admin@bookworm.com"
 
+	// number of open reports that hides a review or comment until it is moderated
+	reportHideThreshold, err := strconv.Atoi(os.Getenv("report_hide_threshold"))
+	if err != nil {
+		reportHideThreshold = 3
+	}
+	app.ReportHideThreshold = reportHideThreshold
+
+	// reverse proxies allowed to set the X-Forwarded-For header
+	trustedProxies, err := helpers.ParseTrustedProxies(os.Getenv("trusted_proxies"))
+	if err != nil {
+		return nil, err
+	}
+	app.TrustedProxies = trustedProxies
+
+	// configure the password hasher used for new and outdated password hashes
+	bcryptCost, err := strconv.Atoi(os.Getenv("bcrypt_cost"))
+	if err != nil {
+		bcryptCost = 12
+	}
+	hasher, err := helpers.NewPasswordHasher(os.Getenv("password_hasher"), bcryptCost)
+	if err != nil {
+		return nil, err
+	}
+	helpers.SetPasswordHasher(hasher)
+
 	// store the values in the session
 	gob.Register(models.User{})
 
-	// create a mail channel and assign it to app.MailChan
-	mailChan := make(chan models.MailData, 10)
-	app.MailChan = mailChan
+	// public address used for links in emails, never taken from the Host header of a request
+	app.BaseURL = strings.TrimSuffix(os.Getenv("base_url"), "/")
+	if app.BaseURL == "" {
+		app.BaseURL = "http://localhost:8000"
+	}
+
+	// configure the mail transport
+	smtpPort, err := strconv.Atoi(os.Getenv("smtp_port"))
+	if err != nil {
+		smtpPort = 1025
+	}
+	smtpHost := os.Getenv("smtp_host")
+	if smtpHost == "" {
+		smtpHost = "localhost"
+	}
+	mailTransport, err = mailer.New(mailer.Config{
+		Transport:  os.Getenv("mail_transport"),
+		Host:       smtpHost,
+		Port:       smtpPort,
+		Username:   os.Getenv("smtp_username"),
+		Password:   os.Getenv("smtp_password"),
+		Encryption: os.Getenv("smtp_encryption"),
+		Dir:        os.Getenv("mail_dir"),
+		Logger:     infoLog,
+		DKIM: mailer.DKIMConfig{
+			Domain:         os.Getenv("dkim_domain"),
+			Selector:       os.Getenv("dkim_selector"),
+			PrivateKeyPath: os.Getenv("dkim_private_key"),
+		},
+		Unsubscribe: os.Getenv("mail_unsubscribe"),
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	// change to true in production
 	app.InProduction = false
@@ -110,8 +196,9 @@ func Run() (*driver.DB, error) {
 	session = scs.New()
 
 	// Establish a pool to Redis if UseRedis config is true
+	var pool *redis.Pool
 	if app.UseRedis {
-		pool := &redis.Pool{
+		pool = &redis.Pool{
 			MaxIdle: 10,
 			Dial: func() (redis.Conn, error) {
 				return redis.Dial("tcp", host)
@@ -119,6 +206,12 @@ func Run() (*driver.DB, error) {
 		}
 		session.Store = redisstore.New(pool)
 	}
+
+	// live updates hub, shared between the instances through redis pub/sub when redis is used
+	liveHub = pubsub.NewHub(pool, "bookworm:live:", errorLog)
+
+	// cache of the home feeds, shared between the instances through redis when redis is used
+	feedCache = cache.New(pool, "bookworm:cache:")
 	session.Lifetime = 24 * time.Hour // set time of the session
 	session.Cookie.Persist = true     // true means session retains in browser even if browser is closed
 	session.Cookie.SameSite = http.SameSiteLaxMode
@@ -148,8 +241,13 @@ func Run() (*driver.DB, error) {
 		return nil, fmt.Errorf("error in connecting to database: %v", err)
 	}
 
+	// event bus used by the handlers to publish domain events, errors of async subscribers are logged
+	eventBus = events.New(func(e events.Event, err error) {
+		errorLog.Printf("subscriber of %s failed: %s", e.Name(), err)
+	})
+
 	// handlers connecting to database
-	repo := handler.NewRepo(&app, db)
+	repo := handler.NewRepo(&app, db, eventBus, liveHub, feedCache)
 	handler.NewHandler(repo)
 
 	helpers.NewHelpers(&app)
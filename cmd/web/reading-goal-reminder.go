@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+	"github.com/ishanshre/Book-Review-Platform/internals/repository"
+)
+
+// readingGoalReminderInterval is the time between two checks for readers behind their goal
+const readingGoalReminderInterval = time.Hour
+
+// readingGoalReminderGap is the least time between two reminders of the same goal
+const readingGoalReminderGap = 7 * 24 * time.Hour
+
+// startReadingGoalReminderJob starts a goroutine that reminds the readers who are behind the pace of their reading goal
+func startReadingGoalReminderJob(repo repository.DatabaseRepo) {
+	go func() {
+		ticker := time.NewTicker(readingGoalReminderInterval)
+		defer ticker.Stop()
+		for {
+			sendReadingGoalReminders(repo)
+			<-ticker.C
+		}
+	}()
+}
+
+// sendReadingGoalReminders queues the reminder of every reader behind the pace of their goal for the current year.
+// Readers on track are not reminded and are checked again on the next run.
+func sendReadingGoalReminders(repo repository.DatabaseRepo) {
+	now := time.Now()
+	recipients, err := repo.ReadingGoalReminderRecipients(now.Year(), now.Add(-readingGoalReminderGap))
+	if err != nil {
+		errorLog.Println(err)
+		return
+	}
+	for _, recipient := range recipients {
+		if !recipient.Goal.Behind(now) {
+			continue
+		}
+		if err := repo.EnqueueMail(readingGoalReminderMail(recipient, now)); err != nil {
+			errorLog.Println(err)
+			continue
+		}
+		if err := repo.MarkReadingGoalReminded(recipient.Goal.ID, now); err != nil {
+			errorLog.Println(err)
+		}
+	}
+}
+
+// readingGoalReminderMail composes the reminder email of the reader behind their goal.
+// The template data only holds strings so that it is stored unchanged in the email outbox.
+func readingGoalReminderMail(recipient *models.ReadingGoalRecipient, now time.Time) *models.MailData {
+	goal := recipient.Goal
+	unsubscribe := fmt.Sprintf("%s/reading-goal/unsubscribe/%s", app.BaseURL, goal.UnsubscribeToken)
+	return &models.MailData{
+		To:          recipient.Email,
+		From:        app.AdminEmail,
+		Subject:     fmt.Sprintf("You are behind your %d reading goal", goal.Year),
+		Template:    "reading_goal_reminder",
+		Unsubscribe: unsubscribe,
+		Data: map[string]interface{}{
+			"Username":        recipient.Username,
+			"Year":            fmt.Sprint(goal.Year),
+			"Target":          fmt.Sprint(goal.Target),
+			"Finished":        fmt.Sprint(goal.Finished),
+			"Expected":        fmt.Sprint(goal.Expected(now)),
+			"ReadListLink":    fmt.Sprintf("%s/read-list", app.BaseURL),
+			"ChallengeLink":   fmt.Sprintf("%s/challenge", app.BaseURL),
+			"UnsubscribeLink": unsubscribe,
+			"PreferencesLink": fmt.Sprintf("%s/profile", app.BaseURL),
+		},
+	}
+}
@@ -1,53 +1,90 @@
 package main
 
 import (
-	"log"
+	"encoding/json"
 	"time"
 
+	"github.com/ishanshre/Book-Review-Platform/internals/mailer"
 	"github.com/ishanshre/Book-Review-Platform/internals/models"
-	mail "github.com/xhit/go-simple-mail/v2"
+	"github.com/ishanshre/Book-Review-Platform/internals/repository"
 )
 
-// listenForMail is a goroutine that listens for mail messages from the app.MailChan channel
-// and sends them using the sendMsg function.
-func listenForMail() {
+// mailTransport delivers the emails, configured in Run from the environment
+var mailTransport mailer.Mailer
+
+const (
+	mailPollInterval = 5 * time.Second // time between two checks of the outbox
+	mailBatchSize    = 10              // number of mails claimed per check
+	mailLease        = time.Minute     // time a claimed mail is hidden from other workers
+	mailBaseBackoff  = 30 * time.Second
+	mailMaxBackoff   = 6 * time.Hour
+)
+
+// listenForMail starts a goroutine that sends the pending mails of the email outbox.
+// Failed mails are retried with exponential backoff until they reach their max attempts.
+func listenForMail(repo repository.DatabaseRepo) {
 	go func() {
-		for msg := range app.MailChan {
-			sendMsg(&msg)
+		ticker := time.NewTicker(mailPollInterval)
+		defer ticker.Stop()
+		for {
+			processOutbox(repo)
+			<-ticker.C
 		}
 	}()
 }
 
-// sendMsg sends an email using the provided mail data.
-// It connects to the SMTP server and sends the email using the go-simple-mail library.
-func sendMsg(m *models.MailData) {
-	// Create a new SMTP client
-	server := mail.NewSMTPClient()
-	server.Host = "localhost"
-	server.Port = 1025
-	server.KeepAlive = false
-	server.ConnectTimeout = 10 * time.Second
-	server.SendTimeout = 10 * time.Second
-	// In production
-	// server.Username
-	// server.Password
-	// server.Encryption
-
-	// Connect to the SMTP server
-	client, err := server.Connect()
+// processOutbox claims the due mails and sends them one by one
+func processOutbox(repo repository.DatabaseRepo) {
+	emails, err := repo.ClaimPendingEmails(mailBatchSize, mailLease)
 	if err != nil {
 		errorLog.Println(err)
+		return
+	}
+	for _, email := range emails {
+		if err := sendMsg(email); err != nil {
+			attempt := email.Attempts + 1
+			errorLog.Printf("sending email %d to %s failed (attempt %d of %d): %s", email.ID, email.To, attempt, email.MaxAttempts, err)
+			if err := repo.MarkEmailAttemptFailed(email.ID, err.Error(), time.Now().Add(retryBackoff(attempt, mailBaseBackoff, mailMaxBackoff))); err != nil {
+				errorLog.Println(err)
+			}
+			continue
+		}
+		if err := repo.MarkEmailSent(email.ID); err != nil {
+			errorLog.Println(err)
+			continue
+		}
+		infoLog.Printf("email sent to %s", email.To)
 	}
+}
 
-	// Create a new email message
-	email := mail.NewMSG()
-	email.SetFrom(m.From).AddTo(m.To).SetSubject(m.Subject)
-	email.SetBody(mail.TextHTML, m.Content)
+// retryBackoff returns the delay before the next attempt, doubling the base delay with every failed attempt up to max
+func retryBackoff(attempt int, base, max time.Duration) time.Duration {
+	backoff := base
+	for i := 1; i < attempt && backoff < max; i++ {
+		backoff *= 2
+	}
+	if backoff > max {
+		backoff = max
+	}
+	return backoff
+}
 
-	// Send the email
-	if err := email.Send(client); err != nil {
-		log.Println(err)
-	} else {
-		log.Println("email sent")
+// sendMsg renders the email template of the outbox mail and sends it using the configured mail transport.
+func sendMsg(email *models.EmailOutbox) error {
+	msg := models.MailData{
+		To:           email.To,
+		From:         email.From,
+		Subject:      email.Subject,
+		Content:      email.Content,
+		PlainContent: email.PlainContent,
+		Template:     email.Template,
+		Unsubscribe:  email.Unsubscribe,
+	}
+	if err := json.Unmarshal([]byte(email.Data), &msg.Data); err != nil {
+		return err
+	}
+	if err := mailer.Render(&msg); err != nil {
+		return err
 	}
+	return mailTransport.Send(&msg)
 }
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"time"
+
+	"github.com/ishanshre/Book-Review-Platform/internals/repository"
+	"github.com/ishanshre/Book-Review-Platform/internals/webhook"
+)
+
+const (
+	webhookPollInterval = 5 * time.Second  // time between two checks of the delivery queue
+	webhookBatchSize    = 10               // number of deliveries claimed per check
+	webhookLease        = time.Minute      // time a claimed delivery is hidden from other workers
+	webhookTimeout      = 10 * time.Second // time the endpoint has to respond
+	webhookBaseBackoff  = 30 * time.Second
+	webhookMaxBackoff   = 12 * time.Hour
+)
+
+// listenForWebhooks starts a goroutine that posts the pending webhook deliveries to their endpoints.
+// Failed deliveries are retried with exponential backoff until they reach their max attempts.
+func listenForWebhooks(repo repository.DatabaseRepo) {
+	client := webhook.NewClient(webhookTimeout)
+	go func() {
+		ticker := time.NewTicker(webhookPollInterval)
+		defer ticker.Stop()
+		for {
+			processWebhookDeliveries(repo, client)
+			<-ticker.C
+		}
+	}()
+}
+
+// processWebhookDeliveries claims the due deliveries and posts them one by one
+func processWebhookDeliveries(repo repository.DatabaseRepo, client *webhook.Client) {
+	deliveries, err := repo.ClaimPendingWebhookDeliveries(webhookBatchSize, webhookLease)
+	if err != nil {
+		errorLog.Println(err)
+		return
+	}
+	for _, delivery := range deliveries {
+		status, body, err := client.Deliver(delivery.Url, delivery.Secret, delivery.Event, delivery.ID, []byte(delivery.Payload))
+		if err != nil {
+			attempt := delivery.Attempts + 1
+			errorLog.Printf("webhook delivery %d of %s to %s failed (attempt %d of %d): %s", delivery.ID, delivery.Event, delivery.Url, attempt, delivery.MaxAttempts, err)
+			next := time.Now().Add(retryBackoff(attempt, webhookBaseBackoff, webhookMaxBackoff))
+			if err := repo.MarkWebhookDeliveryFailed(delivery.ID, status, body, err.Error(), next); err != nil {
+				errorLog.Println(err)
+			}
+			continue
+		}
+		if err := repo.MarkWebhookDelivered(delivery.ID, status, body); err != nil {
+			errorLog.Println(err)
+			continue
+		}
+		infoLog.Printf("webhook %s delivered to %s", delivery.Event, delivery.Url)
+	}
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"time"
+
+	"github.com/ishanshre/Book-Review-Platform/internals/repository"
+)
+
+// sessionCleanupInterval is the time between two removals of the expired sessions from the session index
+const sessionCleanupInterval = time.Hour
+
+// startSessionCleanupJob starts a goroutine that removes the sessions older than the session lifetime from the session index,
+// so that the expired sessions are no longer listed as active
+func startSessionCleanupJob(repo repository.DatabaseRepo, lifetime time.Duration) {
+	go func() {
+		ticker := time.NewTicker(sessionCleanupInterval)
+		defer ticker.Stop()
+		for {
+			if err := repo.DeleteExpiredUserSessions(time.Now().Add(-lifetime)); err != nil {
+				errorLog.Println(err)
+			}
+			<-ticker.C
+		}
+	}()
+}
@@ -0,0 +1,352 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/ishanshre/Book-Review-Platform/internals/cache"
+	"github.com/ishanshre/Book-Review-Platform/internals/events"
+	"github.com/ishanshre/Book-Review-Platform/internals/handler"
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+	"github.com/ishanshre/Book-Review-Platform/internals/pubsub"
+	"github.com/ishanshre/Book-Review-Platform/internals/repository"
+	"github.com/ishanshre/Book-Review-Platform/internals/sanitize"
+)
+
+// eventBus delivers the domain events published by the handlers to the subscribers registered in registerSubscribers
+var eventBus *events.Bus
+
+// liveHub streams the live updates to the readers of the book detail page
+var liveHub *pubsub.Hub
+
+// feedCache caches the home feeds of the users until the subscribers invalidate them
+var feedCache *cache.Cache
+
+// registerSubscribers registers the side effects of the domain events.
+// Mails are queued and the home feeds invalidated synchronously so that a failure is reported to the publisher
+// and the next page shows the change, notifications, webhooks, the similar books and the audit log are handled asynchronously.
+// Mails that must not be lost with the change, like the welcome mail, are queued by the handler in the transaction of the change instead.
+func registerSubscribers(bus *events.Bus, repo repository.DatabaseRepo) {
+	// mailer
+	events.On(bus, events.Sync, func(ctx context.Context, e events.UserWarned) error {
+		return repo.EnqueueMail(&models.MailData{
+			From:     app.AdminEmail,
+			To:       e.User.Email,
+			Subject:  "A moderator reviewed your " + e.Report.TargetType,
+			Template: "moderation_warning",
+			Data: map[string]interface{}{
+				"Username":     e.User.Username,
+				"TargetType":   e.Report.TargetType,
+				"Reason":       e.Report.Reason,
+				"Body":         e.Report.TargetBody,
+				"Note":         e.Report.ResolutionNote,
+				"SupportEmail": app.AdminEmail,
+			},
+		})
+	})
+
+	// notifications
+	events.On(bus, events.Async, func(ctx context.Context, e events.BookAuthorAdded) error {
+		return repo.InsertNotificationForAuthorFollowers(e.Author.ID, &models.Notification{
+			Type:    models.NotificationNewBook,
+			Message: fmt.Sprintf("New book by %s %s: %s", e.Author.FirstName, e.Author.LastName, e.Book.Title),
+			Link:    fmt.Sprintf("/books/%d", e.Book.Isbn),
+		})
+	})
+	events.On(bus, events.Async, func(ctx context.Context, e events.RequestBookFulfilled) error {
+		return repo.InsertNotification(&models.Notification{
+			UserID:  e.User.ID,
+			Type:    models.NotificationRequestBookFulfilled,
+			Message: fmt.Sprintf("Your requested book %s by %s is now available", e.Request.BookTitle, e.Request.Author),
+			Link:    "/books",
+		})
+	})
+	events.On(bus, events.Async, func(ctx context.Context, e events.KycApproved) error {
+		return repo.InsertNotification(&models.Notification{
+			UserID:  e.User.ID,
+			Type:    models.NotificationKycApproved,
+			Message: "Your KYC has been approved",
+			Link:    "/profile",
+		})
+	})
+	events.On(bus, events.Async, func(ctx context.Context, e events.KycRejected) error {
+		return repo.InsertNotification(&models.Notification{
+			UserID:  e.User.ID,
+			Type:    models.NotificationKycRejected,
+			Message: "Your KYC has been rejected. Please check your details and submit the documents again",
+			Link:    "/profile",
+		})
+	})
+	events.On(bus, events.Async, func(ctx context.Context, e events.UserWarned) error {
+		return repo.InsertNotification(&models.Notification{
+			UserID:  e.User.ID,
+			Type:    models.NotificationModerationWarning,
+			Message: fmt.Sprintf("A moderator warned you about your %s reported as %s", e.Report.TargetType, e.Report.Reason),
+			Link:    "/profile",
+		})
+	})
+	events.On(bus, events.Async, func(ctx context.Context, e events.ReviewCommentCreated) error {
+		return notifyReviewReply(repo, e.Comment, e.Review)
+	})
+	events.On(bus, events.Async, func(ctx context.Context, e events.UserFollowed) error {
+		return repo.InsertNotification(&models.Notification{
+			UserID:  e.Followee.ID,
+			Type:    models.NotificationNewFollower,
+			Message: fmt.Sprintf("@%s started following you", e.Follower.Username),
+			Link:    "/users/" + url.QueryEscape(e.Follower.Username),
+		})
+	})
+
+	// webhooks
+	webhook := func(event string, data interface{}) error {
+		payload, err := json.Marshal(&models.WebhookPayload{
+			Event:     event,
+			CreatedAt: time.Now().UTC(),
+			Data:      data,
+		})
+		if err != nil {
+			return err
+		}
+		return repo.EnqueueWebhookEvent(event, payload)
+	}
+	events.On(bus, events.Async, func(ctx context.Context, e events.BookCreated) error {
+		return webhook(models.WebhookBookCreated, webhookBookData(e.Book))
+	})
+	events.On(bus, events.Async, func(ctx context.Context, e events.BookUpdated) error {
+		return webhook(models.WebhookBookUpdated, webhookBookData(e.Book))
+	})
+	events.On(bus, events.Async, func(ctx context.Context, e events.ReviewCreated) error {
+		return webhook(models.WebhookReviewCreated, webhookReviewData(e.Review))
+	})
+	events.On(bus, events.Async, func(ctx context.Context, e events.ReviewDeactivated) error {
+		return webhook(models.WebhookReviewDeactivated, webhookReviewData(e.Review))
+	})
+	events.On(bus, events.Async, func(ctx context.Context, e events.RequestBookFulfilled) error {
+		request := *e.Request
+		request.IsAdded = true
+		return webhook(models.WebhookRequestBookFulfilled, &request)
+	})
+	events.On(bus, events.Async, func(ctx context.Context, e events.UserRegistered) error {
+		return webhook(models.WebhookUserRegistered, webhookUserData(e.User))
+	})
+
+	// live updates of the book detail page
+	events.On(bus, events.Async, func(ctx context.Context, e events.ReviewCreated) error {
+		return publishLiveReview(repo, "review.created", e.Review)
+	})
+	events.On(bus, events.Async, func(ctx context.Context, e events.ReviewUpdated) error {
+		return publishLiveReview(repo, "review.updated", e.Review)
+	})
+	events.On(bus, events.Async, func(ctx context.Context, e events.ReviewDeleted) error {
+		return publishLiveReview(repo, "review.deleted", e.Review)
+	})
+	events.On(bus, events.Async, func(ctx context.Context, e events.ReviewDeactivated) error {
+		return publishLiveReview(repo, "review.deleted", e.Review)
+	})
+
+	// content similarity of the books
+	events.On(bus, events.Async, func(ctx context.Context, e events.BookCreated) error {
+		return refreshBookContentSimilarities(repo, e.Book.ID)
+	})
+	events.On(bus, events.Async, func(ctx context.Context, e events.BookUpdated) error {
+		return refreshBookContentSimilarities(repo, e.Book.ID)
+	})
+	events.On(bus, events.Async, func(ctx context.Context, e events.BookAuthorAdded) error {
+		return refreshBookContentSimilarities(repo, e.Book.ID)
+	})
+	events.On(bus, events.Async, func(ctx context.Context, e events.BookRelationsChanged) error {
+		return refreshBookContentSimilarities(repo, e.BookIDs...)
+	})
+
+	// activity stream
+	events.On(bus, events.Async, func(ctx context.Context, e events.ReviewCreated) error {
+		return repo.InsertActivity(&models.Activity{
+			UserID:    e.Review.UserID,
+			Type:      models.ActivityReviewPosted,
+			BookID:    e.Review.BookID,
+			ReviewID:  e.Review.ID,
+			CreatedAt: e.Review.CreatedAt,
+		})
+	})
+	events.On(bus, events.Async, func(ctx context.Context, e events.BookFinished) error {
+		return repo.InsertActivity(&models.Activity{
+			UserID:    e.Read.UserID,
+			Type:      models.ActivityBookFinished,
+			BookID:    e.Read.BookID,
+			CreatedAt: e.Read.FinishedAt,
+		})
+	})
+	events.On(bus, events.Async, func(ctx context.Context, e events.ShelfCreated) error {
+		return repo.InsertActivity(&models.Activity{
+			UserID:    e.Shelf.UserID,
+			Type:      models.ActivityShelfCreated,
+			ShelfID:   e.Shelf.ID,
+			CreatedAt: e.Shelf.CreatedAt,
+		})
+	})
+	events.On(bus, events.Async, func(ctx context.Context, e events.AuthorFollowed) error {
+		return repo.InsertActivity(&models.Activity{
+			UserID:    e.UserID,
+			Type:      models.ActivityAuthorFollowed,
+			AuthorID:  e.AuthorID,
+			CreatedAt: time.Now(),
+		})
+	})
+
+	// home feeds
+	events.On(bus, events.Sync, func(ctx context.Context, e events.UserLibraryChanged) error {
+		return handler.InvalidateHomeFeeds(feedCache, e.UserID)
+	})
+	events.On(bus, events.Sync, func(ctx context.Context, e events.ReviewCreated) error {
+		return invalidateReviewHomeFeeds(repo, e.Review)
+	})
+	events.On(bus, events.Sync, func(ctx context.Context, e events.ReviewUpdated) error {
+		return invalidateReviewHomeFeeds(repo, e.Review)
+	})
+	events.On(bus, events.Sync, func(ctx context.Context, e events.ReviewDeleted) error {
+		return invalidateReviewHomeFeeds(repo, e.Review)
+	})
+	events.On(bus, events.Sync, func(ctx context.Context, e events.ReviewDeactivated) error {
+		return invalidateReviewHomeFeeds(repo, e.Review)
+	})
+	for _, name := range []string{events.BookCreatedEvent, events.BookUpdatedEvent, events.BookAuthorAddedEvent, events.BookRelationsChangedEvent} {
+		bus.Subscribe(name, events.Sync, func(ctx context.Context, e events.Event) error {
+			return handler.InvalidateAllHomeFeeds(feedCache)
+		})
+	}
+
+	// audit
+	bus.Subscribe(events.All, events.Async, func(ctx context.Context, e events.Event) error {
+		infoLog.Printf("event %s published", e.Name())
+		return nil
+	})
+}
+
+// publishLiveReview publishes the review event followed by the new rating of the book to the readers of the book.
+// The stream is public, so an inactive review is only published as removed, without its body or author.
+func publishLiveReview(repo repository.DatabaseRepo, event string, review *models.Review) error {
+	book, err := repo.GetBookByID(review.BookID)
+	if err != nil {
+		return err
+	}
+	topic := handler.BookEventsTopic(book.Isbn)
+	if !review.IsActive {
+		event = "review.deleted"
+	}
+	data := map[string]interface{}{
+		"id": review.ID,
+	}
+	if event != "review.deleted" {
+		user, err := repo.GetGlobalUserByIDAny(review.UserID)
+		if err != nil {
+			return err
+		}
+		revisions, err := repo.GetReviewRevisions(review.ID)
+		if err != nil {
+			return err
+		}
+		data["rating"] = review.Rating
+		data["username"] = user.Username
+		data["body_html"] = sanitize.Markdown(review.Body)
+		data["edited"] = len(revisions) > 1
+		data["created_at"] = review.CreatedAt
+		data["updated_at"] = review.UpdatedAt
+	}
+	if err := liveHub.Publish(topic, event, data); err != nil {
+		return err
+	}
+	rating, err := repo.GetBookRating(book.ID)
+	if err != nil {
+		return err
+	}
+	return liveHub.Publish(topic, "rating.updated", rating)
+}
+
+// invalidateReviewHomeFeeds invalidates the home feeds of the author of the review,
+// whose favourite genres may change, and of the users having the book on their shelves
+func invalidateReviewHomeFeeds(repo repository.DatabaseRepo, review *models.Review) error {
+	user_ids, err := repo.ShelfOwnerIDs(review.BookID)
+	if err != nil {
+		return err
+	}
+	return handler.InvalidateHomeFeeds(feedCache, append(user_ids, review.UserID)...)
+}
+
+// notifyReviewReply notifies the author of the review and the author of the parent comment about the new comment.
+// Nobody is notified about their own comment.
+func notifyReviewReply(repo repository.DatabaseRepo, comment *models.ReviewComment, review *models.Review) error {
+	book, err := repo.GetBookByID(review.BookID)
+	if err != nil {
+		return err
+	}
+	link := fmt.Sprintf("/books/%d#review-%d", book.Isbn, review.ID)
+	if review.UserID != comment.UserID {
+		if err := repo.InsertNotification(&models.Notification{
+			UserID:  review.UserID,
+			Type:    models.NotificationReviewReply,
+			Message: fmt.Sprintf("@%s commented on your review of %s", comment.Username, book.Title),
+			Link:    link,
+		}); err != nil {
+			return err
+		}
+	}
+	if comment.ParentID == 0 {
+		return nil
+	}
+	parent, err := repo.GetReviewCommentByID(comment.ParentID)
+	if err != nil {
+		return err
+	}
+	if parent.UserID == comment.UserID || parent.UserID == review.UserID {
+		return nil
+	}
+	return repo.InsertNotification(&models.Notification{
+		UserID:  parent.UserID,
+		Type:    models.NotificationReviewReply,
+		Message: fmt.Sprintf("@%s replied to your comment on %s", comment.Username, book.Title),
+		Link:    link,
+	})
+}
+
+// webhookBookData returns the book fields sent in the book webhook events
+func webhookBookData(b *models.Book) map[string]interface{} {
+	return map[string]interface{}{
+		"id":             b.ID,
+		"isbn":           b.Isbn,
+		"title":          b.Title,
+		"description":    b.Description,
+		"cover":          b.Cover,
+		"published_date": b.PublishedDate.Format(time.DateOnly),
+		"paperback":      b.Paperback,
+		"is_active":      b.IsActive,
+		"publisher_id":   b.PublisherID,
+		"added_at":       b.AddedAt,
+		"updated_at":     b.UpdatedAt,
+	}
+}
+
+// webhookReviewData returns the review fields sent in the review webhook events
+func webhookReviewData(r *models.Review) map[string]interface{} {
+	return map[string]interface{}{
+		"id":         r.ID,
+		"book_id":    r.BookID,
+		"user_id":    r.UserID,
+		"rating":     r.Rating,
+		"body":       r.Body,
+		"is_active":  r.IsActive,
+		"created_at": r.CreatedAt,
+		"updated_at": r.UpdatedAt,
+	}
+}
+
+// webhookUserData returns the user fields sent in the user webhook events.
+// Email and other personal data are left out on purpose.
+func webhookUserData(u *models.User) map[string]interface{} {
+	return map[string]interface{}{
+		"id":       u.ID,
+		"username": u.Username,
+	}
+}
@@ -17,6 +17,7 @@ require (
 	github.com/go-chi/cors v1.2.1
 	github.com/go-faker/faker/v4 v4.1.1
 	github.com/gomodule/redigo v1.8.9
+	github.com/toorop/go-dkim v0.0.0-20201103131630-e1cd1a0a5208
 	github.com/xhit/go-simple-mail/v2 v2.13.0
 	golang.org/x/crypto v0.9.0
 )
@@ -24,6 +25,6 @@ require (
 require (
 	github.com/go-test/deep v1.1.0 // indirect
 	github.com/stretchr/testify v1.8.4 // indirect
-	github.com/toorop/go-dkim v0.0.0-20201103131630-e1cd1a0a5208 // indirect
+	golang.org/x/sys v0.8.0 // indirect
 	golang.org/x/text v0.11.0 // indirect
 )
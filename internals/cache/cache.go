@@ -0,0 +1,154 @@
+// Package cache stores JSON encoded values with an expiry.
+// With a redis pool the values are shared between the instances, otherwise they are kept in memory.
+package cache
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+type item struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// Cache stores the values under the keys starting with its prefix
+type Cache struct {
+	pool   *redis.Pool
+	prefix string
+
+	mu    sync.Mutex
+	items map[string]item
+	gens  map[string]int64
+	sets  int
+}
+
+// sweepEvery is the number of values set in memory between two removals of the expired values,
+// which are otherwise only removed when they are read
+const sweepEvery = 1000
+
+// New returns a cache using the redis keys starting with prefix, or an in memory cache when pool is nil
+func New(pool *redis.Pool, prefix string) *Cache {
+	return &Cache{
+		pool:   pool,
+		prefix: prefix,
+		items:  make(map[string]item),
+		gens:   make(map[string]int64),
+	}
+}
+
+// Get decodes the value of the key into v. It returns false when the key is missing or expired.
+func (c *Cache) Get(key string, v any) (bool, error) {
+	var raw []byte
+	if c.pool == nil {
+		c.mu.Lock()
+		it, ok := c.items[key]
+		if ok && time.Now().After(it.expiresAt) {
+			delete(c.items, key)
+			ok = false
+		}
+		c.mu.Unlock()
+		if !ok {
+			return false, nil
+		}
+		raw = it.value
+	} else {
+		conn := c.pool.Get()
+		defer conn.Close()
+		value, err := redis.Bytes(conn.Do("GET", c.prefix+key))
+		if err == redis.ErrNil {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		raw = value
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Set stores the value of the key for ttl
+func (c *Cache) Set(key string, v any, ttl time.Duration) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if c.pool == nil {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		now := time.Now()
+		if c.sets++; c.sets%sweepEvery == 0 {
+			for k, it := range c.items {
+				if now.After(it.expiresAt) {
+					delete(c.items, k)
+				}
+			}
+		}
+		c.items[key] = item{value: raw, expiresAt: now.Add(ttl)}
+		return nil
+	}
+	conn := c.pool.Get()
+	defer conn.Close()
+	_, err = conn.Do("SET", c.prefix+key, raw, "PX", ttl.Milliseconds())
+	return err
+}
+
+// Delete removes the keys
+func (c *Cache) Delete(keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	if c.pool == nil {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		for _, key := range keys {
+			delete(c.items, key)
+		}
+		return nil
+	}
+	conn := c.pool.Get()
+	defer conn.Close()
+	args := make([]interface{}, len(keys))
+	for i, key := range keys {
+		args[i] = c.prefix + key
+	}
+	_, err := conn.Do("DEL", args...)
+	return err
+}
+
+// Generation returns the generation of the name. Putting it in the keys of a group of values
+// invalidates all of them at once when Bump is called, without having to find and delete every key.
+func (c *Cache) Generation(name string) (int64, error) {
+	if c.pool == nil {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return c.gens[name], nil
+	}
+	conn := c.pool.Get()
+	defer conn.Close()
+	gen, err := redis.Int64(conn.Do("GET", c.prefix+"gen:"+name))
+	if err == redis.ErrNil {
+		return 0, nil
+	}
+	return gen, err
+}
+
+// Bump increments the generation of the name
+func (c *Cache) Bump(name string) error {
+	if c.pool == nil {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.gens[name]++
+		return nil
+	}
+	conn := c.pool.Get()
+	defer conn.Close()
+	_, err := conn.Do("INCR", c.prefix+"gen:"+name)
+	return err
+}
@@ -1,11 +1,11 @@
 package config
 
 import (
+	"html/template"
 	"log"
-	"text/template"
+	"net"
 
 	"github.com/alexedwards/scs/v2"
-	"github.com/ishanshre/Book-Review-Platform/internals/models"
 )
 
 // Global configurations for the application.
@@ -18,6 +18,13 @@ type AppConfig struct {
 	InfoLog       *log.Logger
 	ErrorLog      *log.Logger
 	Session       *scs.SessionManager
-	MailChan      chan models.MailData
 	AdminEmail    string
+	// BaseURL is the public address of the application used for absolute links in emails, e.g. http://localhost:8000
+	BaseURL string
+	// ReportHideThreshold is the number of open reports that hides a review or comment until it is moderated.
+	// Zero disables the auto hide.
+	ReportHideThreshold int
+	// TrustedProxies are the networks of the reverse proxies whose X-Forwarded-For header is trusted.
+	// The header is ignored when it is empty.
+	TrustedProxies []*net.IPNet
 }
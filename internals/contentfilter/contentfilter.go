@@ -0,0 +1,98 @@
+// Package contentfilter checks the text submitted by the users for spam and profanity.
+// A Pipeline runs every Filter on the submission and the strictest verdict decides
+// whether the submission is allowed, held for moderation or rejected.
+package contentfilter
+
+import "strings"
+
+// Verdict is the decision of a filter about a submission
+type Verdict string
+
+const (
+	Allow  Verdict = "allow"
+	Hold   Verdict = "hold"
+	Reject Verdict = "reject"
+)
+
+// Verdicts lists the verdicts from the least to the most strict
+var Verdicts = []Verdict{Allow, Hold, Reject}
+
+// severity returns the position of the verdict in Verdicts
+func (v Verdict) severity() int {
+	for i, verdict := range Verdicts {
+		if verdict == v {
+			return i
+		}
+	}
+	return 0
+}
+
+// Submission is the text checked by the filters
+type Submission struct {
+	Kind string
+	Text string
+}
+
+// Filter checks a submission and returns its verdict with the reason of a hold or a reject
+type Filter interface {
+	Name() string
+	Check(s *Submission) (Verdict, string)
+}
+
+// Result is the verdict of one filter that did not allow the submission
+type Result struct {
+	Filter  string  `json:"filter"`
+	Verdict Verdict `json:"verdict"`
+	Reason  string  `json:"reason"`
+}
+
+// Decision is the verdict of the pipeline with the results that led to it
+type Decision struct {
+	Verdict Verdict  `json:"verdict"`
+	Results []Result `json:"results"`
+}
+
+// Reasons joins the reasons of the results in a single line
+func (d *Decision) Reasons() string {
+	reasons := make([]string, 0, len(d.Results))
+	for _, result := range d.Results {
+		reasons = append(reasons, result.Filter+": "+result.Reason)
+	}
+	return strings.Join(reasons, "; ")
+}
+
+// Pipeline runs the filters in the order they were added
+type Pipeline struct {
+	filters []Filter
+}
+
+// New returns a pipeline of the filters
+func New(filters ...Filter) *Pipeline {
+	return &Pipeline{filters: filters}
+}
+
+// Use adds the filters at the end of the pipeline
+func (p *Pipeline) Use(filters ...Filter) {
+	p.filters = append(p.filters, filters...)
+}
+
+// Check runs every filter on the submission.
+// The strictest verdict of the filters is the verdict of the decision.
+func (p *Pipeline) Check(s *Submission) *Decision {
+	decision := &Decision{Verdict: Allow, Results: []Result{}}
+	for _, filter := range p.filters {
+		verdict, reason := filter.Check(s)
+		if verdict == Allow {
+			continue
+		}
+		decision.Results = append(decision.Results, Result{
+			Filter:  filter.Name(),
+			Verdict: verdict,
+			Reason:  reason,
+		})
+		if verdict.severity() > decision.Verdict.severity() {
+			decision.Verdict = verdict
+		}
+	}
+	return decision
+}
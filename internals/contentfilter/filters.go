@@ -0,0 +1,187 @@
+package contentfilter
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Word is a word or phrase of the word list with the verdict of a submission containing it
+type Word struct {
+	Word    string
+	Verdict Verdict
+}
+
+// WordList holds or rejects the submissions containing a word or phrase of the list.
+// Words are matched whole and case insensitive, so "class" does not match "ass".
+type WordList struct {
+	Words []Word
+}
+
+func (f *WordList) Name() string { return "word_list" }
+
+func (f *WordList) Check(s *Submission) (Verdict, string) {
+	text := " " + strings.Join(words(s.Text), " ") + " "
+	verdict, matched := Allow, []string{}
+	for _, word := range f.Words {
+		phrase := strings.Join(words(word.Word), " ")
+		if phrase == "" || !strings.Contains(text, " "+phrase+" ") {
+			continue
+		}
+		matched = append(matched, phrase)
+		if word.Verdict.severity() > verdict.severity() {
+			verdict = word.Verdict
+		}
+	}
+	if verdict == Allow {
+		return Allow, ""
+	}
+	return verdict, "contains " + strings.Join(matched, ", ")
+}
+
+// Links holds the submissions with at least Hold links and rejects the ones with at least Reject links.
+// A zero threshold disables its verdict.
+type Links struct {
+	Hold   int
+	Reject int
+}
+
+func (f *Links) Name() string { return "links" }
+
+func (f *Links) Check(s *Submission) (Verdict, string) {
+	count := CountLinks(s.Text)
+	reason := fmt.Sprintf("contains %d links", count)
+	if f.Reject > 0 && count >= f.Reject {
+		return Reject, reason
+	}
+	if f.Hold > 0 && count >= f.Hold {
+		return Hold, reason
+	}
+	return Allow, ""
+}
+
+// CountLinks returns the number of http, https and www links in the text
+func CountLinks(text string) int {
+	count := 0
+	for _, field := range strings.Fields(strings.ToLower(text)) {
+		if strings.Contains(field, "http://") || strings.Contains(field, "https://") || strings.Contains(field, "www.") {
+			count++
+		}
+	}
+	return count
+}
+
+// RepeatedChars holds the submissions repeating a character more than Max times in a row, like "soooooo good!!!!!!!"
+type RepeatedChars struct {
+	Max int
+}
+
+func (f *RepeatedChars) Name() string { return "repeated_chars" }
+
+func (f *RepeatedChars) Check(s *Submission) (Verdict, string) {
+	run, longest := 0, 0
+	var last rune
+	for _, c := range s.Text {
+		if c == last && !unicode.IsSpace(c) {
+			run++
+		} else {
+			run = 1
+		}
+		last = c
+		if run > longest {
+			longest = run
+		}
+	}
+	if longest > f.Max {
+		return Hold, fmt.Sprintf("repeats a character %d times", longest)
+	}
+	return Allow, ""
+}
+
+// Caps holds the submissions with at least MinLetters letters of which at least Ratio are upper case
+type Caps struct {
+	Ratio      float64
+	MinLetters int
+}
+
+func (f *Caps) Name() string { return "caps" }
+
+func (f *Caps) Check(s *Submission) (Verdict, string) {
+	letters, upper := 0, 0
+	for _, c := range s.Text {
+		if !unicode.IsLetter(c) {
+			continue
+		}
+		letters++
+		if unicode.IsUpper(c) {
+			upper++
+		}
+	}
+	if letters < f.MinLetters || letters == 0 {
+		return Allow, ""
+	}
+	ratio := float64(upper) / float64(letters)
+	if ratio >= f.Ratio {
+		return Hold, fmt.Sprintf("%.0f%% of the letters are upper case", ratio*100)
+	}
+	return Allow, ""
+}
+
+// NearDuplicate gives the Verdict to the submissions at least Threshold similar to one of the Recent submissions.
+// The similarity is the jaccard index of the word pairs of the texts.
+type NearDuplicate struct {
+	Recent    []string
+	Threshold float64
+	Verdict   Verdict
+}
+
+func (f *NearDuplicate) Name() string { return "near_duplicate" }
+
+func (f *NearDuplicate) Check(s *Submission) (Verdict, string) {
+	text := shingles(s.Text)
+	if len(text) == 0 {
+		return Allow, ""
+	}
+	for _, recent := range f.Recent {
+		similarity := jaccard(text, shingles(recent))
+		if similarity >= f.Threshold {
+			return f.Verdict, fmt.Sprintf("%.0f%% similar to a recent submission", similarity*100)
+		}
+	}
+	return Allow, ""
+}
+
+// jaccard returns the jaccard index of the two sets
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	common := 0
+	for item := range a {
+		if b[item] {
+			common++
+		}
+	}
+	return float64(common) / float64(len(a)+len(b)-common)
+}
+
+// shingles returns the set of the pairs of consecutive words of the text.
+// A text of a single word is its own shingle.
+func shingles(text string) map[string]bool {
+	tokens := words(text)
+	set := make(map[string]bool)
+	if len(tokens) == 1 {
+		set[tokens[0]] = true
+	}
+	for i := 1; i < len(tokens); i++ {
+		set[tokens[i-1]+" "+tokens[i]] = true
+	}
+	return set
+}
+
+// words splits the lower cased text into its words of letters and digits
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(c rune) bool {
+		return !unicode.IsLetter(c) && !unicode.IsDigit(c)
+	})
+}
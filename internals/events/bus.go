@@ -0,0 +1,111 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Event is implemented by every domain event.
+// Name must not depend on the event fields since it is also called on zero values.
+type Event interface {
+	Name() string
+}
+
+// All is the name used to subscribe to every event, e.g. for auditing
+const All = "*"
+
+// Handler handles a published event
+type Handler func(ctx context.Context, e Event) error
+
+// Mode tells how the event is delivered to a subscriber
+type Mode int
+
+const (
+	// Sync subscribers run one after another before Publish returns and their errors are returned by Publish
+	Sync Mode = iota
+	// Async subscribers run in their own goroutine and their errors are reported to the error handler of the bus
+	Async
+)
+
+type subscriber struct {
+	mode    Mode
+	handler Handler
+}
+
+// Bus is an in-process publish/subscribe event bus.
+// Subscribers are expected to be registered at startup before events are published.
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers map[string][]subscriber
+	onError     func(e Event, err error)
+	wg          sync.WaitGroup
+}
+
+// New returns an event bus reporting the errors of async subscribers to onError
+func New(onError func(e Event, err error)) *Bus {
+	if onError == nil {
+		onError = func(Event, error) {}
+	}
+	return &Bus{
+		subscribers: make(map[string][]subscriber),
+		onError:     onError,
+	}
+}
+
+// Subscribe registers the handler for the event name, or for every event when the name is All
+func (b *Bus) Subscribe(name string, mode Mode, h Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers[name] = append(b.subscribers[name], subscriber{mode: mode, handler: h})
+}
+
+// On registers a handler receiving the typed event
+func On[T Event](b *Bus, mode Mode, h func(ctx context.Context, e T) error) {
+	var zero T
+	b.Subscribe(zero.Name(), mode, func(ctx context.Context, e Event) error {
+		return h(ctx, e.(T))
+	})
+}
+
+// Publish delivers the event to its subscribers followed by the subscribers of every event.
+// It returns the errors of the sync subscribers. Async subscribers receive a context which is not cancelled with ctx.
+func (b *Bus) Publish(ctx context.Context, e Event) error {
+	b.mu.RLock()
+	subscribers := append(append([]subscriber{}, b.subscribers[e.Name()]...), b.subscribers[All]...)
+	b.mu.RUnlock()
+
+	var errs []error
+	for _, s := range subscribers {
+		if s.mode == Async {
+			b.wg.Add(1)
+			go func(h Handler) {
+				defer b.wg.Done()
+				if err := call(context.Background(), h, e); err != nil {
+					b.onError(e, err)
+				}
+			}(s.handler)
+			continue
+		}
+		if err := call(ctx, s.handler, e); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Wait blocks until the running async subscribers are done
+func (b *Bus) Wait() {
+	b.wg.Wait()
+}
+
+// call runs the handler and converts a panic into an error
+func call(ctx context.Context, h Handler, e Event) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("subscriber of %s panicked: %v", e.Name(), p)
+		}
+	}()
+	return h(ctx, e)
+}
@@ -0,0 +1,166 @@
+package events
+
+import "github.com/ishanshre/Book-Review-Platform/internals/models"
+
+// Event names
+const (
+	BookCreatedEvent          = "book.created"
+	BookUpdatedEvent          = "book.updated"
+	BookAuthorAddedEvent      = "book_author.added"
+	BookRelationsChangedEvent = "book.relations_changed"
+	ReviewCreatedEvent        = "review.created"
+	ReviewUpdatedEvent        = "review.updated"
+	ReviewDeletedEvent        = "review.deleted"
+	ReviewDeactivatedEvent    = "review.deactivated"
+	ReviewCommentCreatedEvent = "review_comment.created"
+	RequestBookFulfilledEvent = "request_book.fulfilled"
+	UserRegisteredEvent       = "user.registered"
+	KycApprovedEvent          = "kyc.approved"
+	KycRejectedEvent          = "kyc.rejected"
+	UserWarnedEvent           = "user.warned"
+	UserLibraryChangedEvent   = "user.library_changed"
+	UserFollowedEvent         = "user.followed"
+	AuthorFollowedEvent       = "author.followed"
+	BookFinishedEvent         = "book.finished"
+	ShelfCreatedEvent         = "shelf.created"
+)
+
+// BookCreated is published when a new book is added
+type BookCreated struct {
+	Book *models.Book
+}
+
+func (BookCreated) Name() string { return BookCreatedEvent }
+
+// BookUpdated is published when a book is updated
+type BookUpdated struct {
+	Book *models.Book
+}
+
+func (BookUpdated) Name() string { return BookUpdatedEvent }
+
+// BookAuthorAdded is published when a book is linked to its author
+type BookAuthorAdded struct {
+	Book   *models.Book
+	Author *models.Author
+}
+
+func (BookAuthorAdded) Name() string { return BookAuthorAddedEvent }
+
+// BookRelationsChanged is published when the authors, genres or languages of books are added, changed or removed
+type BookRelationsChanged struct {
+	BookIDs []int
+}
+
+func (BookRelationsChanged) Name() string { return BookRelationsChangedEvent }
+
+// ReviewCreated is published when a review is added
+type ReviewCreated struct {
+	Review *models.Review
+}
+
+func (ReviewCreated) Name() string { return ReviewCreatedEvent }
+
+// ReviewUpdated is published when a review is edited
+type ReviewUpdated struct {
+	Review *models.Review
+}
+
+func (ReviewUpdated) Name() string { return ReviewUpdatedEvent }
+
+// ReviewDeleted is published after a review is deleted
+type ReviewDeleted struct {
+	Review *models.Review
+}
+
+func (ReviewDeleted) Name() string { return ReviewDeletedEvent }
+
+// ReviewDeactivated is published when an active review is deactivated
+type ReviewDeactivated struct {
+	Review *models.Review
+}
+
+func (ReviewDeactivated) Name() string { return ReviewDeactivatedEvent }
+
+// ReviewCommentCreated is published when a comment or a reply is added to a review
+type ReviewCommentCreated struct {
+	Comment *models.ReviewComment
+	Review  *models.Review
+}
+
+func (ReviewCommentCreated) Name() string { return ReviewCommentCreatedEvent }
+
+// RequestBookFulfilled is published when a requested book is marked as added
+type RequestBookFulfilled struct {
+	Request *models.RequestedBook
+	User    *models.User
+}
+
+func (RequestBookFulfilled) Name() string { return RequestBookFulfilledEvent }
+
+// UserRegistered is published when a new user is created
+type UserRegistered struct {
+	User *models.User
+}
+
+func (UserRegistered) Name() string { return UserRegisteredEvent }
+
+// KycApproved is published when the admin validates the KYC of a user
+type KycApproved struct {
+	User *models.User
+}
+
+func (KycApproved) Name() string { return KycApprovedEvent }
+
+// KycRejected is published when the admin explicitly rejects the submitted documents of a user
+type KycRejected struct {
+	User *models.User
+}
+
+func (KycRejected) Name() string { return KycRejectedEvent }
+
+// UserWarned is published when a moderator warns the author of a reported review or comment
+type UserWarned struct {
+	User   *models.User
+	Report *models.Report
+}
+
+func (UserWarned) Name() string { return UserWarnedEvent }
+
+// UserLibraryChanged is published when a user follows or unfollows an author,
+// changes their read list or puts books on or off their shelves
+type UserLibraryChanged struct {
+	UserID int
+}
+
+func (UserLibraryChanged) Name() string { return UserLibraryChangedEvent }
+
+// UserFollowed is published when a user starts following another user
+type UserFollowed struct {
+	Follower *models.User
+	Followee *models.User
+}
+
+func (UserFollowed) Name() string { return UserFollowedEvent }
+
+// AuthorFollowed is published when a user starts following an author
+type AuthorFollowed struct {
+	UserID   int
+	AuthorID int
+}
+
+func (AuthorFollowed) Name() string { return AuthorFollowedEvent }
+
+// BookFinished is published when a user marks a book of their read list as finished, re-reads included
+type BookFinished struct {
+	Read *models.ReadListRead
+}
+
+func (BookFinished) Name() string { return BookFinishedEvent }
+
+// ShelfCreated is published when a user creates a shelf
+type ShelfCreated struct {
+	Shelf *models.Shelf
+}
+
+func (ShelfCreated) Name() string { return ShelfCreatedEvent }
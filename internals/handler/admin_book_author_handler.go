@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/ishanshre/Book-Review-Platform/internals/events"
 	"github.com/ishanshre/Book-Review-Platform/internals/forms"
 	"github.com/ishanshre/Book-Review-Platform/internals/helpers"
 	"github.com/ishanshre/Book-Review-Platform/internals/models"
@@ -83,6 +84,7 @@ func (m *Repository) PostAdminDeleteBookAuthor(w http.ResponseWriter, r *http.Re
 		helpers.ServerError(w, err)
 		return
 	}
+	m.publish(r.Context(), events.BookRelationsChanged{BookIDs: []int{book_id}})
 
 	// Add success message
 	m.App.Session.Put(r.Context(), "flash", "Book Deleted")
@@ -237,6 +239,7 @@ func (m *Repository) PostAdminUpdateBookAuthor(w http.ResponseWriter, r *http.Re
 		helpers.ServerError(w, err)
 		return
 	}
+	m.publish(r.Context(), events.BookRelationsChanged{BookIDs: []int{book_id, bookAuthor.BookID}})
 
 	// Add success message
 	m.App.Session.Put(r.Context(), "flash", "Book Updated")
@@ -344,6 +347,20 @@ func (m *Repository) PostAdminInsertBookAuthor(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	// let the subscribers know about the new book of the author
+	book, err := m.DB.GetBookByID(bookAuthor.BookID)
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	author, err := m.DB.GetAuthorFullNameByID(bookAuthor.AuthorID)
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	author.ID = bookAuthor.AuthorID
+	m.publish(r.Context(), events.BookAuthorAdded{Book: book, Author: author})
+
 	// Add success message
 	m.App.Session.Put(r.Context(), "flash", "Book Added")
 
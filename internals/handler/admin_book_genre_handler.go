@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/ishanshre/Book-Review-Platform/internals/events"
 	"github.com/ishanshre/Book-Review-Platform/internals/forms"
 	"github.com/ishanshre/Book-Review-Platform/internals/helpers"
 	"github.com/ishanshre/Book-Review-Platform/internals/models"
@@ -60,6 +61,7 @@ func (m *Repository) PostAdminDeleteBookGenre(w http.ResponseWriter, r *http.Req
 		helpers.ServerError(w, err)
 		return
 	}
+	m.publish(r.Context(), events.BookRelationsChanged{BookIDs: []int{book_id}})
 
 	// Add success message
 	m.App.Session.Put(r.Context(), "flash", "Book Genre Relationship Deleted")
@@ -206,6 +208,7 @@ func (m *Repository) PostAdminUpdateBookGenre(w http.ResponseWriter, r *http.Req
 		helpers.ServerError(w, err)
 		return
 	}
+	m.publish(r.Context(), events.BookRelationsChanged{BookIDs: []int{book_id, bookGenre.BookID}})
 
 	// Add success message
 	m.App.Session.Put(r.Context(), "flash", "Book Genre Relation Updated")
@@ -285,6 +288,7 @@ func (m *Repository) PostAdminInsertBookGenre(w http.ResponseWriter, r *http.Req
 		helpers.ServerError(w, err)
 		return
 	}
+	m.publish(r.Context(), events.BookRelationsChanged{BookIDs: []int{bookGenre.BookID}})
 
 	// Add success message
 	m.App.Session.Put(r.Context(), "flash", "Book Genre Relationship Added")
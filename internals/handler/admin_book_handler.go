@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/ishanshre/Book-Review-Platform/internals/events"
 	"github.com/ishanshre/Book-Review-Platform/internals/forms"
 	"github.com/ishanshre/Book-Review-Platform/internals/helpers"
 	"github.com/ishanshre/Book-Review-Platform/internals/models"
@@ -230,6 +231,7 @@ func (m *Repository) PostAdminInsertBook(w http.ResponseWriter, r *http.Request)
 		helpers.ServerError(w, err)
 		return
 	}
+	m.publish(r.Context(), events.BookCreated{Book: &book})
 
 	// Add success message
 	m.App.Session.Put(r.Context(), "flash", "Book Added")
@@ -343,6 +345,7 @@ func (m *Repository) PostAdminUpdateBook(w http.ResponseWriter, r *http.Request)
 		helpers.ServerError(w, err)
 		return
 	}
+	m.publish(r.Context(), events.BookUpdated{Book: &updated_book})
 
 	// Add success message
 	m.App.Session.Put(r.Context(), "flash", "Book Added")
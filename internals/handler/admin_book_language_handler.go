@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/ishanshre/Book-Review-Platform/internals/events"
 	"github.com/ishanshre/Book-Review-Platform/internals/forms"
 	"github.com/ishanshre/Book-Review-Platform/internals/helpers"
 	"github.com/ishanshre/Book-Review-Platform/internals/models"
@@ -88,6 +89,7 @@ func (m *Repository) PostAdminDeleteBookLanguage(w http.ResponseWriter, r *http.
 		helpers.ServerError(w, err)
 		return
 	}
+	m.publish(r.Context(), events.BookRelationsChanged{BookIDs: []int{book_id}})
 
 	// Add success message
 	m.App.Session.Put(r.Context(), "flash", "Book Language Deleted")
@@ -280,6 +282,7 @@ func (m *Repository) PostAdminUpdateBookLanguage(w http.ResponseWriter, r *http.
 		helpers.ServerError(w, err)
 		return
 	}
+	m.publish(r.Context(), events.BookRelationsChanged{BookIDs: []int{book_id, bookLanguage.BookID}})
 
 	// Add success message
 	m.App.Session.Put(r.Context(), "flash", "Book Language Updated")
@@ -394,6 +397,7 @@ func (m *Repository) PostAdminInsertBookLanguage(w http.ResponseWriter, r *http.
 		helpers.ServerError(w, err)
 		return
 	}
+	m.publish(r.Context(), events.BookRelationsChanged{BookIDs: []int{bookLanguage.BookID}})
 
 	// Add success message
 	m.App.Session.Put(r.Context(), "flash", "Book Language Relationship Added")
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
 
@@ -54,6 +55,25 @@ func (m *Repository) PostAdminDeleteContact(w http.ResponseWriter, r *http.Reque
 	http.Redirect(w, r, "/admin/contacts", http.StatusSeeOther)
 }
 
+// PostAdminApproveContact approves the contact message held by the content filter
+func (m *Repository) PostAdminApproveContact(w http.ResponseWriter, r *http.Request) {
+	contact_id, err := strconv.Atoi(chi.URLParam(r, "contact_id"))
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	if _, err := m.DB.GetContactByID(contact_id); err != nil {
+		helpers.PageNotFound(w, r, err)
+		return
+	}
+	if err := m.DB.SetContactActive(contact_id, true); err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	m.App.Session.Put(r.Context(), "flash", "Contact message approved")
+	http.Redirect(w, r, fmt.Sprintf("/admin/contacts/detail/%d", contact_id), http.StatusSeeOther)
+}
+
 // AdminGetContactByID handes the detail logic for Contact table.
 // It takes HTTP response writer and request as parameters.
 func (m *Repository) AdminGetContactByID(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/ishanshre/Book-Review-Platform/internals/contentfilter"
+	"github.com/ishanshre/Book-Review-Platform/internals/forms"
+	"github.com/ishanshre/Book-Review-Platform/internals/helpers"
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+	"github.com/ishanshre/Book-Review-Platform/internals/render"
+)
+
+// AdminContentFilterWords renders the word list of the content filter with the form to add a word
+func (m *Repository) AdminContentFilterWords(w http.ResponseWriter, r *http.Request) {
+	words, err := m.DB.AllFilterWords()
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	data := make(map[string]interface{})
+	data["words"] = words
+	data["word"] = &models.FilterWord{Verdict: string(contentfilter.Hold)}
+	data["base_path"] = base_content_filter_path
+	render.Template(w, r, "admin-contentfilterwords.page.tmpl", &models.TemplateData{
+		Data: data,
+		Form: forms.New(nil),
+	})
+}
+
+// PostAdminAddFilterWord adds the posted word or phrase to the word list with the hold or reject verdict
+func (m *Repository) PostAdminAddFilterWord(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	word := &models.FilterWord{
+		Word:      strings.TrimSpace(r.Form.Get("word")),
+		Verdict:   r.Form.Get("verdict"),
+		CreatedAt: time.Now(),
+	}
+	form := forms.New(r.PostForm)
+	form.Required("word", "verdict")
+	form.MaxLength("word", 100)
+	if word.Verdict != "" && word.Verdict != string(contentfilter.Hold) && word.Verdict != string(contentfilter.Reject) {
+		form.Errors.Add("verdict", "Select hold or reject")
+	}
+	exists, err := m.DB.FilterWordExists(word.Word)
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	if exists {
+		form.Errors.Add("word", "The word is already in the list")
+	}
+	if !form.Valid() {
+		words, err := m.DB.AllFilterWords()
+		if err != nil {
+			helpers.ServerError(w, err)
+			return
+		}
+		data := make(map[string]interface{})
+		data["words"] = words
+		data["word"] = word
+		data["base_path"] = base_content_filter_path
+		render.Template(w, r, "admin-contentfilterwords.page.tmpl", &models.TemplateData{
+			Data: data,
+			Form: form,
+		})
+		return
+	}
+	if err := m.DB.InsertFilterWord(word); err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	m.App.Session.Put(r.Context(), "flash", "Word added to the content filter")
+	http.Redirect(w, r, base_content_filter_path, http.StatusSeeOther)
+}
+
+// PostAdminDeleteFilterWord removes the word from the word list
+func (m *Repository) PostAdminDeleteFilterWord(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		helpers.PageNotFound(w, r, err)
+		return
+	}
+	if err := m.DB.DeleteFilterWord(id); err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	m.App.Session.Put(r.Context(), "flash", "Word removed from the content filter")
+	http.Redirect(w, r, base_content_filter_path, http.StatusSeeOther)
+}
+
+// AdminContentFilterLogs renders the verdicts of the content filter
+func (m *Repository) AdminContentFilterLogs(w http.ResponseWriter, r *http.Request) {
+	data := make(map[string]interface{})
+	data["base_path"] = base_content_filter_path
+	render.Template(w, r, "admin-contentfilterlogs.page.tmpl", &models.TemplateData{
+		Data: data,
+		Form: forms.New(nil),
+	})
+}
+
+// AdminContentFilterLogsApi returns the filtered verdicts of the content filter.
+// The status query filters the verdicts by allow, hold or reject.
+func (m *Repository) AdminContentFilterLogsApi(w http.ResponseWriter, r *http.Request) {
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil {
+		limit = 10
+	}
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil {
+		page = 1
+	}
+	searchKey := r.URL.Query().Get("search")
+	status := r.URL.Query().Get("status")
+	sort := r.URL.Query().Get("sort")
+	logs, err := m.DB.ContentFilterLogFilter(limit, page, searchKey, status, sort)
+	if err != nil {
+		helpers.ServerError(w, err)
+		helpers.StatusInternalServerError(w, err.Error())
+		return
+	}
+	helpers.ApiStatusOkData(w, logs)
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/ishanshre/Book-Review-Platform/internals/helpers"
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+	"github.com/ishanshre/Book-Review-Platform/internals/render"
+)
+
+// AdminAllEmailOutbox renders the page listing pending, sent and failed emails
+func (m *Repository) AdminAllEmailOutbox(w http.ResponseWriter, r *http.Request) {
+	data := make(map[string]interface{})
+	data["base_path"] = base_emails_path
+	render.Template(w, r, "admin-allemails.page.tmpl", &models.TemplateData{
+		Data: data,
+	})
+}
+
+// AdminAllEmailOutboxApi returns the filtered emails of the outbox.
+// The status query filters the emails by pending, sent or failed.
+func (m *Repository) AdminAllEmailOutboxApi(w http.ResponseWriter, r *http.Request) {
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil {
+		limit = 10
+	}
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil {
+		page = 1
+	}
+	searchKey := r.URL.Query().Get("search")
+	status := r.URL.Query().Get("status")
+	sort := r.URL.Query().Get("sort")
+	filteredEmails, err := m.DB.EmailOutboxFilter(limit, page, searchKey, status, sort)
+	if err != nil {
+		helpers.ServerError(w, err)
+		helpers.StatusInternalServerError(w, err.Error())
+		return
+	}
+	helpers.ApiStatusOkData(w, filteredEmails)
+}
+
+// PostAdminResendEmail puts the email back to the outbox queue with fresh attempts
+func (m *Repository) PostAdminResendEmail(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		helpers.PageNotFound(w, r, err)
+		return
+	}
+	if err := m.DB.ResendEmail(id); err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	m.App.Session.Put(r.Context(), "flash", "Email queued for resend")
+	http.Redirect(w, r, base_emails_path, http.StatusSeeOther)
+}
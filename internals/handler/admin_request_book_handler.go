@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/ishanshre/Book-Review-Platform/internals/events"
 	"github.com/ishanshre/Book-Review-Platform/internals/helpers"
 	"github.com/ishanshre/Book-Review-Platform/internals/models"
 	"github.com/ishanshre/Book-Review-Platform/internals/render"
@@ -68,7 +69,8 @@ func (m *Repository) PostAdminUpdateRequestBookStatus(w http.ResponseWriter, r *
 		helpers.ServerError(w, err)
 		return
 	}
-	if err := m.DB.UpdateBookRequestStatus(request_id); err != nil {
+	requestedBook, err := m.DB.GetRequestBookById(request_id)
+	if err != nil {
 		helpers.ServerError(w, err)
 		return
 	}
@@ -78,12 +80,21 @@ func (m *Repository) PostAdminUpdateRequestBookStatus(w http.ResponseWriter, r *
 		return
 	}
 	msg := models.MailData{
-		From:    m.App.AdminEmail,
-		To:      user.Email,
-		Subject: "Your requested book added",
-		Content: `<p>Your requested book has been added to the platform.</p>`,
+		From:     m.App.AdminEmail,
+		To:       user.Email,
+		Subject:  "Your requested book added",
+		Template: "request_book_fulfilled",
+		Data: map[string]interface{}{
+			"Username":  user.Username,
+			"BookTitle": requestedBook.BookTitle,
+			"Author":    requestedBook.Author,
+		},
+	}
+	if err := m.DB.UpdateBookRequestStatus(request_id, &msg); err != nil {
+		helpers.ServerError(w, err)
+		return
 	}
-	m.App.MailChan <- msg
+	m.publish(r.Context(), events.RequestBookFulfilled{Request: requestedBook, User: user})
 	m.App.Session.Put(r.Context(), "flash", fmt.Sprintf("Book Added Email Notification Sent to %s", user.Email))
 	http.Redirect(w, r, "/admin/request-books", http.StatusSeeOther)
 }
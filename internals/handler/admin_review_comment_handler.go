@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/ishanshre/Book-Review-Platform/internals/forms"
+	"github.com/ishanshre/Book-Review-Platform/internals/helpers"
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+	"github.com/ishanshre/Book-Review-Platform/internals/render"
+)
+
+// AdminAllReviewComments renders the review comments moderation page
+func (m *Repository) AdminAllReviewComments(w http.ResponseWriter, r *http.Request) {
+	data := make(map[string]interface{})
+	data["base_path"] = base_review_comments_path
+	render.Template(w, r, "admin-allreviewcomments.page.tmpl", &models.TemplateData{
+		Data: data,
+		Form: forms.New(nil),
+	})
+}
+
+// AdminAllReviewCommentsApi returns the filtered review comments.
+// The status query filters the comments by flagged, hidden or deleted.
+func (m *Repository) AdminAllReviewCommentsApi(w http.ResponseWriter, r *http.Request) {
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil {
+		limit = 10
+	}
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil {
+		page = 1
+	}
+	searchKey := r.URL.Query().Get("search")
+	status := r.URL.Query().Get("status")
+	sort := r.URL.Query().Get("sort")
+	comments, err := m.DB.ReviewCommentFilter(limit, page, searchKey, status, sort)
+	if err != nil {
+		helpers.ServerError(w, err)
+		helpers.StatusInternalServerError(w, err.Error())
+		return
+	}
+	helpers.ApiStatusOkData(w, comments)
+}
+
+// PostAdminModerateReviewComment shows or hides the comment using the is_active form value and clears its flag
+func (m *Repository) PostAdminModerateReviewComment(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		helpers.PageNotFound(w, r, err)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	isActive, err := strconv.ParseBool(r.Form.Get("is_active"))
+	if err != nil {
+		helpers.ClientError(w, http.StatusBadRequest)
+		return
+	}
+	if _, err := m.DB.GetReviewCommentByID(id); err != nil {
+		helpers.PageNotFound(w, r, err)
+		return
+	}
+	if err := m.DB.ModerateReviewComment(id, isActive); err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	if isActive {
+		m.App.Session.Put(r.Context(), "flash", "Comment approved")
+	} else {
+		m.App.Session.Put(r.Context(), "flash", "Comment hidden")
+	}
+	http.Redirect(w, r, base_review_comments_path, http.StatusSeeOther)
+}
+
+// PostAdminDeleteReviewComment deletes the comment along with its replies
+func (m *Repository) PostAdminDeleteReviewComment(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		helpers.PageNotFound(w, r, err)
+		return
+	}
+	if err := m.DB.DeleteReviewComment(id); err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	m.App.Session.Put(r.Context(), "flash", "Comment deleted")
+	http.Redirect(w, r, base_review_comments_path, http.StatusSeeOther)
+}
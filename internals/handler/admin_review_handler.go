@@ -7,10 +7,12 @@ import (
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/ishanshre/Book-Review-Platform/internals/events"
 	"github.com/ishanshre/Book-Review-Platform/internals/forms"
 	"github.com/ishanshre/Book-Review-Platform/internals/helpers"
 	"github.com/ishanshre/Book-Review-Platform/internals/models"
 	"github.com/ishanshre/Book-Review-Platform/internals/render"
+	"github.com/ishanshre/Book-Review-Platform/internals/textdiff"
 )
 
 // AdminAllReviews fetches all the record in Reviews.
@@ -174,6 +176,10 @@ func (m *Repository) PostAdminInsertReview(w http.ResponseWriter, r *http.Reques
 		helpers.ServerError(w, err)
 		return
 	}
+	// an inactive review is published once it is activated
+	if review.IsActive {
+		m.publish(r.Context(), events.ReviewCreated{Review: &review})
+	}
 
 	// Add success message
 	m.App.Session.Put(r.Context(), "flash", "Review record added")
@@ -193,11 +199,18 @@ func (m *Repository) PostAdminDeleteReview(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	review, err := m.DB.GetReviewByID(review_id)
+	if err != nil {
+		helpers.PageNotFound(w, r, err)
+		return
+	}
+
 	// DeleteBuyList interface is used to deleting the record.
 	if err := m.DB.DeleteReview(review_id); err != nil {
 		helpers.ServerError(w, err)
 		return
 	}
+	m.publish(r.Context(), events.ReviewDeleted{Review: review})
 
 	// Add success message
 	m.App.Session.Put(r.Context(), "flash", "Review record deleted")
@@ -309,7 +322,11 @@ func (m *Repository) PostAdminUpdateReview(w http.ResponseWriter, r *http.Reques
 		helpers.ServerError(w, err)
 		return
 	}
-	getReview, _ := m.DB.GetReviewByID(review_id)
+	getReview, err := m.DB.GetReviewByID(review_id)
+	if err != nil {
+		helpers.PageNotFound(w, r, err)
+		return
+	}
 	review := models.Review{
 		ID:        review_id,
 		Rating:    rating,
@@ -364,14 +381,77 @@ func (m *Repository) PostAdminUpdateReview(w http.ResponseWriter, r *http.Reques
 			Form: form,
 			Data: data,
 		})
+		return
 	}
-	if err := m.DB.UpdateReview(&review); err != nil {
+	editor_id := m.App.Session.GetInt(r.Context(), "user_id")
+	if err := m.DB.UpdateReview(&review, reviewRevisions(getReview, &review, editor_id, models.ReviewEditorAdmin)...); err != nil {
 		helpers.ServerError(w, err)
 		return
 	}
+	if !getReview.IsActive && review.IsActive {
+		if err := m.publishActivatedReview(r.Context(), &review); err != nil {
+			helpers.ServerError(w, err)
+			return
+		}
+	} else {
+		m.publish(r.Context(), events.ReviewUpdated{Review: &review})
+	}
+	if getReview.IsActive && !review.IsActive {
+		m.publish(r.Context(), events.ReviewDeactivated{Review: &review})
+	}
 
 	// Add success message
 	m.App.Session.Put(r.Context(), "flash", "Review record updated")
 
 	http.Redirect(w, r, fmt.Sprintf("/admin/reviews/detail/%d", review_id), http.StatusSeeOther)
 }
+
+// AdminReviewRevisions renders the revision history of the review with the changes of every edit
+func (m *Repository) AdminReviewRevisions(w http.ResponseWriter, r *http.Request) {
+	review_id, err := strconv.Atoi(chi.URLParam(r, "review_id"))
+	if err != nil {
+		helpers.PageNotFound(w, r, err)
+		return
+	}
+	review, err := m.DB.GetReviewByID(review_id)
+	if err != nil {
+		helpers.PageNotFound(w, r, err)
+		return
+	}
+	book, err := m.DB.GetBookByID(review.BookID)
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	revisions, err := m.DB.GetReviewRevisions(review.ID)
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+
+	// revisions are ordered from the latest, so the previous revision is the next one in the slice
+	revisionDatas := []*models.ReviewRevisionData{}
+	for i, revision := range revisions {
+		revisionData := &models.ReviewRevisionData{
+			Revision:       revision,
+			PreviousRating: revision.Rating,
+			BodyDiff:       textdiff.Words(revision.Body, revision.Body),
+		}
+		if i+1 < len(revisions) {
+			previous := revisions[i+1]
+			revisionData.PreviousRating = previous.Rating
+			revisionData.RatingChanged = previous.Rating != revision.Rating
+			revisionData.BodyDiff = textdiff.Words(previous.Body, revision.Body)
+		}
+		revisionDatas = append(revisionDatas, revisionData)
+	}
+	data := make(map[string]interface{})
+	data["review"] = review
+	data["book"] = book
+	data["revisionDatas"] = revisionDatas
+	data["base_path"] = base_reviews_path
+	render.Template(w, r, "admin-reviewrevisions.page.tmpl", &models.TemplateData{
+		Form: forms.New(nil),
+		Data: data,
+	})
+}
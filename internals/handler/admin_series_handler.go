@@ -0,0 +1,384 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/ishanshre/Book-Review-Platform/internals/forms"
+	"github.com/ishanshre/Book-Review-Platform/internals/helpers"
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+	"github.com/ishanshre/Book-Review-Platform/internals/render"
+)
+
+// maxSeriesPosition is the largest position a book can have in a series
+const maxSeriesPosition = 999999.99
+
+// seriesPositionPattern matches a number with at most two decimals.
+// The text is checked instead of the parsed float since most decimals like 2.55 have no exact float.
+var seriesPositionPattern = regexp.MustCompile(`^\d+(\.\d{1,2})?$`)
+
+// seriesPosition parses the position field of the form.
+// It adds a form error when the position is not a positive number with at most two decimals.
+func seriesPosition(form *forms.Form) float64 {
+	value := form.Get("position")
+	if !seriesPositionPattern.MatchString(value) {
+		form.Errors.Add("position", "Position must be a number with at most two decimals, e.g. 2 or 2.5")
+		return 0
+	}
+	position, err := strconv.ParseFloat(value, 64)
+	if err != nil || position <= 0 || position > maxSeriesPosition {
+		form.Errors.Add("position", "Position must be greater than 0 and less than 1000000")
+		return 0
+	}
+	return position
+}
+
+// AdminAllSeries renders the admin series page.
+// It takes HTTP response writer and request as parameters.
+// It fetches all series records from db and renders the page with the add series form.
+func (m *Repository) AdminAllSeries(w http.ResponseWriter, r *http.Request) {
+
+	// Retrive all the series with their book counts.
+	// If error occurs, a server error is returned
+	series, err := m.DB.AllSeries()
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+
+	// Create a map that holds the series data
+	data := make(map[string]interface{})
+	data["series"] = series
+	data["base_path"] = base_series_path
+
+	// Render the template with nil form and data
+	render.Template(w, r, "admin-allseries.page.tmpl", &models.TemplateData{
+		Data: data,
+		Form: forms.New(nil),
+	})
+}
+
+// PostAdminAddSeries is a handler that handles add new series.
+// It takes HTTP response writer and request as parameters.
+// It parses the data from form, validates it, check for existing series and only then add new series.
+// Finally, admin is redirected to the new series detail page if adding series is successfull.
+func (m *Repository) PostAdminAddSeries(w http.ResponseWriter, r *http.Request) {
+
+	// Parse the form to populate post form.
+	// If any error occurs, a server error is returned.
+	if err := r.ParseForm(); err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+
+	// Initiate a new form with post form values and add form field validation
+	form := forms.New(r.PostForm)
+	form.Required("name")
+	form.MaxLength("name", 255)
+	form.MaxLength("description", 10000)
+
+	// Create a Series model that holds the form data
+	add_series := models.Series{
+		Name:        r.Form.Get("name"),
+		Description: r.Form.Get("description"),
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	// Check if a series with the same name exists
+	exists, err := m.DB.SeriesExists(add_series.Name, 0)
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	if exists {
+		form.Errors.Add("name", "Series already exists")
+	}
+
+	// If form is not valid render "admin-allseries.page.tmpl" with form and data
+	if !form.Valid() {
+		series, err := m.DB.AllSeries()
+		if err != nil {
+			helpers.ServerError(w, err)
+			return
+		}
+		data := make(map[string]interface{})
+		data["series"] = series
+		data["add_series"] = add_series
+		data["base_path"] = base_series_path
+		render.Template(w, r, "admin-allseries.page.tmpl", &models.TemplateData{
+			Form: form,
+			Data: data,
+		})
+		return
+	}
+
+	// If form is valid then call InsertSeries interface to add new series to db
+	if err := m.DB.InsertSeries(&add_series); err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+
+	m.App.Session.Put(r.Context(), "flash", "Series Added")
+
+	// Finally, admin is redirected to the series detail page to add its books
+	http.Redirect(w, r, fmt.Sprintf("/admin/series/detail/%d", add_series.ID), http.StatusSeeOther)
+}
+
+// renderAdminSeriesDetail renders the series detail page with the books of the series and the form.
+// It writes a not found page when the series does not exist.
+func (m *Repository) renderAdminSeriesDetail(w http.ResponseWriter, r *http.Request, id int, form *forms.Form) {
+	series, err := m.DB.GetSeriesByID(id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			helpers.PageNotFound(w, r, err)
+			return
+		}
+		helpers.ServerError(w, err)
+		return
+	}
+	seriesBooks, err := m.DB.GetSeriesBooks(id, true)
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	allBooks, err := m.DB.AllBook()
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	data := make(map[string]interface{})
+	data["series"] = series
+	data["seriesBooks"] = seriesBooks
+	data["allBooks"] = allBooks
+	data["base_path"] = base_series_path
+	render.Template(w, r, "admin-series-read-update.page.tmpl", &models.TemplateData{
+		Form: form,
+		Data: data,
+	})
+}
+
+// AdminGetSeriesByID renders the series detail and update form page with the books of the series.
+// It takes HTTP response writer and request as parameters.
+func (m *Repository) AdminGetSeriesByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		helpers.PageNotFound(w, r, err)
+		return
+	}
+	m.renderAdminSeriesDetail(w, r, id, forms.New(nil))
+}
+
+// PostAdminGetSeriesByID handles the update of the name and description of the series.
+// It takes HTTP response writer and request as parameters.
+func (m *Repository) PostAdminGetSeriesByID(w http.ResponseWriter, r *http.Request) {
+
+	// Retrive "id" from the url and parse it into integer.
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		helpers.PageNotFound(w, r, err)
+		return
+	}
+
+	// Parse the form to populate the PostForm.
+	if err := r.ParseForm(); err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+
+	// Create a new form with the post form values and add the validations.
+	form := forms.New(r.PostForm)
+	form.Required("name")
+	form.MaxLength("name", 255)
+	form.MaxLength("description", 10000)
+
+	update_series := models.Series{
+		ID:          id,
+		Name:        r.Form.Get("name"),
+		Description: r.Form.Get("description"),
+		UpdatedAt:   time.Now(),
+	}
+
+	// Another series cannot have the same name
+	exists, err := m.DB.SeriesExists(update_series.Name, id)
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	if exists {
+		form.Errors.Add("name", "Series already exists")
+	}
+
+	// If form is invalid render the detail page with the form errors
+	if !form.Valid() {
+		m.renderAdminSeriesDetail(w, r, id, form)
+		return
+	}
+
+	if err := m.DB.UpdateSeries(&update_series); err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	m.App.Session.Put(r.Context(), "flash", "Series Updated")
+
+	// If successful, the admin is redirected to series detail page.
+	http.Redirect(w, r, fmt.Sprintf("/admin/series/detail/%d", id), http.StatusSeeOther)
+}
+
+// AdminDeleteSeries deletes the series and its book memberships from the database in admin context.
+// It takes HTTP response writer and request as parameters.
+func (m *Repository) AdminDeleteSeries(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		helpers.PageNotFound(w, r, err)
+		return
+	}
+	if err := m.DB.DeleteSeries(id); err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	m.App.Session.Put(r.Context(), "flash", "Series Deleted")
+
+	// If successful, the admin is redirected to all series page.
+	http.Redirect(w, r, "/admin/series", http.StatusSeeOther)
+}
+
+// PostAdminAddSeriesBook adds a book to the series at the position from the form.
+// A book can only be in a series once and two books of a series cannot share a position.
+func (m *Repository) PostAdminAddSeriesBook(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		helpers.PageNotFound(w, r, err)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	form := forms.New(r.PostForm)
+	form.Required("book_id")
+	book_id, err := strconv.Atoi(r.Form.Get("book_id"))
+	if err != nil {
+		form.Errors.Add("book_id", "Invalid book")
+	}
+	seriesBook := &models.SeriesBook{
+		SeriesID: id,
+		BookID:   book_id,
+		Position: seriesPosition(form),
+	}
+	if form.Valid() {
+		if _, err := m.DB.GetBookTitleByID(book_id); err != nil {
+			if err != sql.ErrNoRows {
+				helpers.ServerError(w, err)
+				return
+			}
+			form.Errors.Add("book_id", "Book does not exist")
+		}
+		exists, err := m.DB.SeriesBookExists(id, book_id)
+		if err != nil {
+			helpers.ServerError(w, err)
+			return
+		}
+		if exists {
+			form.Errors.Add("book_id", "Book is already in the series")
+		}
+		taken, err := m.DB.SeriesPositionTaken(id, book_id, seriesBook.Position)
+		if err != nil {
+			helpers.ServerError(w, err)
+			return
+		}
+		if taken {
+			form.Errors.Add("position", "Another book is at this position")
+		}
+	}
+	if !form.Valid() {
+		m.renderAdminSeriesDetail(w, r, id, form)
+		return
+	}
+	if err := m.DB.InsertSeriesBook(seriesBook); err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	m.App.Session.Put(r.Context(), "flash", "Book Added To Series")
+	http.Redirect(w, r, fmt.Sprintf("/admin/series/detail/%d", id), http.StatusSeeOther)
+}
+
+// PostAdminUpdateSeriesBook moves the book of the series to the position from the form.
+// It writes a not found page when the book is not in the series.
+func (m *Repository) PostAdminUpdateSeriesBook(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		helpers.PageNotFound(w, r, err)
+		return
+	}
+	book_id, err := strconv.Atoi(chi.URLParam(r, "book_id"))
+	if err != nil {
+		helpers.PageNotFound(w, r, err)
+		return
+	}
+	exists, err := m.DB.SeriesBookExists(id, book_id)
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	if !exists {
+		helpers.PageNotFound(w, r, errors.New("book is not in the series"))
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	form := forms.New(r.PostForm)
+	seriesBook := &models.SeriesBook{
+		SeriesID: id,
+		BookID:   book_id,
+		Position: seriesPosition(form),
+	}
+	if form.Valid() {
+		taken, err := m.DB.SeriesPositionTaken(id, book_id, seriesBook.Position)
+		if err != nil {
+			helpers.ServerError(w, err)
+			return
+		}
+		if taken {
+			form.Errors.Add("position", "Another book is at this position")
+		}
+	}
+	if !form.Valid() {
+		m.renderAdminSeriesDetail(w, r, id, form)
+		return
+	}
+	if err := m.DB.UpdateSeriesBookPosition(seriesBook); err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	m.App.Session.Put(r.Context(), "flash", "Book Position Updated")
+	http.Redirect(w, r, fmt.Sprintf("/admin/series/detail/%d", id), http.StatusSeeOther)
+}
+
+// PostAdminDeleteSeriesBook removes the book from the series
+func (m *Repository) PostAdminDeleteSeriesBook(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		helpers.PageNotFound(w, r, err)
+		return
+	}
+	book_id, err := strconv.Atoi(chi.URLParam(r, "book_id"))
+	if err != nil {
+		helpers.PageNotFound(w, r, err)
+		return
+	}
+	if err := m.DB.DeleteSeriesBook(id, book_id); err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	m.App.Session.Put(r.Context(), "flash", "Book Removed From Series")
+	http.Redirect(w, r, fmt.Sprintf("/admin/series/detail/%d", id), http.StatusSeeOther)
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/ishanshre/Book-Review-Platform/internals/events"
 	"github.com/ishanshre/Book-Review-Platform/internals/forms"
 	"github.com/ishanshre/Book-Review-Platform/internals/helpers"
 	"github.com/ishanshre/Book-Review-Platform/internals/models"
@@ -80,9 +81,15 @@ func (m *Repository) AdminGetUserDetailByID(w http.ResponseWriter, r *http.Reque
 	if err != nil {
 		helpers.ServerError(w, err)
 	}
+	sessions, err := m.DB.GetUserSessionsByUserID(id)
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
 	data := make(map[string]interface{})
 	data["user"] = userKyc.User
 	data["kyc"] = userKyc.Kyc
+	data["sessions"] = sessions
 	data["base_path"] = base_users_path
 	render.Template(w, r, "admin-userdetail.page.tmpl", &models.TemplateData{
 		Data: data,
@@ -226,6 +233,17 @@ func (m *Repository) PostAdminUserDeleteByID(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	// Log the user out of every device before deleting the account
+	sessions, err := m.DB.GetUserSessionsByUserID(id)
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	if err := m.revokeUserSessions(sessions); err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+
 	// The function calls DeleteUser interface to delete the user form the database
 	if err := m.DB.DeleteUser(id); err != nil {
 		helpers.ServerError(w, err)
@@ -237,6 +255,28 @@ func (m *Repository) PostAdminUserDeleteByID(w http.ResponseWriter, r *http.Requ
 	http.Redirect(w, r, "/admin/users", http.StatusSeeOther)
 }
 
+// PostAdminRevokeUserSessions invalidates all the sessions of a user.
+// It takes HTTP response writer and request as parameters.
+// It is used to force logout a compromised account from every device.
+func (m *Repository) PostAdminRevokeUserSessions(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	sessions, err := m.DB.GetUserSessionsByUserID(id)
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	if err := m.revokeUserSessions(sessions); err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	m.App.Session.Put(r.Context(), "flash", fmt.Sprintf("%d Session(s) Revoked", len(sessions)))
+	http.Redirect(w, r, fmt.Sprintf("/admin/users/detail/%d", id), http.StatusSeeOther)
+}
+
 // AdminUserAdd renders page for adding user by admin.
 // It takes HTTP response writer and request as parameters.
 func (m *Repository) AdminUserAdd(w http.ResponseWriter, r *http.Request) {
@@ -327,6 +367,7 @@ func (m *Repository) PostAdminUserAdd(w http.ResponseWriter, r *http.Request) {
 		helpers.ServerError(w, err)
 		return
 	}
+	m.publish(r.Context(), events.UserRegistered{User: &register_user})
 
 	// Add success message
 	m.App.Session.Put(r.Context(), "flash", "User Added")
@@ -350,6 +391,8 @@ func (m *Repository) PostAdminKycUpdate(w http.ResponseWriter, r *http.Request)
 	update_kyc := &models.Kyc{}
 	form := forms.New(r.PostForm)
 	is_validated, _ := strconv.ParseBool(r.Form.Get("is_validated"))
+	// the admin explicitly rejects the submitted documents, which saves the kyc as unverified and notifies the user
+	rejected := r.Form.Get("is_validated") == "rejected"
 	layout := "2006-01-02"
 	dob, err := time.Parse(layout, r.Form.Get("date_of_birth"))
 	if err != nil {
@@ -388,30 +431,29 @@ func (m *Repository) PostAdminKycUpdate(w http.ResponseWriter, r *http.Request)
 		})
 		return
 	}
-	if err := m.DB.AdminKycUpdate(update_kyc); err != nil {
+	approved := update_kyc.IsValidated && !userKyc.Kyc.IsValidated
+	mails := []*models.MailData{}
+	if approved {
+		mails = append(mails, &models.MailData{
+			From:     m.App.AdminEmail,
+			To:       userKyc.User.Email,
+			Subject:  "KYC Verified. Please enjoy our services. Thank You!",
+			Template: "kyc_verified",
+			Data: map[string]interface{}{
+				"Username":     userKyc.User.Username,
+				"SupportEmail": m.App.AdminEmail,
+			},
+		})
+	}
+	if err := m.DB.AdminKycUpdate(update_kyc, mails...); err != nil {
 		helpers.ServerError(w, err)
 		return
 	}
-	if update_kyc.IsValidated {
-		msg := models.MailData{
-			From:    m.App.AdminEmail,
-			To:      userKyc.User.Email,
-			Subject: "KYC Verified. Please enjoy our services. Thank You!",
-			Content: fmt.Sprintf(`
-			<h4>Dear %s,</h4>
-			<p>
-			We are pleased to inform you that your Know Your Customer (KYC) verification process has been successfully completed. Your account is now verified, ensuring a secure and reliable experience with our services.
-			Thank you for providing the necessary documents and information to facilitate this process. KYC verification is a crucial step in ensuring the safety and security of our platform for all users.
-			With your account now verified, you can enjoy uninterrupted access to all features and benefits offered by our platform. If you have any questions or require further assistance, please do not hesitate to contact our customer support team at %s.
-			Once again, thank you for choosing our platform. We look forward to serving you and providing you with a seamless experience.
-			</p>
-			<h4>
-			Best regards,<br>
-			BookWorm
-			%s
-			</h4>`, userKyc.User.Username, m.App.AdminEmail, m.App.AdminEmail),
-		}
-		m.App.MailChan <- msg
+	userKyc.User.ID = id
+	if approved {
+		m.publish(r.Context(), events.KycApproved{User: userKyc.User})
+	} else if rejected && userKyc.Kyc.DocumentFront != "" {
+		m.publish(r.Context(), events.KycRejected{User: userKyc.User})
 	}
 	m.App.Session.Put(r.Context(), "flash", "KYC Updated")
 	http.Redirect(w, r, fmt.Sprintf("/admin/users/detail/%d", id), http.StatusSeeOther)
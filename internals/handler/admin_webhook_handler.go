@@ -0,0 +1,278 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/ishanshre/Book-Review-Platform/internals/forms"
+	"github.com/ishanshre/Book-Review-Platform/internals/helpers"
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+	"github.com/ishanshre/Book-Review-Platform/internals/render"
+)
+
+// webhookFromForm builds the webhook from the posted form and validates it
+func webhookFromForm(form *forms.Form, r *http.Request) *models.Webhook {
+	webhook := &models.Webhook{
+		Url:      r.Form.Get("url"),
+		Secret:   r.Form.Get("secret"),
+		IsActive: r.Form.Get("is_active") == "true",
+	}
+	for _, event := range models.WebhookEvents {
+		for _, selected := range r.Form["events"] {
+			if selected == event {
+				webhook.Events = append(webhook.Events, event)
+			}
+		}
+	}
+	form.Required("url")
+	form.MaxLength("url", 500)
+	form.MaxLength("secret", 255)
+	if u, err := url.ParseRequestURI(webhook.Url); webhook.Url != "" && (err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "") {
+		form.Errors.Add("url", "Enter a valid http or https url")
+	}
+	if len(webhook.Events) == 0 {
+		form.Errors.Add("events", "Select at least one event")
+	}
+	return webhook
+}
+
+// AdminAllWebhooks renders the webhooks page with the form to add new webhook
+func (m *Repository) AdminAllWebhooks(w http.ResponseWriter, r *http.Request) {
+	webhooks, err := m.DB.AllWebhooks()
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	data := make(map[string]interface{})
+	data["webhooks"] = webhooks
+	data["events"] = models.WebhookEvents
+	data["add_webhook"] = &models.Webhook{IsActive: true}
+	data["base_path"] = base_webhooks_path
+	render.Template(w, r, "admin-allwebhooks.page.tmpl", &models.TemplateData{
+		Data: data,
+		Form: forms.New(nil),
+	})
+}
+
+// PostAdminAddWebhook adds new webhook.
+// A random secret is generated when the secret is left empty.
+func (m *Repository) PostAdminAddWebhook(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	form := forms.New(r.PostForm)
+	webhook := webhookFromForm(form, r)
+	if !form.Valid() {
+		webhooks, err := m.DB.AllWebhooks()
+		if err != nil {
+			helpers.ServerError(w, err)
+			return
+		}
+		data := make(map[string]interface{})
+		data["webhooks"] = webhooks
+		data["events"] = models.WebhookEvents
+		data["add_webhook"] = webhook
+		data["base_path"] = base_webhooks_path
+		render.Template(w, r, "admin-allwebhooks.page.tmpl", &models.TemplateData{
+			Form: form,
+			Data: data,
+		})
+		return
+	}
+	if webhook.Secret == "" {
+		secret, err := helpers.GenerateRandomToken(32)
+		if err != nil {
+			helpers.ServerError(w, err)
+			return
+		}
+		webhook.Secret = secret
+	}
+	webhook.CreatedAt = time.Now()
+	webhook.UpdatedAt = time.Now()
+	if err := m.DB.InsertWebhook(webhook); err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	m.App.Session.Put(r.Context(), "flash", "Webhook Added")
+	http.Redirect(w, r, fmt.Sprintf("%s/detail/%d", base_webhooks_path, webhook.ID), http.StatusSeeOther)
+}
+
+// AdminGetWebhookByID renders the webhook detail page with the update form and its delivery log
+func (m *Repository) AdminGetWebhookByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		helpers.PageNotFound(w, r, err)
+		return
+	}
+	webhook, err := m.DB.GetWebhookByID(id)
+	if err != nil {
+		helpers.PageNotFound(w, r, err)
+		return
+	}
+	data := make(map[string]interface{})
+	data["webhook"] = webhook
+	data["events"] = models.WebhookEvents
+	data["base_path"] = base_webhooks_path
+	render.Template(w, r, "admin-webhookdetail.page.tmpl", &models.TemplateData{
+		Data: data,
+		Form: forms.New(nil),
+	})
+}
+
+// PostAdminUpdateWebhook updates the webhook.
+// The secret is kept when left empty, and a new one is generated when regenerate_secret is checked.
+func (m *Repository) PostAdminUpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		helpers.PageNotFound(w, r, err)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	existing, err := m.DB.GetWebhookByID(id)
+	if err != nil {
+		helpers.PageNotFound(w, r, err)
+		return
+	}
+	form := forms.New(r.PostForm)
+	webhook := webhookFromForm(form, r)
+	webhook.ID = id
+	webhook.CreatedAt = existing.CreatedAt
+	if !form.Valid() {
+		webhook.Secret = existing.Secret
+		data := make(map[string]interface{})
+		data["webhook"] = webhook
+		data["events"] = models.WebhookEvents
+		data["base_path"] = base_webhooks_path
+		render.Template(w, r, "admin-webhookdetail.page.tmpl", &models.TemplateData{
+			Form: form,
+			Data: data,
+		})
+		return
+	}
+	if r.Form.Get("regenerate_secret") == "true" {
+		secret, err := helpers.GenerateRandomToken(32)
+		if err != nil {
+			helpers.ServerError(w, err)
+			return
+		}
+		webhook.Secret = secret
+	} else if webhook.Secret == "" {
+		webhook.Secret = existing.Secret
+	}
+	webhook.UpdatedAt = time.Now()
+	if err := m.DB.UpdateWebhook(webhook); err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	m.App.Session.Put(r.Context(), "flash", "Webhook Updated")
+	http.Redirect(w, r, fmt.Sprintf("%s/detail/%d", base_webhooks_path, id), http.StatusSeeOther)
+}
+
+// PostAdminDeleteWebhook deletes the webhook and its delivery log
+func (m *Repository) PostAdminDeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		helpers.PageNotFound(w, r, err)
+		return
+	}
+	if err := m.DB.DeleteWebhook(id); err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	m.App.Session.Put(r.Context(), "flash", "Webhook Deleted")
+	http.Redirect(w, r, base_webhooks_path, http.StatusSeeOther)
+}
+
+// PostAdminTestWebhook queues a ping event for the webhook so the endpoint and signature can be checked
+func (m *Repository) PostAdminTestWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		helpers.PageNotFound(w, r, err)
+		return
+	}
+	webhook, err := m.DB.GetWebhookByID(id)
+	if err != nil {
+		helpers.PageNotFound(w, r, err)
+		return
+	}
+	if !webhook.IsActive {
+		m.App.Session.Put(r.Context(), "error", "Activate the webhook before sending a test event")
+		http.Redirect(w, r, fmt.Sprintf("%s/detail/%d", base_webhooks_path, id), http.StatusSeeOther)
+		return
+	}
+	payload, err := json.Marshal(&models.WebhookPayload{
+		Event:     models.WebhookPing,
+		CreatedAt: time.Now().UTC(),
+		Data: map[string]interface{}{
+			"webhook_id": webhook.ID,
+			"events":     webhook.Events,
+		},
+	})
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	if err := m.DB.EnqueueWebhookDelivery(webhook.ID, models.WebhookPing, payload); err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	m.App.Session.Put(r.Context(), "flash", "Test event queued")
+	http.Redirect(w, r, fmt.Sprintf("%s/detail/%d", base_webhooks_path, id), http.StatusSeeOther)
+}
+
+// PostAdminRedeliverWebhook puts the delivery back to the queue with fresh attempts
+func (m *Repository) PostAdminRedeliverWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		helpers.PageNotFound(w, r, err)
+		return
+	}
+	delivery_id, err := strconv.Atoi(chi.URLParam(r, "delivery_id"))
+	if err != nil {
+		helpers.PageNotFound(w, r, err)
+		return
+	}
+	if err := m.DB.RedeliverWebhookDelivery(id, delivery_id); err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	m.App.Session.Put(r.Context(), "flash", "Delivery queued for redelivery")
+	http.Redirect(w, r, fmt.Sprintf("%s/detail/%d", base_webhooks_path, id), http.StatusSeeOther)
+}
+
+// AdminWebhookDeliveriesApi returns the filtered delivery log of the webhook.
+// The status query filters the deliveries by pending, delivered or failed.
+func (m *Repository) AdminWebhookDeliveriesApi(w http.ResponseWriter, r *http.Request) {
+	webhook_id, err := strconv.Atoi(r.URL.Query().Get("webhook_id"))
+	if err != nil {
+		helpers.ClientError(w, http.StatusBadRequest)
+		return
+	}
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil {
+		limit = 10
+	}
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil {
+		page = 1
+	}
+	searchKey := r.URL.Query().Get("search")
+	status := r.URL.Query().Get("status")
+	sort := r.URL.Query().Get("sort")
+	deliveries, err := m.DB.WebhookDeliveryFilter(webhook_id, limit, page, searchKey, status, sort)
+	if err != nil {
+		helpers.ServerError(w, err)
+		helpers.StatusInternalServerError(w, err.Error())
+		return
+	}
+	helpers.ApiStatusOkData(w, deliveries)
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/ishanshre/Book-Review-Platform/internals/events"
 	"github.com/ishanshre/Book-Review-Platform/internals/forms"
 	"github.com/ishanshre/Book-Review-Platform/internals/helpers"
 	"github.com/ishanshre/Book-Review-Platform/internals/models"
@@ -91,6 +92,7 @@ func (m *Repository) UpdateSession(w http.ResponseWriter, r *http.Request, id, a
 	m.App.Session.Put(r.Context(), "access_level", access_level)
 	m.App.Session.Put(r.Context(), "is_validated", is_validated)
 	m.App.Session.Put(r.Context(), "flash", "Login Successfull")
+	m.recordUserSession(r, id)
 	if !is_validated {
 		m.App.Session.Put(r.Context(), "warning", "Please update your KYC to use its features!")
 	}
@@ -181,20 +183,21 @@ func (m *Repository) PostRegister(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	register.Password = hashed_password
-	if err := m.DB.InsertUser(&register); err != nil {
+	// the welcome mail is queued in the same transaction as the user
+	msg := models.MailData{
+		To:       register.Email,
+		From:     m.App.AdminEmail,
+		Subject:  fmt.Sprintf("Welcome to BookWorm @%s!", register.Username),
+		Template: "welcome",
+		Data: map[string]interface{}{
+			"Username": register.Username,
+		},
+	}
+	if err := m.DB.InsertUser(&register, &msg); err != nil {
 		helpers.ServerError(w, err)
 		return
 	}
-	msg := models.MailData{
-		To:      register.Email,
-		From:    m.App.AdminEmail,
-		Subject: fmt.Sprintf("Welcome to BookWorm @%s!", register.Username),
-		Content: fmt.Sprintf(`
-			<h1>Welcome to BookWorm @%s!</h1>
-			<p>Thank you for registering to our book review platform. Please update your kyc to access most of the features</p>
-		`, register.Username),
-	}
-	m.App.MailChan <- msg
+	m.publish(r.Context(), events.UserRegistered{User: &register})
 	m.App.Session.Put(r.Context(), "flash", "User Registration Successfull")
 	http.Redirect(w, r, "/user/login", http.StatusSeeOther)
 
@@ -213,6 +216,10 @@ func (m *Repository) Logout(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// remove the session from the session index before destroying it
+	if err := m.DB.DeleteUserSessionByToken(m.App.Session.Token(r.Context())); err != nil {
+		m.App.ErrorLog.Println(err)
+	}
 	_ = m.App.Session.Destroy(r.Context())
 	_ = m.App.Session.RenewToken(r.Context())
 	m.App.Session.Put(r.Context(), "flash", "Logout Successfull")
@@ -246,12 +253,41 @@ func (m *Repository) PersonalProfile(w http.ResponseWriter, r *http.Request) {
 		helpers.ServerError(w, err)
 		return
 	}
+	sessions, err := m.DB.GetUserSessionsByUserID(id)
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	digest, err := m.DB.GetDigestPreference(id)
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	readingGoal, err := m.currentReadingGoal(id)
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	privacy, err := m.DB.GetPrivacySettings(id)
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
 	data := make(map[string]interface{})
 	data["user"] = userKyc.User
 	data["kyc"] = userKyc.Kyc
 	data["following"] = following
 	data["read_list_count"] = read_list_count
 	data["buy_list_count"] = buy_list_count
+	data["sessions"] = sessions
+	data["current_session_id"] = m.currentUserSessionID(r, sessions)
+	data["digest"] = digest
+	data["readingGoal"] = readingGoal
+	data["readingGoalYear"] = time.Now().Year()
+	data["privacy"] = privacy
+	if readingGoal != nil {
+		data["readingGoalExpected"] = readingGoal.Expected(time.Now())
+	}
 	render.Template(w, r, "profile.page.tmpl", &models.TemplateData{
 		Data: data,
 		Form: forms.New(nil),
@@ -287,6 +323,26 @@ func (m *Repository) PublicUpdateKYC(w http.ResponseWriter, r *http.Request) {
 		helpers.ServerError(w, err)
 		return
 	}
+	sessions, err := m.DB.GetUserSessionsByUserID(id)
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	digest, err := m.DB.GetDigestPreference(id)
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	readingGoal, err := m.currentReadingGoal(id)
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	privacy, err := m.DB.GetPrivacySettings(id)
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
 	layout := "2006-01-02"
 	dob, err := time.Parse(layout, r.Form.Get("date_of_birth"))
 	if err != nil {
@@ -326,6 +382,15 @@ func (m *Repository) PublicUpdateKYC(w http.ResponseWriter, r *http.Request) {
 	data["following"] = following
 	data["read_list_count"] = read_list_count
 	data["buy_list_count"] = buy_list_count
+	data["sessions"] = sessions
+	data["current_session_id"] = m.currentUserSessionID(r, sessions)
+	data["digest"] = digest
+	data["readingGoal"] = readingGoal
+	data["readingGoalYear"] = time.Now().Year()
+	data["privacy"] = privacy
+	if readingGoal != nil {
+		data["readingGoalExpected"] = readingGoal.Expected(time.Now())
+	}
 	if !form.Valid() {
 		log.Println("inside")
 		render.Template(w, r, "profile.page.tmpl", &models.TemplateData{
@@ -334,21 +399,21 @@ func (m *Repository) PublicUpdateKYC(w http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
-	if err := m.DB.PublicKycUpdate(update_kyc); err != nil {
+	msg := models.MailData{
+		To:       m.App.AdminEmail,
+		From:     userKyc.User.Email,
+		Subject:  fmt.Sprintf("Please check and validate kyc for username: %s", userKyc.User.Username),
+		Template: "kyc_submitted",
+		Data: map[string]interface{}{
+			"Username": userKyc.User.Username,
+			"Link":     fmt.Sprintf("%s/admin/users/detail/%d", m.App.BaseURL, userKyc.User.ID),
+		},
+	}
+	if err := m.DB.PublicKycUpdate(update_kyc, &msg); err != nil {
 		helpers.ServerError(w, err)
 		return
 	}
 	m.App.Session.Put(r.Context(), "flash", "KYC Form Uploaded! Email Notification sent to admin. Please wait for admin to verify")
-	msg := models.MailData{
-		To:      m.App.AdminEmail,
-		From:    userKyc.User.Email,
-		Subject: fmt.Sprintf("Please check and validate kyc for username: %s", userKyc.User.Username),
-		Content: fmt.Sprintf(`
-		     <h1>Update and Validate User's Kyc: %s</h1>
-			 <p>Please check, validate the updated KYC of user at <a href="%s/admin/users/detail/%s">@%s</a></p>
-		`, userKyc.User.Username, r.Host, userKyc.User.Username, userKyc.User.Username),
-	}
-	m.App.MailChan <- msg
 	http.Redirect(w, r, "/profile", http.StatusSeeOther)
 }
 
@@ -101,19 +101,20 @@ func (m *Repository) PostResetPassword(w http.ResponseWriter, r *http.Request) {
 	userStore.PasswordResetRepo[token] = resetToken
 
 	// send the email to email address with reset token
-	body := fmt.Sprintf(`
-		<h1>Reset Password</h1>
-			<strong>The token for password change = </strong> %s<br><hr>
-			<button><a href="%s/user/reset">Reset</a></button><br><hr>
-			<strong>Ignore it, if you did not apply for reset password</strong>
-	`, token, r.Header["Origin"][0])
 	msg := models.MailData{
-		To:      reset_user.Email,
-		From:    m.App.AdminEmail,
-		Subject: "Change Password",
-		Content: body,
+		To:       reset_user.Email,
+		From:     m.App.AdminEmail,
+		Subject:  "Change Password",
+		Template: "password_reset",
+		Data: map[string]interface{}{
+			"Token": token,
+			"Link":  fmt.Sprintf("%s/user/reset", m.App.BaseURL),
+		},
+	}
+	if err := m.DB.EnqueueMail(&msg); err != nil {
+		helpers.ServerError(w, err)
+		return
 	}
-	m.App.MailChan <- msg
 
 	// Add success message
 	m.App.Session.Put(r.Context(), "flash", "Reset token is sent to email")
@@ -212,30 +213,26 @@ func (m *Repository) PostResetPasswordChange(w http.ResponseWriter, r *http.Requ
 	user.Password = hashed_password
 	userStore.Users[resetToken.Email] = user
 
-	// Call ChangePassword interface to change the password.
+	// notification for successfull password change
+	msg := models.MailData{
+		To:       resetToken.Email,
+		From:     m.App.AdminEmail,
+		Subject:  "Password Reset Successfull",
+		Template: "password_reset_success",
+		Data: map[string]interface{}{
+			"Link": fmt.Sprintf("%s/user/login", m.App.BaseURL),
+		},
+	}
+
+	// Call ChangePassword interface to change the password and queue the notification.
 	// If any error occurs, a server error is returned
-	if err := m.DB.ChangePassword(hashed_password, resetToken.Email); err != nil {
+	if err := m.DB.ChangePassword(hashed_password, resetToken.Email, &msg); err != nil {
 		helpers.ServerError(w, err)
 		return
 	}
 
 	// Deletes the token stored in cache.
 	delete(userStore.PasswordResetRepo, resetToken.Token)
-	// To do add notification for successfull password change
-	body := fmt.Sprintf(`
-		<h1>Password Reset Successfull<h1>
-			<p>You can login from here<p><br>
-			<button><a href="%s/user/login">Login</a></button>
-	`, r.Header["Origin"][0])
-	msg := models.MailData{
-		To:      resetToken.Email,
-		From:    m.App.AdminEmail,
-		Subject: "Password Reset Successfull",
-		Content: body,
-	}
-
-	// send the msg to email channel
-	m.App.MailChan <- msg
 
 	// Add success message
 	m.App.Session.Put(r.Context(), "flash", "Password Reset Successfull")
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ishanshre/Book-Review-Platform/internals/contentfilter"
+	"github.com/ishanshre/Book-Review-Platform/internals/helpers"
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+)
+
+// content filter thresholds of the heuristics
+const (
+	contentFilterLinksHold          = 2
+	contentFilterLinksReject        = 5
+	contentFilterMaxRepeatedChars   = 6
+	contentFilterCapsRatio          = 0.7
+	contentFilterCapsMinLetters     = 20
+	contentFilterDuplicateThreshold = 0.7
+	contentFilterDuplicateWindow    = 24 * time.Hour
+	contentFilterDuplicateLimit     = 200
+)
+
+// contentFilter builds the content filter pipeline with the current word list and the recent submissions of the kind.
+// The submissions of the user are left out of the near duplicate check so that editing a review is not caught.
+// Near duplicate contact messages are rejected as bot spam while near duplicate reviews are held.
+func (m *Repository) contentFilter(kind string, user_id int) (*contentfilter.Pipeline, error) {
+	filterWords, err := m.DB.AllFilterWords()
+	if err != nil {
+		return nil, err
+	}
+	recent, err := m.DB.RecentContentFilterContents(kind, user_id, time.Now().Add(-contentFilterDuplicateWindow), contentFilterDuplicateLimit)
+	if err != nil {
+		return nil, err
+	}
+	wordList := &contentfilter.WordList{}
+	for _, word := range filterWords {
+		wordList.Words = append(wordList.Words, contentfilter.Word{
+			Word:    word.Word,
+			Verdict: contentfilter.Verdict(word.Verdict),
+		})
+	}
+	duplicate := contentfilter.Hold
+	if kind == models.ContentFilterContact {
+		duplicate = contentfilter.Reject
+	}
+	return contentfilter.New(
+		wordList,
+		&contentfilter.Links{Hold: contentFilterLinksHold, Reject: contentFilterLinksReject},
+		&contentfilter.RepeatedChars{Max: contentFilterMaxRepeatedChars},
+		&contentfilter.Caps{Ratio: contentFilterCapsRatio, MinLetters: contentFilterCapsMinLetters},
+		&contentfilter.NearDuplicate{Recent: recent, Threshold: contentFilterDuplicateThreshold, Verdict: duplicate},
+	), nil
+}
+
+// checkContent runs the content filter of the kind on the text submitted by the user
+func (m *Repository) checkContent(kind string, user_id int, text string) (*contentfilter.Decision, error) {
+	pipeline, err := m.contentFilter(kind, user_id)
+	if err != nil {
+		return nil, err
+	}
+	return pipeline.Check(&contentfilter.Submission{Kind: kind, Text: text}), nil
+}
+
+// logContentFilter stores the verdict of the content filter on the submission.
+// The target id is zero for the rejected submissions. Errors are only logged and do not fail the request.
+func (m *Repository) logContentFilter(r *http.Request, kind string, target_id, user_id int, text string, decision *contentfilter.Decision) {
+	log := &models.ContentFilterLog{
+		Kind:      kind,
+		TargetID:  target_id,
+		UserID:    user_id,
+		Verdict:   string(decision.Verdict),
+		Reasons:   truncate(decision.Reasons(), 1000),
+		Content:   truncate(text, 10000),
+		IpAddress: truncate(helpers.ClientIP(r), 255),
+		CreatedAt: time.Now(),
+	}
+	if err := m.DB.InsertContentFilterLog(log); err != nil {
+		m.App.ErrorLog.Printf("logging the content filter verdict of %s %d: %s", kind, target_id, err)
+	}
+}
+
+// truncate cuts the text to at most max characters
+func truncate(text string, max int) string {
+	if utf8.RuneCountInString(text) <= max {
+		return text
+	}
+	return string([]rune(text)[:max])
+}
@@ -1,29 +1,47 @@
 package handler
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/ishanshre/Book-Review-Platform/internals/cache"
 	"github.com/ishanshre/Book-Review-Platform/internals/config"
 	"github.com/ishanshre/Book-Review-Platform/internals/driver"
+	"github.com/ishanshre/Book-Review-Platform/internals/events"
+	"github.com/ishanshre/Book-Review-Platform/internals/pubsub"
 	"github.com/ishanshre/Book-Review-Platform/internals/repository"
 	"github.com/ishanshre/Book-Review-Platform/internals/repository/dbrepo"
 )
 
-// Repository used to get global app config and database access
+// Repository used to get global app config, database access, the event bus, the live updates hub and the cache
 type Repository struct {
-	App *config.AppConfig
-	DB  repository.DatabaseRepo
+	App    *config.AppConfig
+	DB     repository.DatabaseRepo
+	Events *events.Bus
+	Hub    *pubsub.Hub
+	Cache  *cache.Cache
 }
 
 // Repo is of type Repository and used by handlers to get access to global app config and datbase
 var Repo *Repository
 
 // NewRepo creates a new Repository
-func NewRepo(a *config.AppConfig, db *driver.DB) *Repository {
+func NewRepo(a *config.AppConfig, db *driver.DB, bus *events.Bus, hub *pubsub.Hub, c *cache.Cache) *Repository {
 	return &Repository{
-		App: a,
-		DB:  dbrepo.NewPostgresRepo(db.SQL, a),
+		App:    a,
+		DB:     dbrepo.NewPostgresRepo(db.SQL, a),
+		Events: bus,
+		Hub:    hub,
+		Cache:  c,
+	}
+}
+
+// publish delivers the domain event to its subscribers.
+// It is called after the change is stored, so errors of the subscribers are only logged and do not fail the request.
+func (m *Repository) publish(ctx context.Context, e events.Event) {
+	if err := m.Events.Publish(ctx, e); err != nil {
+		m.App.ErrorLog.Printf("publishing %s: %s", e.Name(), err)
 	}
 }
 
@@ -34,6 +52,7 @@ func NewHandler(r *Repository) {
 
 const base_users_path = "/admin/users"
 const base_genres_path = "/admin/genres"
+const base_series_path = "/admin/series"
 const base_publishers_path = "/admin/publishers"
 const base_authors_path = "/admin/authors"
 const base_languages_path = "/admin/languages"
@@ -45,8 +64,13 @@ const base_readLists_path = "/admin/readLists"
 const base_buyLists_path = "/admin/buyLists"
 const base_followers_path = "/admin/followers"
 const base_reviews_path = "/admin/reviews"
+const base_review_comments_path = "/admin/review-comments"
 const base_contacts_path = "/admin/contacts"
+const base_content_filter_path = "/admin/content-filter"
 const base_request_book_path = "/admin/request-books"
+const base_emails_path = "/admin/emails"
+const base_webhooks_path = "/admin/webhooks"
+const base_moderation_reports_path = "/moderation/reports"
 
 // ClearSessionMessage clears the session message like flash, error and warning after being displayed
 func (m *Repository) ClearSessionMessage(w http.ResponseWriter, r *http.Request) {
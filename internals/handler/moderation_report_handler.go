@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/ishanshre/Book-Review-Platform/internals/events"
+	"github.com/ishanshre/Book-Review-Platform/internals/forms"
+	"github.com/ishanshre/Book-Review-Platform/internals/helpers"
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+	"github.com/ishanshre/Book-Review-Platform/internals/render"
+)
+
+// ModerationReports renders the moderation queue of the reported reviews and comments
+func (m *Repository) ModerationReports(w http.ResponseWriter, r *http.Request) {
+	data := make(map[string]interface{})
+	data["base_path"] = base_moderation_reports_path
+	data["resolutions"] = models.ReportResolutions
+	render.Template(w, r, "moderation-reports.page.tmpl", &models.TemplateData{
+		Data: data,
+		Form: forms.New(nil),
+	})
+}
+
+// ModerationReportsApi returns the filtered reports.
+// The status query filters the reports by open or resolved.
+func (m *Repository) ModerationReportsApi(w http.ResponseWriter, r *http.Request) {
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil {
+		limit = 10
+	}
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil {
+		page = 1
+	}
+	searchKey := r.URL.Query().Get("search")
+	status := r.URL.Query().Get("status")
+	sort := r.URL.Query().Get("sort")
+	reports, err := m.DB.ReportFilter(limit, page, searchKey, status, sort)
+	if err != nil {
+		helpers.ServerError(w, err)
+		helpers.StatusInternalServerError(w, err.Error())
+		return
+	}
+	helpers.ApiStatusOkData(w, reports)
+}
+
+// PostModerationResolveReport applies the posted resolution to the reported review or comment
+// and resolves every open report of it with the resolution and the note of the moderator.
+func (m *Repository) PostModerationResolveReport(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		helpers.PageNotFound(w, r, err)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	report, err := m.DB.GetReportByID(id)
+	if err != nil {
+		helpers.PageNotFound(w, r, err)
+		return
+	}
+	resolution := r.Form.Get("resolution")
+	note := r.Form.Get("note")
+	if !containsString(models.ReportResolutions, resolution) {
+		m.App.Session.Put(r.Context(), "error", "Select a valid resolution")
+		http.Redirect(w, r, base_moderation_reports_path, http.StatusSeeOther)
+		return
+	}
+	if len(note) > 1000 {
+		m.App.Session.Put(r.Context(), "error", "The note must be at most 1000 characters long")
+		http.Redirect(w, r, base_moderation_reports_path, http.StatusSeeOther)
+		return
+	}
+	if report.Status != models.ReportOpen {
+		m.App.Session.Put(r.Context(), "error", "The report is already resolved")
+		http.Redirect(w, r, base_moderation_reports_path, http.StatusSeeOther)
+		return
+	}
+	report.ResolutionNote = note
+	if err := m.applyReportResolution(r.Context(), report, resolution); err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	moderator_id := m.App.Session.GetInt(r.Context(), "user_id")
+	if err := m.DB.ResolveReports(report.TargetType, report.TargetID, resolution, note, moderator_id); err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	m.App.Session.Put(r.Context(), "flash", "Report resolved as "+resolution)
+	http.Redirect(w, r, base_moderation_reports_path, http.StatusSeeOther)
+}
+
+// applyReportResolution dismisses, hides, deletes or warns the author of the reported review or comment.
+// Dismissing restores the content only when the reports hid it, content hidden for other reasons stays hidden.
+func (m *Repository) applyReportResolution(ctx context.Context, report *models.Report, resolution string) error {
+	switch resolution {
+	case models.ReportDismissed:
+		hid, err := m.DB.OpenReportsHidTarget(report.TargetType, report.TargetID)
+		if err != nil || !hid {
+			return err
+		}
+		if report.TargetType == models.ReportTargetComment {
+			return m.DB.ModerateReviewComment(report.TargetID, true)
+		}
+		return m.setReviewActive(ctx, report.TargetID, true)
+	case models.ReportHidden:
+		if report.TargetType == models.ReportTargetComment {
+			return m.DB.ModerateReviewComment(report.TargetID, false)
+		}
+		return m.setReviewActive(ctx, report.TargetID, false)
+	case models.ReportDeleted:
+		if report.TargetType == models.ReportTargetComment {
+			return m.DB.DeleteReviewComment(report.TargetID)
+		}
+		review, err := m.DB.GetReviewByID(report.TargetID)
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := m.DB.DeleteReview(review.ID); err != nil {
+			return err
+		}
+		m.publish(ctx, events.ReviewDeleted{Review: review})
+	case models.ReportWarned:
+		if report.TargetUserID == 0 {
+			return nil
+		}
+		user, err := m.DB.GetGlobalUserByIDAny(report.TargetUserID)
+		if err != nil {
+			return err
+		}
+		m.publish(ctx, events.UserWarned{User: user, Report: report})
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ishanshre/Book-Review-Platform/internals/helpers"
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+	"github.com/ishanshre/Book-Review-Platform/internals/render"
+)
+
+// activityLimit is the number of activities on a page
+const activityLimit = 20
+
+// activityPage returns the page query param, the first page when it is missing
+func activityPage(r *http.Request) int {
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		return 1
+	}
+	return page
+}
+
+// canViewActivity returns true if the viewer can see the activity of the owner according to the privacy settings of the owner.
+// The viewer id is zero for anonymous visitors.
+func (m *Repository) canViewActivity(viewer_id int, owner *models.User) (bool, error) {
+	if viewer_id == owner.ID {
+		return true, nil
+	}
+	settings, err := m.DB.GetPrivacySettings(owner.ID)
+	if err != nil {
+		return false, err
+	}
+	return settings.ActivityVisibility == models.VisibilityPublic, nil
+}
+
+// ActivityFeed renders the activity of the users the authenticated user follows
+func (m *Repository) ActivityFeed(w http.ResponseWriter, r *http.Request) {
+	user_id := m.App.Session.GetInt(r.Context(), "user_id")
+	feed, err := m.DB.GetFollowedActivities(user_id, activityLimit, activityPage(r))
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	data := make(map[string]interface{})
+	data["feed"] = feed
+	data["previousPage"] = feed.Page - 1
+	data["nextPage"] = feed.Page + 1
+	render.Template(w, r, "public_activity.page.tmpl", &models.TemplateData{
+		Data: data,
+	})
+}
+
+// ActivityFeedApi returns the activity of the users the authenticated user follows
+func (m *Repository) ActivityFeedApi(w http.ResponseWriter, r *http.Request) {
+	user_id := m.App.Session.GetInt(r.Context(), "user_id")
+	feed, err := m.DB.GetFollowedActivities(user_id, activityLimit, activityPage(r))
+	if err != nil {
+		helpers.StatusInternalServerError(w, "something went wrong")
+		return
+	}
+	helpers.ApiStatusOkData(w, feed)
+}
+
+// UserActivityApi returns the activity of the user when their privacy settings allow it
+func (m *Repository) UserActivityApi(w http.ResponseWriter, r *http.Request) {
+	user := m.userByUsername(w, r)
+	if user == nil {
+		return
+	}
+	viewer_id := m.App.Session.GetInt(r.Context(), "user_id")
+	allowed, err := m.canViewActivity(viewer_id, user)
+	if err != nil {
+		helpers.StatusInternalServerError(w, "something went wrong")
+		return
+	}
+	if !allowed {
+		helpers.ApiError(w, http.StatusForbidden, "the activity of this user is private")
+		return
+	}
+	activities, err := m.DB.GetUserActivities(user.ID, activityLimit, activityPage(r), viewer_id == user.ID)
+	if err != nil {
+		helpers.StatusInternalServerError(w, "something went wrong")
+		return
+	}
+	helpers.ApiStatusOkData(w, activities)
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/ishanshre/Book-Review-Platform/internals/helpers"
+)
+
+// bookEventsPingInterval is the time between two keep alive comments on the event stream
+const bookEventsPingInterval = 25 * time.Second
+
+// BookEventsTopic returns the pub/sub topic of the live updates of the book
+func BookEventsTopic(isbn int64) string {
+	return fmt.Sprintf("book:%d", isbn)
+}
+
+// BookEvents streams the new, updated and deleted reviews and the rating changes of the book as Server-Sent Events.
+// The events are review.created, review.updated, review.deleted and rating.updated with JSON data.
+func (m *Repository) BookEvents(w http.ResponseWriter, r *http.Request) {
+	isbn, err := strconv.ParseInt(chi.URLParam(r, "isbn"), 10, 64)
+	if err != nil {
+		helpers.PageNotFound(w, r, err)
+		return
+	}
+	if _, err := m.DB.GetBookByISBN(isbn); err != nil {
+		if err == sql.ErrNoRows {
+			helpers.PageNotFound(w, r, err)
+			return
+		}
+		helpers.ServerError(w, err)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		helpers.ServerError(w, fmt.Errorf("streaming is not supported by the response writer"))
+		return
+	}
+
+	subscription := m.Hub.Subscribe(BookEventsTopic(isbn))
+	defer subscription.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	// ask the browser to reconnect after 5 seconds when the connection is lost
+	fmt.Fprint(w, "retry: 5000\n\n")
+	flusher.Flush()
+
+	ping := time.NewTicker(bookEventsPingInterval)
+	defer ping.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ping.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		case msg, ok := <-subscription.C:
+			if !ok {
+				return
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", msg.Event, msg.Data)
+			flusher.Flush()
+		}
+	}
+}
@@ -5,11 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-faker/faker/v4"
+	"github.com/ishanshre/Book-Review-Platform/internals/events"
 	"github.com/ishanshre/Book-Review-Platform/internals/helpers"
 	"github.com/ishanshre/Book-Review-Platform/internals/models"
 	"github.com/ishanshre/Book-Review-Platform/internals/render"
@@ -48,6 +50,9 @@ func (m *Repository) Home(w http.ResponseWriter, r *http.Request) {
 		var totalRatings float64
 		var numReviews int
 		for _, review := range reviews {
+			if !review.IsActive {
+				continue
+			}
 			totalRatings += review.Rating
 			numReviews++
 		}
@@ -88,6 +93,14 @@ func (m *Repository) Home(w http.ResponseWriter, r *http.Request) {
 	data["allBooks"] = allBooks
 	data["recentBooks"] = recentBooks
 	data["topRatedBooks"] = topRatedBooks
+	if user_id := m.App.Session.GetInt(r.Context(), "user_id"); user_id > 0 {
+		feed, err := m.homeFeed(user_id)
+		if err != nil {
+			helpers.ServerError(w, err)
+			return
+		}
+		data["feed"] = feed
+	}
 	render.Template(w, r, "public_home.page.tmpl", &models.TemplateData{
 		Data: data,
 	})
@@ -137,27 +150,64 @@ func (m *Repository) BookDetailByISBN(w http.ResponseWriter, r *http.Request) {
 		helpers.ServerError(w, err)
 		return
 	}
+	user_id := m.App.Session.GetInt(r.Context(), "user_id")
+	voteCounts, err := m.DB.GetReviewVoteCountsByBookID(book.BookWithPublisherData.ID, user_id)
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	editTimes, err := m.DB.GetReviewEditTimesByBookID(book.BookWithPublisherData.ID)
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
 	var totalRatings float64
 	var numReviews int
 	var averageRating float64
 	reviewDatas := []*models.ReviewUserData{}
 	for _, review := range reviews {
+		// hidden and held reviews are only shown to their author
+		if !review.IsActive && review.UserID != user_id {
+			continue
+		}
 		user, err := m.DB.GetGlobalUserByIDAny(review.UserID)
 		if err != nil {
 			helpers.ServerError(w, err)
 			return
 		}
+		votes, ok := voteCounts[review.ID]
+		if !ok {
+			votes = &models.ReviewVoteCount{ReviewID: review.ID}
+		}
 		reviewData := &models.ReviewUserData{
 			Review: review,
 			User:   user,
+			Votes:  votes,
+		}
+		if editedAt, ok := editTimes[review.ID]; ok {
+			reviewData.EditedAt = &editedAt
 		}
 		reviewDatas = append(reviewDatas, reviewData)
+		if !review.IsActive {
+			continue
+		}
 		totalRatings += review.Rating
 		numReviews++
 	}
 	if numReviews > 0 {
 		averageRating = totalRatings / float64(numReviews)
 	}
+	// reviews are listed in the order they were written unless sorted by the most helpful
+	reviewSort := r.URL.Query().Get("sort")
+	if reviewSort == models.ReviewVoteHelpful {
+		sort.SliceStable(reviewDatas, func(i, j int) bool {
+			a, b := reviewDatas[i].Votes, reviewDatas[j].Votes
+			if a.Score() != b.Score() {
+				return a.Score() > b.Score()
+			}
+			return a.Helpful > b.Helpful
+		})
+	}
 	genres, err := m.DB.GetGenresFromBookID(book.BookWithPublisherData.ID)
 	if err != nil {
 		helpers.ServerError(w, err)
@@ -168,6 +218,16 @@ func (m *Repository) BookDetailByISBN(w http.ResponseWriter, r *http.Request) {
 		helpers.ServerError(w, err)
 		return
 	}
+	alsoLiked, err := m.DB.GetSimilarBooks(book.BookWithPublisherData.ID, alsoLikedLimit)
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	bookSeries, err := m.DB.GetBookSeries(book.BookWithPublisherData.ID)
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
 	data := make(map[string]interface{})
 	data["book"] = book.BookWithPublisherData
 	data["authors"] = authors
@@ -175,6 +235,10 @@ func (m *Repository) BookDetailByISBN(w http.ResponseWriter, r *http.Request) {
 	data["genres"] = genres
 	data["languages"] = languages
 	data["reviewDatas"] = reviewDatas
+	data["alsoLiked"] = alsoLiked
+	data["bookSeries"] = bookSeries
+	data["reviewSort"] = reviewSort
+	data["reportReasons"] = models.ReportReasons
 	data["averageRating"] = averageRating
 	data["lastIndexAuthors"] = len(authors) - 1
 	data["lastIndexGenres"] = len(genres) - 1
@@ -320,6 +384,7 @@ func (m *Repository) AddtoReadListApi(w http.ResponseWriter, r *http.Request) {
 		helpers.ServerError(w, err)
 		return
 	}
+	m.publish(r.Context(), events.UserLibraryChanged{UserID: user_id})
 	helpers.ApiStatusOk(w, "add to read list success")
 }
 
@@ -343,6 +408,7 @@ func (m *Repository) RemoveFromReadListApi(w http.ResponseWriter, r *http.Reques
 		helpers.ServerError(w, err)
 		return
 	}
+	m.publish(r.Context(), events.UserLibraryChanged{UserID: user_id})
 	helpers.ApiStatusOk(w, "Removed from read list")
 }
 
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/ishanshre/Book-Review-Platform/internals/contentfilter"
 	"github.com/ishanshre/Book-Review-Platform/internals/forms"
 	"github.com/ishanshre/Book-Review-Platform/internals/helpers"
 	"github.com/ishanshre/Book-Review-Platform/internals/models"
@@ -63,21 +64,49 @@ func (m *Repository) PostContactUs(w http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	text := contact.Subject + "\n" + contact.Message
+	decision, err := m.checkContent(models.ContentFilterContact, 0, text)
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	if decision.Verdict == contentfilter.Reject {
+		m.logContentFilter(r, models.ContentFilterContact, 0, 0, text, decision)
+		form.Errors.Add("message", "The message was rejected by the spam filter")
+		render.Template(w, r, "contact-us.page.tmpl", &models.TemplateData{
+			Form: form,
+			Data: data,
+		})
+		return
+	}
+	contact.IsActive = decision.Verdict == contentfilter.Allow
 	contact.SubmittedAt = time.Now()
 	contact.IpAddress = r.RemoteAddr
 	contact.BrowserInfo = r.UserAgent()
 	contact.ReferringPage = r.Referer()
-	if err := m.DB.InsertContact(&contact); err != nil {
+	msg := models.MailData{
+		To:       m.App.AdminEmail,
+		From:     contact.Email,
+		Subject:  fmt.Sprintf("Contact Notification: %v", contact.Subject),
+		Template: "contact_notification",
+		Data: map[string]interface{}{
+			"FirstName": contact.FirstName,
+			"LastName":  contact.LastName,
+			"Email":     contact.Email,
+			"Subject":   contact.Subject,
+			"Message":   contact.Message,
+		},
+	}
+	// the admin is only notified about the messages allowed by the content filter
+	mails := []*models.MailData{}
+	if contact.IsActive {
+		mails = append(mails, &msg)
+	}
+	if err := m.DB.InsertContact(&contact, mails...); err != nil {
 		helpers.ServerError(w, err)
 		return
 	}
-	msg := models.MailData{
-		To:      m.App.AdminEmail,
-		From:    contact.Email,
-		Subject: fmt.Sprintf("Contact Notification: %v", contact.Subject),
-		Content: fmt.Sprintf("%v %v contacted the company. \n %v", contact.FirstName, contact.LastName, contact.Message),
-	}
-	m.App.MailChan <- msg
+	m.logContentFilter(r, models.ContentFilterContact, contact.ID, 0, text, decision)
 
 	// Add success message
 	m.App.Session.Put(r.Context(), "flash", "Message Successfull Sent")
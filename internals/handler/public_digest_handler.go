@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/ishanshre/Book-Review-Platform/internals/forms"
+	"github.com/ishanshre/Book-Review-Platform/internals/helpers"
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+	"github.com/ishanshre/Book-Review-Platform/internals/render"
+)
+
+// PostDigestPreference updates how often the authenticated user receives the email digest
+func (m *Repository) PostDigestPreference(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	frequency := r.Form.Get("frequency")
+	switch frequency {
+	case models.DigestNever, models.DigestDaily, models.DigestWeekly, models.DigestMonthly:
+	default:
+		m.App.Session.Put(r.Context(), "error", "Invalid digest frequency")
+		http.Redirect(w, r, "/profile", http.StatusSeeOther)
+		return
+	}
+	user_id := m.App.Session.GetInt(r.Context(), "user_id")
+	if err := m.DB.UpdateDigestFrequency(user_id, frequency); err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	m.App.Session.Put(r.Context(), "flash", "Digest Preference Updated")
+	http.Redirect(w, r, "/profile", http.StatusSeeOther)
+}
+
+// DigestUnsubscribe renders the page confirming the unsubscribe from the email digest.
+// It writes a not found page when the token is unknown.
+func (m *Repository) DigestUnsubscribe(w http.ResponseWriter, r *http.Request) {
+	token := chi.URLParam(r, "token")
+	found, err := m.DB.DigestUnsubscribeTokenExists(token)
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	if !found {
+		helpers.PageNotFound(w, r, errors.New("invalid unsubscribe token"))
+		return
+	}
+	data := make(map[string]interface{})
+	data["token"] = token
+	data["unsubscribed"] = false
+	render.Template(w, r, "digest-unsubscribe.page.tmpl", &models.TemplateData{
+		Data: data,
+		Form: forms.New(nil),
+	})
+}
+
+// PostDigestUnsubscribe turns off the email digest of the user owning the token.
+// Mail clients send the RFC 8058 one-click unsubscribe request to this url without csrf token,
+// so the path is exempted from the csrf check and the token is the only authorization.
+func (m *Repository) PostDigestUnsubscribe(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		helpers.ClientError(w, http.StatusBadRequest)
+		return
+	}
+	token := chi.URLParam(r, "token")
+	found, err := m.DB.UnsubscribeDigest(token)
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	if !found {
+		helpers.PageNotFound(w, r, errors.New("invalid unsubscribe token"))
+		return
+	}
+	if r.Form.Get("List-Unsubscribe") == "One-Click" {
+		helpers.ApiStatusOk(w, "unsubscribed")
+		return
+	}
+	data := make(map[string]interface{})
+	data["token"] = token
+	data["unsubscribed"] = true
+	render.Template(w, r, "digest-unsubscribe.page.tmpl", &models.TemplateData{
+		Data: data,
+		Form: forms.New(nil),
+	})
+}
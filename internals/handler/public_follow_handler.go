@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/ishanshre/Book-Review-Platform/internals/events"
 	"github.com/ishanshre/Book-Review-Platform/internals/helpers"
 	"github.com/ishanshre/Book-Review-Platform/internals/models"
 )
@@ -65,6 +66,8 @@ func (h *Repository) FollowApi(w http.ResponseWriter, r *http.Request) {
 		helpers.ServerError(w, err)
 		return
 	}
+	h.publish(r.Context(), events.UserLibraryChanged{UserID: user_id})
+	h.publish(r.Context(), events.AuthorFollowed{UserID: user_id, AuthorID: author_id})
 	helpers.ApiStatusOk(w, "follow success")
 }
 func (h *Repository) UnFollowApi(w http.ResponseWriter, r *http.Request) {
@@ -92,6 +95,7 @@ func (h *Repository) UnFollowApi(w http.ResponseWriter, r *http.Request) {
 		helpers.ServerError(w, err)
 		return
 	}
+	h.publish(r.Context(), events.UserLibraryChanged{UserID: user_id})
 	helpers.ApiStatusOk(w, "unfollow success")
 }
 
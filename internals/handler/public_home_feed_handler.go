@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ishanshre/Book-Review-Platform/internals/cache"
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+)
+
+// homeFeedGeneration is bumped to invalidate the home feeds of every user at once
+const homeFeedGeneration = "home-feed"
+
+// homeFeedTTL is the time a home feed is cached when nothing invalidates it before
+const homeFeedTTL = 15 * time.Minute
+
+// homeFeedLimit is the number of books or reviews in every section of the home feed
+const homeFeedLimit = 8
+
+// favouriteGenresLimit is the number of favourite genres the books of the home feed are picked from
+const favouriteGenresLimit = 3
+
+// homeFeedKey returns the cache key of the home feed of the user in the current generation
+func homeFeedKey(c *cache.Cache, user_id int) (string, error) {
+	gen, err := c.Generation(homeFeedGeneration)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("home-feed:%d:%d", gen, user_id), nil
+}
+
+// InvalidateHomeFeeds removes the cached home feeds of the users
+func InvalidateHomeFeeds(c *cache.Cache, user_ids ...int) error {
+	keys := []string{}
+	for _, user_id := range user_ids {
+		key, err := homeFeedKey(c, user_id)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, key)
+	}
+	return c.Delete(keys...)
+}
+
+// InvalidateAllHomeFeeds invalidates the cached home feeds of every user, e.g. when a book changes
+func InvalidateAllHomeFeeds(c *cache.Cache) error {
+	return c.Bump(homeFeedGeneration)
+}
+
+// homeFeed returns the home feed of the user from the cache, building and caching it when it is missing.
+// Cache errors are only logged so that the home page still works when the cache is down.
+func (m *Repository) homeFeed(user_id int) (*models.HomeFeed, error) {
+	key, err := homeFeedKey(m.Cache, user_id)
+	if err != nil {
+		m.App.ErrorLog.Println(err)
+		return m.buildHomeFeed(user_id)
+	}
+	feed := &models.HomeFeed{}
+	found, err := m.Cache.Get(key, feed)
+	if err != nil {
+		m.App.ErrorLog.Println(err)
+	}
+	if found {
+		return feed, nil
+	}
+	feed, err = m.buildHomeFeed(user_id)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.Cache.Set(key, feed, homeFeedTTL); err != nil {
+		m.App.ErrorLog.Println(err)
+	}
+	return feed, nil
+}
+
+// buildHomeFeed returns the new books of the authors the user follows, books of their favourite genres
+// inferred from their reviews and read list, and recent reviews of the books on their shelves
+func (m *Repository) buildHomeFeed(user_id int) (*models.HomeFeed, error) {
+	feed := &models.HomeFeed{
+		AuthorBooks:  []*models.FeedBook{},
+		Genres:       []string{},
+		GenreBooks:   []*models.FeedBook{},
+		ShelfReviews: []*models.FeedReview{},
+	}
+	authors, err := m.DB.GetAllFollowingsByUserId(user_id)
+	if err != nil {
+		return nil, err
+	}
+	if len(authors) > 0 {
+		author_ids := make([]int, len(authors))
+		for i, author := range authors {
+			author_ids[i] = author.ID
+		}
+		if feed.AuthorBooks, err = m.DB.FeedAuthorBooks(user_id, author_ids, homeFeedLimit); err != nil {
+			return nil, err
+		}
+	}
+	genres, err := m.DB.FavouriteGenres(user_id, favouriteGenresLimit)
+	if err != nil {
+		return nil, err
+	}
+	if len(genres) > 0 {
+		genre_ids := make([]int, len(genres))
+		for i, genre := range genres {
+			genre_ids[i] = genre.ID
+			feed.Genres = append(feed.Genres, genre.Title)
+		}
+		if feed.GenreBooks, err = m.DB.FeedGenreBooks(user_id, genre_ids, homeFeedLimit); err != nil {
+			return nil, err
+		}
+	}
+	if feed.ShelfReviews, err = m.DB.FeedShelfReviews(user_id, homeFeedLimit); err != nil {
+		return nil, err
+	}
+	return feed, nil
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/ishanshre/Book-Review-Platform/internals/helpers"
+)
+
+// NotificationsApi returns the notifications of the authenticated user.
+// The unread query parameter set to true returns only the unread notifications.
+func (m *Repository) NotificationsApi(w http.ResponseWriter, r *http.Request) {
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil {
+		limit = 10
+	}
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil {
+		page = 1
+	}
+	unreadOnly := r.URL.Query().Get("unread") == "true"
+	user_id := m.App.Session.GetInt(r.Context(), "user_id")
+	notifications, err := m.DB.GetNotificationsByUserID(user_id, limit, page, unreadOnly)
+	if err != nil {
+		helpers.StatusInternalServerError(w, "something went wrong")
+		return
+	}
+	helpers.ApiStatusOkData(w, notifications)
+}
+
+// MarkNotificationReadApi marks a notification of the authenticated user as read
+func (m *Repository) MarkNotificationReadApi(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		helpers.ClientError(w, http.StatusBadRequest)
+		return
+	}
+	user_id := m.App.Session.GetInt(r.Context(), "user_id")
+	if err := m.DB.MarkNotificationRead(id, user_id); err != nil {
+		helpers.StatusInternalServerError(w, "something went wrong")
+		return
+	}
+	helpers.ApiStatusOk(w, "notification marked as read")
+}
+
+// MarkAllNotificationsReadApi marks every notification of the authenticated user as read
+func (m *Repository) MarkAllNotificationsReadApi(w http.ResponseWriter, r *http.Request) {
+	user_id := m.App.Session.GetInt(r.Context(), "user_id")
+	if err := m.DB.MarkAllNotificationsRead(user_id); err != nil {
+		helpers.StatusInternalServerError(w, "something went wrong")
+		return
+	}
+	helpers.ApiStatusOk(w, "all notifications marked as read")
+}
@@ -1,9 +1,14 @@
 package handler
 
 import (
+	"database/sql"
 	"net/http"
 	"strconv"
+	"time"
 
+	"github.com/go-chi/chi/v5"
+	"github.com/ishanshre/Book-Review-Platform/internals/events"
+	"github.com/ishanshre/Book-Review-Platform/internals/forms"
 	"github.com/ishanshre/Book-Review-Platform/internals/helpers"
 	"github.com/ishanshre/Book-Review-Platform/internals/models"
 	"github.com/ishanshre/Book-Review-Platform/internals/render"
@@ -27,8 +32,13 @@ func (m *Repository) AllBooksFilterFromReadListApi(w http.ResponseWriter, r *htt
 	if sort == "" {
 		sort = "asc"
 	}
+	status := r.URL.Query().Get("status")
+	if status != "" && !containsString(models.ReadingStatuses, status) {
+		helpers.ApiError(w, http.StatusBadRequest, "invalid reading status")
+		return
+	}
 	user_id := m.App.Session.GetInt(r.Context(), "user_id")
-	filteredBooks, err := m.DB.GetAllBooksFromReadListByUserId(limit, page, user_id, searchKey, sort)
+	filteredBooks, err := m.DB.GetAllBooksFromReadListByUserId(limit, page, user_id, searchKey, sort, status)
 	if err != nil {
 		helpers.ServerError(w, err)
 		helpers.StatusInternalServerError(w, err.Error())
@@ -36,3 +46,220 @@ func (m *Repository) AllBooksFilterFromReadListApi(w http.ResponseWriter, r *htt
 	}
 	helpers.ApiStatusOkData(w, filteredBooks)
 }
+
+// ReadingProgressApi returns the reading status and progress of the book for the authenticated user
+func (m *Repository) ReadingProgressApi(w http.ResponseWriter, r *http.Request) {
+	book := m.readingBook(w, r)
+	if book == nil {
+		return
+	}
+	user_id := m.App.Session.GetInt(r.Context(), "user_id")
+	readList, err := m.DB.GetReadListByID(user_id, book.ID)
+	if err != nil && err != sql.ErrNoRows {
+		helpers.StatusInternalServerError(w, "something went wrong")
+		return
+	}
+	m.writeReadingProgress(w, book, readList)
+}
+
+// PostReadingStatusApi changes the reading status of the book for the authenticated user.
+// The book is added to the read list if it is not in it. Finishing the book stores the read
+// and reading a finished book again starts a re-read from the first page.
+func (m *Repository) PostReadingStatusApi(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		helpers.ClientError(w, http.StatusBadRequest)
+		return
+	}
+	status := r.Form.Get("status")
+	if !containsString(models.ReadingStatuses, status) {
+		helpers.WriteJson(w, http.StatusUnprocessableEntity, map[string]interface{}{
+			"status": "error",
+			"errors": map[string][]string{"status": {"Invalid reading status"}},
+		})
+		return
+	}
+	book := m.readingBook(w, r)
+	if book == nil {
+		return
+	}
+	readList := m.readingReadList(w, r, book)
+	if readList == nil {
+		return
+	}
+	now := time.Now()
+	read := changeReadingStatus(readList, status, book.Paperback, now)
+	if err := m.updateReadingProgress(readList, read, now); err != nil {
+		helpers.StatusInternalServerError(w, "something went wrong")
+		return
+	}
+	m.publish(r.Context(), events.UserLibraryChanged{UserID: readList.UserID})
+	if read != nil {
+		m.publish(r.Context(), events.BookFinished{Read: read})
+	}
+	m.writeReadingProgress(w, book, readList)
+}
+
+// PostReadingProgressApi updates the current page of the book for the authenticated user.
+// Updating the page starts reading the book and reaching the last page finishes it.
+func (m *Repository) PostReadingProgressApi(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		helpers.ClientError(w, http.StatusBadRequest)
+		return
+	}
+	book := m.readingBook(w, r)
+	if book == nil {
+		return
+	}
+	form := forms.New(r.PostForm)
+	form.Required("current_page")
+	current_page, err := strconv.Atoi(r.Form.Get("current_page"))
+	if err != nil || current_page < 0 {
+		form.Errors.Add("current_page", "Page must be a positive number")
+	} else if book.Paperback > 0 && current_page > book.Paperback {
+		form.Errors.Add("current_page", "Page must not be greater than the number of pages")
+	}
+	if !form.Valid() {
+		helpers.WriteJson(w, http.StatusUnprocessableEntity, map[string]interface{}{
+			"status": "error",
+			"errors": form.Errors,
+		})
+		return
+	}
+	readList := m.readingReadList(w, r, book)
+	if readList == nil {
+		return
+	}
+	now := time.Now()
+	var read *models.ReadListRead
+	if readList.Status != models.ReadingCurrently {
+		read = changeReadingStatus(readList, models.ReadingCurrently, book.Paperback, now)
+	}
+	readList.CurrentPage = current_page
+	if book.Paperback > 0 && current_page == book.Paperback {
+		read = changeReadingStatus(readList, models.ReadingFinished, book.Paperback, now)
+	}
+	if err := m.updateReadingProgress(readList, read, now); err != nil {
+		helpers.StatusInternalServerError(w, "something went wrong")
+		return
+	}
+	if read != nil {
+		m.publish(r.Context(), events.BookFinished{Read: read})
+	}
+	m.writeReadingProgress(w, book, readList)
+}
+
+// readingBook returns the book of the id url param.
+// It writes the error response and returns nil when the book does not exists.
+func (m *Repository) readingBook(w http.ResponseWriter, r *http.Request) *models.Book {
+	book_id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		helpers.ClientError(w, http.StatusBadRequest)
+		return nil
+	}
+	book, err := m.DB.GetBookByID(book_id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			helpers.ApiError(w, http.StatusNotFound, "book does not exists")
+			return nil
+		}
+		helpers.StatusInternalServerError(w, "something went wrong")
+		return nil
+	}
+	return book
+}
+
+// readingReadList returns the read list record of the book for the authenticated user, adding the book to the read list if needed.
+// It writes the error response and returns nil when something goes wrong.
+func (m *Repository) readingReadList(w http.ResponseWriter, r *http.Request, book *models.Book) *models.ReadList {
+	user_id := m.App.Session.GetInt(r.Context(), "user_id")
+	readList, err := m.DB.GetReadListByID(user_id, book.ID)
+	if err == sql.ErrNoRows {
+		readList = &models.ReadList{
+			UserID:    user_id,
+			BookID:    book.ID,
+			CreatedAt: time.Now(),
+			Status:    models.ReadingWantToRead,
+		}
+		if err = m.DB.InsertReadList(readList); err == nil {
+			m.publish(r.Context(), events.UserLibraryChanged{UserID: user_id})
+		}
+	}
+	if err != nil {
+		helpers.StatusInternalServerError(w, "something went wrong")
+		return nil
+	}
+	return readList
+}
+
+// updateReadingProgress stores the reading progress with the finished read if there is one
+func (m *Repository) updateReadingProgress(readList *models.ReadList, read *models.ReadListRead, now time.Time) error {
+	readList.UpdatedAt = &now
+	if read == nil {
+		return m.DB.UpdateReadingProgress(readList)
+	}
+	return m.DB.UpdateReadingProgress(readList, read)
+}
+
+// writeReadingProgress writes the reading progress of the book, readList is nil when the book is not in the read list
+func (m *Repository) writeReadingProgress(w http.ResponseWriter, book *models.Book, readList *models.ReadList) {
+	progress := &models.ReadingProgress{
+		Paperback: book.Paperback,
+	}
+	if readList != nil {
+		read_count, err := m.DB.ReadListReadCount(readList.UserID, book.ID)
+		if err != nil {
+			helpers.StatusInternalServerError(w, "something went wrong")
+			return
+		}
+		progress.InReadList = true
+		progress.Status = readList.Status
+		progress.CurrentPage = readList.CurrentPage
+		progress.StartedAt = readList.StartedAt
+		progress.FinishedAt = readList.FinishedAt
+		progress.ReadCount = read_count
+		if book.Paperback > 0 {
+			progress.Percent = readList.CurrentPage * 100 / book.Paperback
+		}
+	}
+	helpers.ApiStatusOkData(w, progress)
+}
+
+// changeReadingStatus moves the read list to the status and sets its dates and current page.
+// It returns the finished read to store when the book is finished, otherwise nil.
+func changeReadingStatus(readList *models.ReadList, status string, paperback int, now time.Time) *models.ReadListRead {
+	previous := readList.Status
+	readList.Status = status
+	switch status {
+	case models.ReadingWantToRead:
+		readList.CurrentPage = 0
+		readList.StartedAt = nil
+		readList.FinishedAt = nil
+	case models.ReadingCurrently:
+		// reading a finished book again is a re-read that starts from the first page
+		if previous == models.ReadingFinished {
+			readList.CurrentPage = 0
+			readList.StartedAt = nil
+		}
+		if readList.StartedAt == nil {
+			readList.StartedAt = &now
+		}
+		readList.FinishedAt = nil
+	case models.ReadingFinished:
+		if previous == models.ReadingFinished {
+			return nil
+		}
+		if paperback > 0 {
+			readList.CurrentPage = paperback
+		}
+		readList.FinishedAt = &now
+		return &models.ReadListRead{
+			UserID:     readList.UserID,
+			BookID:     readList.BookID,
+			StartedAt:  readList.StartedAt,
+			FinishedAt: now,
+		}
+	case models.ReadingAbandoned:
+		readList.FinishedAt = nil
+	}
+	return nil
+}
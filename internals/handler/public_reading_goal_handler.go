@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/ishanshre/Book-Review-Platform/internals/forms"
+	"github.com/ishanshre/Book-Review-Platform/internals/helpers"
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+	"github.com/ishanshre/Book-Review-Platform/internals/render"
+)
+
+// maxReadingGoalTarget is the largest number of books a reading goal can target
+const maxReadingGoalTarget = 1000
+
+// readingChallengeLimit is the number of users on a page of the leaderboard
+const readingChallengeLimit = 25
+
+// currentReadingGoal returns the reading goal of the user for the current year, or nil when the user has none
+func (m *Repository) currentReadingGoal(user_id int) (*models.ReadingGoal, error) {
+	goal, err := m.DB.GetReadingGoal(user_id, time.Now().Year())
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return goal, err
+}
+
+// PostReadingGoal sets the reading goal of the authenticated user for the current year
+func (m *Repository) PostReadingGoal(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	target, err := strconv.Atoi(r.Form.Get("target"))
+	if err != nil || target < 1 || target > maxReadingGoalTarget {
+		m.App.Session.Put(r.Context(), "error", "Reading goal must be between 1 and 1000 books")
+		http.Redirect(w, r, "/profile", http.StatusSeeOther)
+		return
+	}
+	goal := &models.ReadingGoal{
+		UserID:    m.App.Session.GetInt(r.Context(), "user_id"),
+		Year:      time.Now().Year(),
+		Target:    target,
+		Remind:    r.Form.Get("remind") == "on",
+		UpdatedAt: time.Now(),
+	}
+	if err := m.DB.UpsertReadingGoal(goal); err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	m.App.Session.Put(r.Context(), "flash", "Reading Goal Saved")
+	http.Redirect(w, r, "/profile", http.StatusSeeOther)
+}
+
+// PostDeleteReadingGoal removes the reading goal of the authenticated user for the current year
+func (m *Repository) PostDeleteReadingGoal(w http.ResponseWriter, r *http.Request) {
+	user_id := m.App.Session.GetInt(r.Context(), "user_id")
+	if err := m.DB.DeleteReadingGoal(user_id, time.Now().Year()); err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	m.App.Session.Put(r.Context(), "flash", "Reading Goal Removed")
+	http.Redirect(w, r, "/profile", http.StatusSeeOther)
+}
+
+// readingChallengeQuery returns the year and page of the leaderboard from the query params.
+// The current year and first page are used when they are missing.
+func readingChallengeQuery(r *http.Request) (year, page int) {
+	year, err := strconv.Atoi(r.URL.Query().Get("year"))
+	if err != nil || year < 1 {
+		year = time.Now().Year()
+	}
+	page, err = strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	return year, page
+}
+
+// ReadingChallenge renders the reading challenge leaderboard of the year
+func (m *Repository) ReadingChallenge(w http.ResponseWriter, r *http.Request) {
+	year, page := readingChallengeQuery(r)
+	leaderboard, err := m.DB.ReadingChallengeLeaderboard(year, readingChallengeLimit, page)
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	data := make(map[string]interface{})
+	data["leaderboard"] = leaderboard
+	data["previousYear"] = year - 1
+	data["nextYear"] = year + 1
+	data["previousPage"] = leaderboard.Page - 1
+	data["nextPage"] = leaderboard.Page + 1
+	data["currentYear"] = time.Now().Year()
+	if user_id := m.App.Session.GetInt(r.Context(), "user_id"); user_id > 0 {
+		goal, err := m.DB.GetReadingGoal(user_id, year)
+		if err != nil && err != sql.ErrNoRows {
+			helpers.ServerError(w, err)
+			return
+		}
+		data["goal"] = goal
+	}
+	render.Template(w, r, "public_reading_challenge.page.tmpl", &models.TemplateData{
+		Data: data,
+	})
+}
+
+// ReadingChallengeApi returns the reading challenge leaderboard of the year
+func (m *Repository) ReadingChallengeApi(w http.ResponseWriter, r *http.Request) {
+	year, page := readingChallengeQuery(r)
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil {
+		limit = readingChallengeLimit
+	}
+	leaderboard, err := m.DB.ReadingChallengeLeaderboard(year, limit, page)
+	if err != nil {
+		helpers.StatusInternalServerError(w, "something went wrong")
+		return
+	}
+	helpers.ApiStatusOkData(w, leaderboard)
+}
+
+// ReadingGoalUnsubscribe renders the page confirming turning off the reading goal reminders.
+// It writes a not found page when the token is unknown.
+func (m *Repository) ReadingGoalUnsubscribe(w http.ResponseWriter, r *http.Request) {
+	token := chi.URLParam(r, "token")
+	found, err := m.DB.ReadingGoalUnsubscribeTokenExists(token)
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	if !found {
+		helpers.PageNotFound(w, r, errors.New("invalid unsubscribe token"))
+		return
+	}
+	data := make(map[string]interface{})
+	data["token"] = token
+	data["unsubscribed"] = false
+	render.Template(w, r, "reading-goal-unsubscribe.page.tmpl", &models.TemplateData{
+		Data: data,
+		Form: forms.New(nil),
+	})
+}
+
+// PostReadingGoalUnsubscribe turns off the reminders of the reading goal owning the token.
+// Like the digest unsubscribe, the path is exempted from the csrf check for one-click unsubscribe requests.
+func (m *Repository) PostReadingGoalUnsubscribe(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		helpers.ClientError(w, http.StatusBadRequest)
+		return
+	}
+	token := chi.URLParam(r, "token")
+	found, err := m.DB.UnsubscribeReadingGoalReminders(token)
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	if !found {
+		helpers.PageNotFound(w, r, errors.New("invalid unsubscribe token"))
+		return
+	}
+	if r.Form.Get("List-Unsubscribe") == "One-Click" {
+		helpers.ApiStatusOk(w, "unsubscribed")
+		return
+	}
+	data := make(map[string]interface{})
+	data["token"] = token
+	data["unsubscribed"] = true
+	render.Template(w, r, "reading-goal-unsubscribe.page.tmpl", &models.TemplateData{
+		Data: data,
+		Form: forms.New(nil),
+	})
+}
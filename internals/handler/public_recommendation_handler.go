@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/ishanshre/Book-Review-Platform/internals/helpers"
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+)
+
+// alsoLikedLimit is the number of books in the readers also liked section of the book detail
+const alsoLikedLimit = 6
+
+// maxRecommendations is the largest number of recommendations returned at once
+const maxRecommendations = 50
+
+// recommendations returns the personalised recommendations of the user.
+// Popular books fill the list when the user has too few interactions for personalised ones.
+func (m *Repository) recommendations(user_id, limit int) ([]*models.RecommendedBook, error) {
+	books, err := m.DB.GetRecommendations(user_id, limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(books) >= limit {
+		return books, nil
+	}
+	popular, err := m.DB.PopularBooks(user_id, limit)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[int]bool)
+	for _, book := range books {
+		seen[book.ID] = true
+	}
+	for _, book := range popular {
+		if len(books) >= limit {
+			break
+		}
+		if !seen[book.ID] {
+			books = append(books, book)
+		}
+	}
+	return books, nil
+}
+
+// recommendationLimit returns the number of books asked for in the limit query param, ten by default
+func recommendationLimit(r *http.Request) int {
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		return 10
+	}
+	if limit > maxRecommendations {
+		return maxRecommendations
+	}
+	return limit
+}
+
+// RecommendationsApi returns the books recommended to the authenticated user
+func (m *Repository) RecommendationsApi(w http.ResponseWriter, r *http.Request) {
+	limit := recommendationLimit(r)
+	user_id := m.App.Session.GetInt(r.Context(), "user_id")
+	books, err := m.recommendations(user_id, limit)
+	if err != nil {
+		helpers.StatusInternalServerError(w, "something went wrong")
+		return
+	}
+	helpers.ApiStatusOkData(w, books)
+}
+
+// SimilarBooksApi returns the books with the most similar genres, authors, languages and description to the book.
+// Unlike the readers also liked section, it works for new books nobody reviewed or listed yet.
+func (m *Repository) SimilarBooksApi(w http.ResponseWriter, r *http.Request) {
+	book_id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		helpers.ClientError(w, http.StatusBadRequest)
+		return
+	}
+	book, err := m.DB.GetBookByID(book_id)
+	if err != nil && err != sql.ErrNoRows {
+		helpers.StatusInternalServerError(w, "something went wrong")
+		return
+	}
+	if err == sql.ErrNoRows || !book.IsActive {
+		helpers.ApiError(w, http.StatusNotFound, "book does not exists")
+		return
+	}
+	books, err := m.DB.GetContentSimilarBooks(book.ID, recommendationLimit(r))
+	if err != nil {
+		helpers.StatusInternalServerError(w, "something went wrong")
+		return
+	}
+	helpers.ApiStatusOkData(w, books)
+}
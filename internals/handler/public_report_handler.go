@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/ishanshre/Book-Review-Platform/internals/events"
+	"github.com/ishanshre/Book-Review-Platform/internals/forms"
+	"github.com/ishanshre/Book-Review-Platform/internals/helpers"
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+)
+
+// reportFromForm builds the report of the authenticated user from the posted reason and details.
+// It writes the errors and returns nil when the form is not valid.
+func (m *Repository) reportFromForm(w http.ResponseWriter, r *http.Request) *models.Report {
+	if err := r.ParseForm(); err != nil {
+		helpers.ClientError(w, http.StatusBadRequest)
+		return nil
+	}
+	form := forms.New(r.PostForm)
+	form.Required("reason")
+	form.MaxLength("details", 1000)
+	reason := r.Form.Get("reason")
+	if reason != "" && !containsString(models.ReportReasons, reason) {
+		form.Errors.Add("reason", "Select a valid reason")
+	}
+	if !form.Valid() {
+		helpers.WriteJson(w, http.StatusUnprocessableEntity, map[string]interface{}{
+			"status": "error",
+			"errors": form.Errors,
+		})
+		return nil
+	}
+	return &models.Report{
+		ReporterID: m.App.Session.GetInt(r.Context(), "user_id"),
+		Reason:     reason,
+		Details:    r.Form.Get("details"),
+		CreatedAt:  time.Now(),
+	}
+}
+
+// fileReport stores the report and hides its target when the report hide threshold is reached.
+// It writes the error and returns false when the report is not stored.
+func (m *Repository) fileReport(w http.ResponseWriter, r *http.Request, report *models.Report) bool {
+	if report.TargetUserID == report.ReporterID {
+		helpers.ApiError(w, http.StatusForbidden, "you cannot report your own "+report.TargetType)
+		return false
+	}
+	exists, err := m.DB.ReportExists(report.TargetType, report.TargetID, report.ReporterID)
+	if err != nil {
+		helpers.StatusInternalServerError(w, "something went wrong")
+		return false
+	}
+	if exists {
+		helpers.ApiError(w, http.StatusConflict, "you have already reported this "+report.TargetType)
+		return false
+	}
+	if err := m.DB.InsertReport(report); err != nil {
+		helpers.StatusInternalServerError(w, "something went wrong")
+		return false
+	}
+	if err := m.hideReportedContent(r.Context(), report); err != nil {
+		m.App.ErrorLog.Printf("hiding reported %s %d: %s", report.TargetType, report.TargetID, err)
+	}
+	return true
+}
+
+// hideReportedContent hides the review or comment once its open reports reach the report hide threshold.
+// It stays hidden until a moderator dismisses the reports.
+// Content that is already hidden is left alone so dismissing the reports does not show it.
+func (m *Repository) hideReportedContent(ctx context.Context, report *models.Report) error {
+	if m.App.ReportHideThreshold <= 0 {
+		return nil
+	}
+	count, err := m.DB.CountOpenReports(report.TargetType, report.TargetID)
+	if err != nil {
+		return err
+	}
+	if count < m.App.ReportHideThreshold {
+		return nil
+	}
+	if report.TargetType == models.ReportTargetComment {
+		comment, err := m.DB.GetReviewCommentByID(report.TargetID)
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !comment.IsActive || comment.IsDeleted {
+			return nil
+		}
+		if err := m.DB.SetReviewCommentActive(comment.ID, false); err != nil {
+			return err
+		}
+	} else {
+		review, err := m.DB.GetReviewByID(report.TargetID)
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !review.IsActive {
+			return nil
+		}
+		if err := m.setReviewActive(ctx, review.ID, false); err != nil {
+			return err
+		}
+	}
+	return m.DB.MarkReportsHidTarget(report.TargetType, report.TargetID)
+}
+
+// setReviewActive activates or deactivates the review and publishes the change.
+// Deleted reviews are ignored.
+func (m *Repository) setReviewActive(ctx context.Context, id int, is_active bool) error {
+	review, err := m.DB.GetReviewByID(id)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if review.IsActive == is_active {
+		return nil
+	}
+	if err := m.DB.SetReviewActive(id, is_active); err != nil {
+		return err
+	}
+	review.IsActive = is_active
+	review.UpdatedAt = time.Now()
+	if is_active {
+		return m.publishActivatedReview(ctx, review)
+	}
+	m.publish(ctx, events.ReviewUpdated{Review: review})
+	m.publish(ctx, events.ReviewDeactivated{Review: review})
+	return nil
+}
+
+// publishActivatedReview publishes the review that was just activated.
+// A review held since it was added is published as created, any other review as updated.
+func (m *Repository) publishActivatedReview(ctx context.Context, review *models.Review) error {
+	first, err := m.DB.PublishReview(review.ID)
+	if err != nil {
+		return err
+	}
+	if first {
+		m.publish(ctx, events.ReviewCreated{Review: review})
+	} else {
+		m.publish(ctx, events.ReviewUpdated{Review: review})
+	}
+	return nil
+}
+
+// ReportReviewApi reports the review to the moderators with the posted reason and details
+func (m *Repository) ReportReviewApi(w http.ResponseWriter, r *http.Request) {
+	review_id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		helpers.ClientError(w, http.StatusBadRequest)
+		return
+	}
+	review, err := m.DB.GetReviewByID(review_id)
+	if err != nil && err != sql.ErrNoRows {
+		helpers.StatusInternalServerError(w, "something went wrong")
+		return
+	}
+	if err == sql.ErrNoRows || !review.IsActive {
+		helpers.ApiError(w, http.StatusNotFound, "review does not exists")
+		return
+	}
+	report := m.reportFromForm(w, r)
+	if report == nil {
+		return
+	}
+	report.TargetType = models.ReportTargetReview
+	report.TargetID = review.ID
+	report.TargetUserID = review.UserID
+	report.TargetBody = review.Body
+	if !m.fileReport(w, r, report) {
+		return
+	}
+	helpers.ApiStatusOk(w, "review reported to the moderators")
+}
+
+// ReportReviewCommentApi reports the comment to the moderators with the posted reason and details and flags it
+func (m *Repository) ReportReviewCommentApi(w http.ResponseWriter, r *http.Request) {
+	review := m.commentedReview(w, r)
+	if review == nil {
+		return
+	}
+	comment := m.reviewComment(w, r, review)
+	if comment == nil {
+		return
+	}
+	report := m.reportFromForm(w, r)
+	if report == nil {
+		return
+	}
+	report.TargetType = models.ReportTargetComment
+	report.TargetID = comment.ID
+	report.TargetUserID = comment.UserID
+	report.TargetBody = comment.Body
+	if !m.fileReport(w, r, report) {
+		return
+	}
+	if err := m.DB.FlagReviewComment(comment.ID); err != nil {
+		helpers.StatusInternalServerError(w, "something went wrong")
+		return
+	}
+	helpers.ApiStatusOk(w, "comment reported to the moderators")
+}
+
+// containsString returns true if the value is in the list
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -46,22 +46,26 @@ func (m *Repository) PostRequestBook(w http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
-	if err := m.DB.InsertRequestedBook(&requestedBook); err != nil {
-		helpers.ServerError(w, err)
-		return
-	}
 	user, err := m.DB.GetGlobalUserByIDAny(requested_by)
 	if err != nil {
 		helpers.ServerError(w, err)
 		return
 	}
 	msg := models.MailData{
-		To:      m.App.AdminEmail,
-		From:    user.Email,
-		Subject: fmt.Sprintf("Request for %s", requestedBook.BookTitle),
-		Content: fmt.Sprintf("Requesting for book %s by %s", requestedBook.BookTitle, requestedBook.Author),
+		To:       m.App.AdminEmail,
+		From:     user.Email,
+		Subject:  fmt.Sprintf("Request for %s", requestedBook.BookTitle),
+		Template: "book_requested",
+		Data: map[string]interface{}{
+			"Username":  user.Username,
+			"BookTitle": requestedBook.BookTitle,
+			"Author":    requestedBook.Author,
+		},
+	}
+	if err := m.DB.InsertRequestedBook(&requestedBook, &msg); err != nil {
+		helpers.ServerError(w, err)
+		return
 	}
-	m.App.MailChan <- msg
 	m.App.Session.Put(r.Context(), "flash", "Book Successfully requested")
 	http.Redirect(w, r, "/request-book", http.StatusSeeOther)
 }
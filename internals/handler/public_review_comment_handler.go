@@ -0,0 +1,238 @@
+package handler
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/ishanshre/Book-Review-Platform/internals/events"
+	"github.com/ishanshre/Book-Review-Platform/internals/forms"
+	"github.com/ishanshre/Book-Review-Platform/internals/helpers"
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+)
+
+// commentedReview returns the review of the review_id url param when it is an active review of the book of the isbn url param.
+// It writes the error response and returns nil otherwise.
+func (m *Repository) commentedReview(w http.ResponseWriter, r *http.Request) *models.Review {
+	isbn, err := strconv.ParseInt(chi.URLParam(r, "isbn"), 10, 64)
+	if err != nil {
+		helpers.ClientError(w, http.StatusBadRequest)
+		return nil
+	}
+	review_id, err := strconv.Atoi(chi.URLParam(r, "review_id"))
+	if err != nil {
+		helpers.ClientError(w, http.StatusBadRequest)
+		return nil
+	}
+	book, err := m.DB.GetBookByISBN(isbn)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			helpers.ApiError(w, http.StatusNotFound, "book does not exists")
+			return nil
+		}
+		helpers.StatusInternalServerError(w, "something went wrong")
+		return nil
+	}
+	review, err := m.DB.GetReviewByID(review_id)
+	if err != nil && err != sql.ErrNoRows {
+		helpers.StatusInternalServerError(w, "something went wrong")
+		return nil
+	}
+	if err == sql.ErrNoRows || review.BookID != book.ID || !review.IsActive {
+		helpers.ApiError(w, http.StatusNotFound, "review does not exists")
+		return nil
+	}
+	return review
+}
+
+// reviewComment returns the comment of the comment_id url param when it belongs to the review and is not deleted.
+// It writes the error response and returns nil otherwise.
+func (m *Repository) reviewComment(w http.ResponseWriter, r *http.Request, review *models.Review) *models.ReviewComment {
+	comment_id, err := strconv.Atoi(chi.URLParam(r, "comment_id"))
+	if err != nil {
+		helpers.ClientError(w, http.StatusBadRequest)
+		return nil
+	}
+	comment, err := m.DB.GetReviewCommentByID(comment_id)
+	if err != nil && err != sql.ErrNoRows {
+		helpers.StatusInternalServerError(w, "something went wrong")
+		return nil
+	}
+	if err == sql.ErrNoRows || comment.ReviewID != review.ID || comment.IsDeleted {
+		helpers.ApiError(w, http.StatusNotFound, "comment does not exists")
+		return nil
+	}
+	return comment
+}
+
+// reviewCommentThread nests the replies under their parent comment.
+// Hidden and deleted comments are kept without their content only when they have replies to show.
+func reviewCommentThread(comments []*models.ReviewComment) []*models.ReviewComment {
+	byID := make(map[int]*models.ReviewComment, len(comments))
+	for _, comment := range comments {
+		byID[comment.ID] = comment
+	}
+	thread := []*models.ReviewComment{}
+	for _, comment := range comments {
+		if parent, ok := byID[comment.ParentID]; ok {
+			parent.Replies = append(parent.Replies, comment)
+			continue
+		}
+		thread = append(thread, comment)
+	}
+	return pruneReviewComments(thread)
+}
+
+// pruneReviewComments removes the hidden and deleted comments without replies and clears the content of the rest
+func pruneReviewComments(comments []*models.ReviewComment) []*models.ReviewComment {
+	visible := []*models.ReviewComment{}
+	for _, comment := range comments {
+		comment.Replies = pruneReviewComments(comment.Replies)
+		if comment.IsDeleted || !comment.IsActive {
+			if len(comment.Replies) == 0 {
+				continue
+			}
+			comment.Body = ""
+			comment.UserID = 0
+			comment.Username = ""
+		}
+		comment.IsFlagged = false
+		visible = append(visible, comment)
+	}
+	return visible
+}
+
+// validateReviewComment validates the posted comment body and writes the errors when the form is not valid
+func validateReviewComment(w http.ResponseWriter, r *http.Request) bool {
+	form := forms.New(r.PostForm)
+	form.Required("body")
+	form.MaxLength("body", 5000)
+	if !form.Valid() {
+		helpers.WriteJson(w, http.StatusUnprocessableEntity, map[string]interface{}{
+			"status": "error",
+			"errors": form.Errors,
+		})
+		return false
+	}
+	return true
+}
+
+// ReviewCommentsApi returns the comments of the review as a thread of nested replies
+func (m *Repository) ReviewCommentsApi(w http.ResponseWriter, r *http.Request) {
+	review := m.commentedReview(w, r)
+	if review == nil {
+		return
+	}
+	comments, err := m.DB.GetReviewCommentsByReviewID(review.ID)
+	if err != nil {
+		helpers.StatusInternalServerError(w, "something went wrong")
+		return
+	}
+	helpers.ApiStatusOkData(w, reviewCommentThread(comments))
+}
+
+// PostReviewCommentApi adds a comment on the review.
+// The parent_id form value makes the comment a reply to another comment of the review.
+func (m *Repository) PostReviewCommentApi(w http.ResponseWriter, r *http.Request) {
+	review := m.commentedReview(w, r)
+	if review == nil {
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		helpers.ClientError(w, http.StatusBadRequest)
+		return
+	}
+	if !validateReviewComment(w, r) {
+		return
+	}
+	user_id := m.App.Session.GetInt(r.Context(), "user_id")
+	comment := &models.ReviewComment{
+		ReviewID:  review.ID,
+		UserID:    user_id,
+		Body:      r.Form.Get("body"),
+		IsActive:  true,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if r.Form.Get("parent_id") != "" {
+		parent_id, err := strconv.Atoi(r.Form.Get("parent_id"))
+		if err != nil {
+			helpers.ClientError(w, http.StatusBadRequest)
+			return
+		}
+		parent, err := m.DB.GetReviewCommentByID(parent_id)
+		if err != nil && err != sql.ErrNoRows {
+			helpers.StatusInternalServerError(w, "something went wrong")
+			return
+		}
+		if err == sql.ErrNoRows || parent.ReviewID != review.ID || parent.IsDeleted || !parent.IsActive {
+			helpers.ApiError(w, http.StatusNotFound, "comment does not exists")
+			return
+		}
+		comment.ParentID = parent.ID
+	}
+	if err := m.DB.InsertReviewComment(comment); err != nil {
+		helpers.StatusInternalServerError(w, "something went wrong")
+		return
+	}
+	comment.Username = m.App.Session.GetString(r.Context(), "username")
+	m.publish(r.Context(), events.ReviewCommentCreated{Comment: comment, Review: review})
+	helpers.WriteJson(w, http.StatusCreated, helpers.Message{
+		Status: "success",
+		Data:   comment,
+	})
+}
+
+// UpdateReviewCommentApi edits the comment of the authenticated user
+func (m *Repository) UpdateReviewCommentApi(w http.ResponseWriter, r *http.Request) {
+	review := m.commentedReview(w, r)
+	if review == nil {
+		return
+	}
+	comment := m.reviewComment(w, r, review)
+	if comment == nil {
+		return
+	}
+	if comment.UserID != m.App.Session.GetInt(r.Context(), "user_id") {
+		helpers.ApiError(w, http.StatusForbidden, "you can only edit your own comment")
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		helpers.ClientError(w, http.StatusBadRequest)
+		return
+	}
+	if !validateReviewComment(w, r) {
+		return
+	}
+	comment.Body = r.Form.Get("body")
+	comment.UpdatedAt = time.Now()
+	if err := m.DB.UpdateReviewCommentBody(comment); err != nil {
+		helpers.StatusInternalServerError(w, "something went wrong")
+		return
+	}
+	helpers.ApiStatusOkData(w, comment)
+}
+
+// DeleteReviewCommentApi deletes the comment of the authenticated user.
+// The replies are kept and the comment is shown as deleted above them.
+func (m *Repository) DeleteReviewCommentApi(w http.ResponseWriter, r *http.Request) {
+	review := m.commentedReview(w, r)
+	if review == nil {
+		return
+	}
+	comment := m.reviewComment(w, r, review)
+	if comment == nil {
+		return
+	}
+	if comment.UserID != m.App.Session.GetInt(r.Context(), "user_id") {
+		helpers.ApiError(w, http.StatusForbidden, "you can only delete your own comment")
+		return
+	}
+	if err := m.DB.MarkReviewCommentDeleted(comment.ID); err != nil {
+		helpers.StatusInternalServerError(w, "something went wrong")
+		return
+	}
+	helpers.ApiStatusOk(w, "comment deleted")
+}
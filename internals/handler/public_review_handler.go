@@ -8,10 +8,13 @@ import (
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/ishanshre/Book-Review-Platform/internals/contentfilter"
+	"github.com/ishanshre/Book-Review-Platform/internals/events"
 	"github.com/ishanshre/Book-Review-Platform/internals/forms"
 	"github.com/ishanshre/Book-Review-Platform/internals/helpers"
 	"github.com/ishanshre/Book-Review-Platform/internals/models"
 	"github.com/ishanshre/Book-Review-Platform/internals/render"
+	"github.com/ishanshre/Book-Review-Platform/internals/sanitize"
 )
 
 func (m *Repository) PublicCreateReview(w http.ResponseWriter, r *http.Request) {
@@ -90,11 +93,33 @@ func (m *Repository) PostPublicCreateReview(w http.ResponseWriter, r *http.Reque
 		})
 		return
 	}
+	decision, err := m.checkContent(models.ContentFilterReview, user_id, body)
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	if decision.Verdict == contentfilter.Reject {
+		m.logContentFilter(r, models.ContentFilterReview, 0, user_id, body, decision)
+		form.Errors.Add("body", "The review was rejected by the spam filter")
+		render.Template(w, r, "public_review_create.page.tmpl", &models.TemplateData{
+			Form: form,
+			Data: data,
+		})
+		return
+	}
+	review.IsActive = decision.Verdict == contentfilter.Allow
 	if err := m.DB.InsertReview(review); err != nil {
 		helpers.ServerError(w, err)
 		return
 	}
-	m.App.Session.Put(r.Context(), "flash", "Review/Rating added Successfull")
+	m.logContentFilter(r, models.ContentFilterReview, review.ID, user_id, body, decision)
+	// a held review is only published once a moderator approves it
+	if review.IsActive {
+		m.publish(r.Context(), events.ReviewCreated{Review: review})
+		m.App.Session.Put(r.Context(), "flash", "Review/Rating added Successfull")
+	} else {
+		m.App.Session.Put(r.Context(), "warning", "Review/Rating added and held for moderation")
+	}
 	http.Redirect(w, r, fmt.Sprintf("/books/%d", isbn), http.StatusSeeOther)
 
 }
@@ -125,6 +150,7 @@ func (m *Repository) PostPublicDeleteReview(w http.ResponseWriter, r *http.Reque
 		helpers.ServerError(w, err)
 		return
 	}
+	m.publish(r.Context(), events.ReviewDeleted{Review: review})
 	m.App.Session.Put(r.Context(), "flash", "Review/Rating Deleted Successfully")
 	http.Redirect(w, r, fmt.Sprintf("/books/%d", isbn), http.StatusSeeOther)
 }
@@ -219,11 +245,75 @@ func (m *Repository) PostPublicUpdateReview(w http.ResponseWriter, r *http.Reque
 		})
 		return
 	}
-	if err := m.DB.UpdateReviewBook(update_data); err != nil {
+	decision, err := m.checkContent(models.ContentFilterReview, user_id, update_data.Body)
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	if decision.Verdict == contentfilter.Reject {
+		m.logContentFilter(r, models.ContentFilterReview, review.ID, user_id, update_data.Body, decision)
+		form.Errors.Add("body", "The review was rejected by the spam filter")
+		render.Template(w, r, "public_review_update.page.tmpl", &models.TemplateData{
+			Data: data,
+			Form: form,
+		})
+		return
+	}
+	// a held edit hides the review until a moderator approves it, an allowed edit keeps the review as it was
+	update_data.IsActive = review.IsActive && decision.Verdict == contentfilter.Allow
+	if err := m.DB.UpdateReviewBook(update_data, reviewRevisions(review, update_data, user_id, models.ReviewEditorAuthor)...); err != nil {
 		helpers.ServerError(w, err)
 		return
 	}
-	m.App.Session.Put(r.Context(), "flash", "Review Updated")
+	m.logContentFilter(r, models.ContentFilterReview, review.ID, user_id, update_data.Body, decision)
+	update_data.CreatedAt = review.CreatedAt
+	// the body of a held edit is not published, the review is only published as deactivated
+	if update_data.IsActive {
+		m.publish(r.Context(), events.ReviewUpdated{Review: update_data})
+	} else if review.IsActive {
+		m.publish(r.Context(), events.ReviewDeactivated{Review: update_data})
+	}
+	if decision.Verdict == contentfilter.Allow {
+		m.App.Session.Put(r.Context(), "flash", "Review Updated")
+	} else {
+		m.App.Session.Put(r.Context(), "warning", "Review updated and held for moderation")
+	}
 	http.Redirect(w, r, fmt.Sprintf("/books/%d/reviews/%d/update", isbn, review_id), http.StatusSeeOther)
 
 }
+
+// ReviewPreviewApi returns the HTML of the posted review body as it will be shown on the book page
+func (m *Repository) ReviewPreviewApi(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		helpers.ClientError(w, http.StatusBadRequest)
+		return
+	}
+	form := forms.New(r.PostForm)
+	form.MaxLength("body", 10000)
+	if !form.Valid() {
+		helpers.WriteJson(w, http.StatusUnprocessableEntity, map[string]interface{}{
+			"status": "error",
+			"errors": form.Errors,
+		})
+		return
+	}
+	helpers.ApiStatusOkData(w, map[string]string{
+		"html": sanitize.Markdown(r.Form.Get("body")),
+	})
+}
+
+// reviewRevisions returns the revision of the edit of the review by the editor.
+// No revision is returned when the rating and body are unchanged.
+func reviewRevisions(previous, update *models.Review, editor_id int, editor_role string) []*models.ReviewRevision {
+	if previous.Rating == update.Rating && previous.Body == update.Body {
+		return nil
+	}
+	return []*models.ReviewRevision{{
+		ReviewID:   previous.ID,
+		Rating:     update.Rating,
+		Body:       update.Body,
+		EditorID:   editor_id,
+		EditorRole: editor_role,
+		CreatedAt:  update.UpdatedAt,
+	}}
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/ishanshre/Book-Review-Platform/internals/helpers"
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+)
+
+// votableReview returns the review of the id url param when the user is allowed to vote on it.
+// It writes the error response and returns nil when the review does not exists or belongs to the user.
+func (m *Repository) votableReview(w http.ResponseWriter, r *http.Request, user_id int) *models.Review {
+	review_id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		helpers.ClientError(w, http.StatusBadRequest)
+		return nil
+	}
+	review, err := m.DB.GetReviewByID(review_id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			helpers.ApiError(w, http.StatusNotFound, "review does not exists")
+			return nil
+		}
+		helpers.StatusInternalServerError(w, "something went wrong")
+		return nil
+	}
+	if review.UserID == user_id {
+		helpers.ApiError(w, http.StatusForbidden, "you cannot vote on your own review")
+		return nil
+	}
+	return review
+}
+
+// ReviewVoteApi marks the review helpful or unhelpful for the authenticated user and returns the new counters.
+// Voting again replaces the previous vote of the user.
+func (m *Repository) ReviewVoteApi(w http.ResponseWriter, r *http.Request) {
+	vote := chi.URLParam(r, "vote")
+	if vote != models.ReviewVoteHelpful && vote != models.ReviewVoteUnhelpful {
+		helpers.ClientError(w, http.StatusBadRequest)
+		return
+	}
+	user_id := m.App.Session.GetInt(r.Context(), "user_id")
+	review := m.votableReview(w, r, user_id)
+	if review == nil {
+		return
+	}
+	if err := m.DB.UpsertReviewVote(&models.ReviewVote{
+		ReviewID:  review.ID,
+		UserID:    user_id,
+		IsHelpful: vote == models.ReviewVoteHelpful,
+		VotedAt:   time.Now(),
+	}); err != nil {
+		helpers.StatusInternalServerError(w, "something went wrong")
+		return
+	}
+	count, err := m.DB.GetReviewVoteCount(review.ID, user_id)
+	if err != nil {
+		helpers.StatusInternalServerError(w, "something went wrong")
+		return
+	}
+	helpers.ApiStatusOkData(w, count)
+}
+
+// RemoveReviewVoteApi removes the vote of the authenticated user on the review and returns the new counters
+func (m *Repository) RemoveReviewVoteApi(w http.ResponseWriter, r *http.Request) {
+	user_id := m.App.Session.GetInt(r.Context(), "user_id")
+	review := m.votableReview(w, r, user_id)
+	if review == nil {
+		return
+	}
+	if err := m.DB.DeleteReviewVote(review.ID, user_id); err != nil {
+		helpers.StatusInternalServerError(w, "something went wrong")
+		return
+	}
+	count, err := m.DB.GetReviewVoteCount(review.ID, user_id)
+	if err != nil {
+		helpers.StatusInternalServerError(w, "something went wrong")
+		return
+	}
+	helpers.ApiStatusOkData(w, count)
+}
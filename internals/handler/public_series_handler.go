@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/ishanshre/Book-Review-Platform/internals/helpers"
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+	"github.com/ishanshre/Book-Review-Platform/internals/render"
+)
+
+// SeriesDetail renders the series page with its active books in the order of their positions
+func (m *Repository) SeriesDetail(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		helpers.PageNotFound(w, r, err)
+		return
+	}
+	series, err := m.DB.GetSeriesByID(id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			helpers.PageNotFound(w, r, err)
+			return
+		}
+		helpers.ServerError(w, err)
+		return
+	}
+	books, err := m.DB.GetSeriesBooks(id, false)
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	data := make(map[string]interface{})
+	data["series"] = series
+	data["books"] = books
+	render.Template(w, r, "public_series_detail.page.tmpl", &models.TemplateData{
+		Data: data,
+	})
+}
@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/ishanshre/Book-Review-Platform/internals/helpers"
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+)
+
+// sessionTouchInterval is the minimum time between two last seen updates of a session
+const sessionTouchInterval = time.Minute
+
+// Column sizes of the user_sessions table. Longer values are cut so that the insert does not fail.
+const (
+	userSessionIpLength        = 45
+	userSessionUserAgentLength = 500
+	userSessionDeviceLength    = 100
+)
+
+// userSession returns the current session token of the user with device and ip information of the request
+func (m *Repository) userSession(r *http.Request, user_id int) *models.UserSession {
+	ua := r.UserAgent()
+	return &models.UserSession{
+		UserID:    user_id,
+		Token:     m.App.Session.Token(r.Context()),
+		IpAddress: truncate(helpers.ClientIP(r), userSessionIpLength),
+		UserAgent: truncate(ua, userSessionUserAgentLength),
+		Device:    truncate(helpers.DeviceFromUserAgent(ua), userSessionDeviceLength),
+		CreatedAt: time.Now(),
+		LastSeen:  time.Now(),
+	}
+}
+
+// recordUserSession stores the current session token with device and ip information in the session index.
+// It must be called after the session token is renewed at login so that the stored token matches the cookie.
+func (m *Repository) recordUserSession(r *http.Request, user_id int) {
+	if err := m.DB.SaveUserSession(m.userSession(r, user_id)); err != nil {
+		m.App.ErrorLog.Println(err)
+		return
+	}
+	m.App.Session.Put(r.Context(), "last_seen", time.Now().Unix())
+}
+
+// TrackSession is a middleware that refreshes the last seen time of the authenticated user's session.
+// The database is only touched once every sessionTouchInterval per session.
+// A session missing from the session index was revoked or has expired, so it is destroyed instead of being indexed again.
+func (m *Repository) TrackSession(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if helpers.IsAuthenticated(r) {
+			last_seen := time.Unix(m.App.Session.GetInt64(r.Context(), "last_seen"), 0)
+			if time.Since(last_seen) > sessionTouchInterval {
+				found, err := m.DB.TouchUserSession(m.userSession(r, m.App.Session.GetInt(r.Context(), "user_id")))
+				switch {
+				case err != nil:
+					m.App.ErrorLog.Println(err)
+				case found:
+					m.App.Session.Put(r.Context(), "last_seen", time.Now().Unix())
+				default:
+					_ = m.App.Session.Destroy(r.Context())
+				}
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// revokeUserSessions removes the given sessions from the session store and the session index.
+// Users holding any of these sessions are logged out on their next request.
+func (m *Repository) revokeUserSessions(sessions []*models.UserSession) error {
+	for _, session := range sessions {
+		if err := m.App.Session.Store.Delete(session.Token); err != nil {
+			return err
+		}
+		if err := m.DB.DeleteUserSession(session.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// currentUserSessionID returns the id of the session making the request, or 0 if it is not indexed.
+func (m *Repository) currentUserSessionID(r *http.Request, sessions []*models.UserSession) int {
+	token := m.App.Session.Token(r.Context())
+	for _, session := range sessions {
+		if session.Token == token {
+			return session.ID
+		}
+	}
+	return 0
+}
+
+// PostRevokeUserSession logs out a single session of the authenticated user.
+// Revoking the current session behaves like a logout.
+func (m *Repository) PostRevokeUserSession(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		helpers.PageNotFound(w, r, err)
+		return
+	}
+	user_id := m.App.Session.GetInt(r.Context(), "user_id")
+	session, err := m.DB.GetUserSessionByID(id)
+	if err != nil {
+		helpers.PageNotFound(w, r, err)
+		return
+	}
+	if session.UserID != user_id {
+		helpers.Unauthorized(w)
+		return
+	}
+	if session.Token == m.App.Session.Token(r.Context()) {
+		m.Logout(w, r)
+		return
+	}
+	if err := m.revokeUserSessions([]*models.UserSession{session}); err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	m.App.Session.Put(r.Context(), "flash", "Session Revoked")
+	http.Redirect(w, r, "/profile", http.StatusSeeOther)
+}
+
+// PostRevokeAllUserSessions logs the authenticated user out of every device including the current one.
+func (m *Repository) PostRevokeAllUserSessions(w http.ResponseWriter, r *http.Request) {
+	user_id := m.App.Session.GetInt(r.Context(), "user_id")
+	sessions, err := m.DB.GetUserSessionsByUserID(user_id)
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	if err := m.revokeUserSessions(sessions); err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	_ = m.App.Session.Destroy(r.Context())
+	_ = m.App.Session.RenewToken(r.Context())
+	m.App.Session.Put(r.Context(), "flash", "Logged out from all devices")
+	http.Redirect(w, r, "/user/login", http.StatusSeeOther)
+}
@@ -0,0 +1,349 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/ishanshre/Book-Review-Platform/internals/events"
+	"github.com/ishanshre/Book-Review-Platform/internals/forms"
+	"github.com/ishanshre/Book-Review-Platform/internals/helpers"
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+	"github.com/ishanshre/Book-Review-Platform/internals/render"
+)
+
+// maxShelfBooks is the largest number of books a shelf can hold
+const maxShelfBooks = 500
+
+// shelfForm validates the posted shelf form and returns the shelf of the user filled from it
+func (m *Repository) shelfForm(r *http.Request, user_id, id int) (*forms.Form, *models.Shelf, error) {
+	form := forms.New(r.PostForm)
+	form.Required("name")
+	form.MaxLength("name", 100)
+	form.MaxLength("description", 1000)
+	shelf := &models.Shelf{
+		ID:          id,
+		UserID:      user_id,
+		Name:        strings.TrimSpace(r.Form.Get("name")),
+		Description: strings.TrimSpace(r.Form.Get("description")),
+		IsPublic:    r.Form.Get("is_public") == "on",
+		UpdatedAt:   time.Now(),
+	}
+	if shelf.Name != "" {
+		exists, err := m.DB.ShelfNameExists(user_id, shelf.Name, id)
+		if err != nil {
+			return nil, nil, err
+		}
+		if exists {
+			form.Errors.Add("name", "You already have a shelf with this name")
+		}
+	}
+	return form, shelf, nil
+}
+
+// Shelves renders the shelves of the authenticated user with the form to create a new one
+func (m *Repository) Shelves(w http.ResponseWriter, r *http.Request) {
+	m.renderShelves(w, r, forms.New(nil))
+}
+
+// renderShelves renders the shelves page of the authenticated user with the create shelf form
+func (m *Repository) renderShelves(w http.ResponseWriter, r *http.Request, form *forms.Form) {
+	user_id := m.App.Session.GetInt(r.Context(), "user_id")
+	shelves, err := m.DB.GetShelvesByUserID(user_id, 0)
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	data := make(map[string]interface{})
+	data["shelves"] = shelves
+	render.Template(w, r, "public_shelves.page.tmpl", &models.TemplateData{
+		Data: data,
+		Form: form,
+	})
+}
+
+// PostCreateShelf creates a new shelf for the authenticated user
+func (m *Repository) PostCreateShelf(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	user_id := m.App.Session.GetInt(r.Context(), "user_id")
+	form, shelf, err := m.shelfForm(r, user_id, 0)
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	if !form.Valid() {
+		m.renderShelves(w, r, form)
+		return
+	}
+	token, err := helpers.GenerateRandomToken(9)
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	shelf.ShareToken = token
+	shelf.CreatedAt = time.Now()
+	if err := m.DB.InsertShelf(shelf); err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	m.publish(r.Context(), events.ShelfCreated{Shelf: shelf})
+	m.App.Session.Put(r.Context(), "flash", "Shelf Created")
+	http.Redirect(w, r, fmt.Sprintf("/shelves/%s", shelf.ShareToken), http.StatusSeeOther)
+}
+
+// ownShelf returns the shelf of the id url param owned by the authenticated user.
+// It renders the not found page and returns nil when the user does not own the shelf.
+func (m *Repository) ownShelf(w http.ResponseWriter, r *http.Request) *models.Shelf {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		helpers.ClientError(w, http.StatusBadRequest)
+		return nil
+	}
+	shelf, err := m.DB.GetShelfByID(id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			helpers.PageNotFound(w, r, err)
+			return nil
+		}
+		helpers.ServerError(w, err)
+		return nil
+	}
+	if shelf.UserID != m.App.Session.GetInt(r.Context(), "user_id") {
+		helpers.PageNotFound(w, r, errors.New("shelf of another user"))
+		return nil
+	}
+	return shelf
+}
+
+// PostUpdateShelf updates the name, description and visibility of the shelf of the authenticated user
+func (m *Repository) PostUpdateShelf(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	previous := m.ownShelf(w, r)
+	if previous == nil {
+		return
+	}
+	form, shelf, err := m.shelfForm(r, previous.UserID, previous.ID)
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	if !form.Valid() {
+		m.renderShelf(w, r, previous, form)
+		return
+	}
+	if err := m.DB.UpdateShelf(shelf); err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	m.App.Session.Put(r.Context(), "flash", "Shelf Updated")
+	http.Redirect(w, r, fmt.Sprintf("/shelves/%s", previous.ShareToken), http.StatusSeeOther)
+}
+
+// PostDeleteShelf deletes the shelf of the authenticated user
+func (m *Repository) PostDeleteShelf(w http.ResponseWriter, r *http.Request) {
+	shelf := m.ownShelf(w, r)
+	if shelf == nil {
+		return
+	}
+	if err := m.DB.DeleteShelf(shelf.ID, shelf.UserID); err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	m.publish(r.Context(), events.UserLibraryChanged{UserID: shelf.UserID})
+	m.App.Session.Put(r.Context(), "flash", "Shelf Deleted")
+	http.Redirect(w, r, "/profile/shelves", http.StatusSeeOther)
+}
+
+// PostMoveShelfBook moves the book one place up or down on the shelf of the authenticated user
+func (m *Repository) PostMoveShelfBook(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	shelf := m.ownShelf(w, r)
+	if shelf == nil {
+		return
+	}
+	book_id, err := strconv.Atoi(chi.URLParam(r, "book_id"))
+	if err != nil {
+		helpers.ClientError(w, http.StatusBadRequest)
+		return
+	}
+	if err := m.DB.MoveShelfBook(shelf.ID, book_id, r.Form.Get("direction") == "up"); err != nil {
+		if err == sql.ErrNoRows {
+			helpers.PageNotFound(w, r, err)
+			return
+		}
+		helpers.ServerError(w, err)
+		return
+	}
+	http.Redirect(w, r, fmt.Sprintf("/shelves/%s#shelf-order", shelf.ShareToken), http.StatusSeeOther)
+}
+
+// viewableShelf returns the shelf of the token url param if it is public or owned by the authenticated user, otherwise nil
+func (m *Repository) viewableShelf(r *http.Request) (*models.Shelf, error) {
+	shelf, err := m.DB.GetShelfByToken(chi.URLParam(r, "token"))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !shelf.IsPublic && shelf.UserID != m.App.Session.GetInt(r.Context(), "user_id") {
+		return nil, nil
+	}
+	return shelf, nil
+}
+
+// ShelfDetail renders the shelf of the share url. Private shelves are only shown to their owner.
+func (m *Repository) ShelfDetail(w http.ResponseWriter, r *http.Request) {
+	shelf, err := m.viewableShelf(r)
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	if shelf == nil {
+		helpers.PageNotFound(w, r, errors.New("shelf not found"))
+		return
+	}
+	m.renderShelf(w, r, shelf, forms.New(nil))
+}
+
+// renderShelf renders the shelf page. The owner also gets the shelf order and the form to edit the shelf.
+func (m *Repository) renderShelf(w http.ResponseWriter, r *http.Request, shelf *models.Shelf, form *forms.Form) {
+	isOwner := shelf.UserID == m.App.Session.GetInt(r.Context(), "user_id")
+	data := make(map[string]interface{})
+	data["shelf"] = shelf
+	data["isOwner"] = isOwner
+	if isOwner {
+		books, err := m.DB.GetShelfBooks(shelf.ID, maxShelfBooks, 1, "", "")
+		if err != nil {
+			helpers.ServerError(w, err)
+			return
+		}
+		data["books"] = books.Books
+	}
+	render.Template(w, r, "public_shelf_detail.page.tmpl", &models.TemplateData{
+		Data: data,
+		Form: form,
+	})
+}
+
+// ShelfBooksApi returns the books on the shelf of the share url filtered like the read list
+func (m *Repository) ShelfBooksApi(w http.ResponseWriter, r *http.Request) {
+	shelf, err := m.viewableShelf(r)
+	if err != nil {
+		helpers.StatusInternalServerError(w, "something went wrong")
+		return
+	}
+	if shelf == nil {
+		helpers.ApiError(w, http.StatusNotFound, "shelf does not exists")
+		return
+	}
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil {
+		limit = 10
+	}
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil {
+		page = 1
+	}
+	filteredBooks, err := m.DB.GetShelfBooks(shelf.ID, limit, page, r.URL.Query().Get("search"), r.URL.Query().Get("sort"))
+	if err != nil {
+		helpers.StatusInternalServerError(w, "something went wrong")
+		return
+	}
+	helpers.ApiStatusOkData(w, filteredBooks)
+}
+
+// ShelvesApi returns the shelves of the authenticated user.
+// With the book_id query param each shelf tells whether it holds the book.
+func (m *Repository) ShelvesApi(w http.ResponseWriter, r *http.Request) {
+	book_id, err := strconv.Atoi(r.URL.Query().Get("book_id"))
+	if err != nil {
+		book_id = 0
+	}
+	user_id := m.App.Session.GetInt(r.Context(), "user_id")
+	shelves, err := m.DB.GetShelvesByUserID(user_id, book_id)
+	if err != nil {
+		helpers.StatusInternalServerError(w, "something went wrong")
+		return
+	}
+	helpers.ApiStatusOkData(w, shelves)
+}
+
+// ownShelfApi returns the shelf of the shelf_id url param owned by the authenticated user and the book id url param.
+// It writes the error response and returns nil when the user does not own the shelf.
+func (m *Repository) ownShelfApi(w http.ResponseWriter, r *http.Request) (*models.Shelf, int) {
+	book_id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		helpers.ClientError(w, http.StatusBadRequest)
+		return nil, 0
+	}
+	shelf_id, err := strconv.Atoi(chi.URLParam(r, "shelf_id"))
+	if err != nil {
+		helpers.ClientError(w, http.StatusBadRequest)
+		return nil, 0
+	}
+	shelf, err := m.DB.GetShelfByID(shelf_id)
+	if err != nil && err != sql.ErrNoRows {
+		helpers.StatusInternalServerError(w, "something went wrong")
+		return nil, 0
+	}
+	if shelf == nil || shelf.UserID != m.App.Session.GetInt(r.Context(), "user_id") {
+		helpers.ApiError(w, http.StatusNotFound, "shelf does not exists")
+		return nil, 0
+	}
+	return shelf, book_id
+}
+
+// AddBookToShelfApi puts the book at the end of the shelf of the authenticated user
+func (m *Repository) AddBookToShelfApi(w http.ResponseWriter, r *http.Request) {
+	shelf, book_id := m.ownShelfApi(w, r)
+	if shelf == nil {
+		return
+	}
+	if _, err := m.DB.GetBookByID(book_id); err != nil {
+		if err == sql.ErrNoRows {
+			helpers.ApiError(w, http.StatusNotFound, "book does not exists")
+			return
+		}
+		helpers.StatusInternalServerError(w, "something went wrong")
+		return
+	}
+	if shelf.BookCount >= maxShelfBooks {
+		helpers.ApiError(w, http.StatusConflict, fmt.Sprintf("a shelf can hold at most %d books", maxShelfBooks))
+		return
+	}
+	if err := m.DB.AddBookToShelf(shelf.ID, book_id); err != nil {
+		helpers.StatusInternalServerError(w, "something went wrong")
+		return
+	}
+	m.publish(r.Context(), events.UserLibraryChanged{UserID: shelf.UserID})
+	helpers.ApiStatusOk(w, "added to shelf")
+}
+
+// RemoveBookFromShelfApi takes the book off the shelf of the authenticated user
+func (m *Repository) RemoveBookFromShelfApi(w http.ResponseWriter, r *http.Request) {
+	shelf, book_id := m.ownShelfApi(w, r)
+	if shelf == nil {
+		return
+	}
+	if err := m.DB.RemoveBookFromShelf(shelf.ID, book_id); err != nil {
+		helpers.StatusInternalServerError(w, "something went wrong")
+		return
+	}
+	m.publish(r.Context(), events.UserLibraryChanged{UserID: shelf.UserID})
+	helpers.ApiStatusOk(w, "removed from shelf")
+}
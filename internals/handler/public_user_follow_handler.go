@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/ishanshre/Book-Review-Platform/internals/events"
+	"github.com/ishanshre/Book-Review-Platform/internals/helpers"
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+)
+
+// userByUsername returns the user of the username url param.
+// It writes the error response and returns nil when the user does not exist.
+func (m *Repository) userByUsername(w http.ResponseWriter, r *http.Request) *models.User {
+	user, err := m.DB.GetUserByUsername(chi.URLParam(r, "username"))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			helpers.ApiError(w, http.StatusNotFound, "user does not exists")
+			return nil
+		}
+		helpers.StatusInternalServerError(w, "something went wrong")
+		return nil
+	}
+	return user
+}
+
+// writeUserFollowStatus writes whether the authenticated user follows the user with the follow counts of the user
+func (m *Repository) writeUserFollowStatus(w http.ResponseWriter, r *http.Request, user *models.User) {
+	follower_id := m.App.Session.GetInt(r.Context(), "user_id")
+	following, err := m.DB.UserFollowExists(follower_id, user.ID)
+	if err != nil {
+		helpers.StatusInternalServerError(w, "something went wrong")
+		return
+	}
+	followers, followings, err := m.DB.UserFollowCounts(user.ID)
+	if err != nil {
+		helpers.StatusInternalServerError(w, "something went wrong")
+		return
+	}
+	helpers.ApiStatusOkData(w, &models.UserFollowStatus{
+		Username:   user.Username,
+		Following:  following,
+		Followers:  followers,
+		Followings: followings,
+	})
+}
+
+// UserFollowStatusApi returns whether the authenticated user follows the user
+func (m *Repository) UserFollowStatusApi(w http.ResponseWriter, r *http.Request) {
+	user := m.userByUsername(w, r)
+	if user == nil {
+		return
+	}
+	m.writeUserFollowStatus(w, r, user)
+}
+
+// FollowUserApi makes the authenticated user follow the user. Following a user twice does nothing.
+func (m *Repository) FollowUserApi(w http.ResponseWriter, r *http.Request) {
+	followee := m.userByUsername(w, r)
+	if followee == nil {
+		return
+	}
+	follower_id := m.App.Session.GetInt(r.Context(), "user_id")
+	if followee.ID == follower_id {
+		helpers.ApiError(w, http.StatusBadRequest, "you cannot follow yourself")
+		return
+	}
+	followed, err := m.DB.FollowUser(follower_id, followee.ID)
+	if err != nil {
+		helpers.StatusInternalServerError(w, "something went wrong")
+		return
+	}
+	if followed {
+		follower, err := m.DB.GetGlobalUserByIDAny(follower_id)
+		if err != nil {
+			helpers.StatusInternalServerError(w, "something went wrong")
+			return
+		}
+		m.publish(r.Context(), events.UserFollowed{Follower: follower, Followee: followee})
+	}
+	m.writeUserFollowStatus(w, r, followee)
+}
+
+// UnfollowUserApi makes the authenticated user stop following the user
+func (m *Repository) UnfollowUserApi(w http.ResponseWriter, r *http.Request) {
+	followee := m.userByUsername(w, r)
+	if followee == nil {
+		return
+	}
+	follower_id := m.App.Session.GetInt(r.Context(), "user_id")
+	if err := m.DB.UnfollowUser(follower_id, followee.ID); err != nil {
+		helpers.StatusInternalServerError(w, "something went wrong")
+		return
+	}
+	m.writeUserFollowStatus(w, r, followee)
+}
+
+// PostPrivacySettings updates who can see the activity and the public profile sections of the authenticated user
+func (m *Repository) PostPrivacySettings(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	visibility := r.Form.Get("activity_visibility")
+	if !containsString(models.Visibilities, visibility) {
+		m.App.Session.Put(r.Context(), "error", "Invalid activity visibility")
+		http.Redirect(w, r, "/profile", http.StatusSeeOther)
+		return
+	}
+	settings := &models.PrivacySettings{
+		UserID:              m.App.Session.GetInt(r.Context(), "user_id"),
+		ActivityVisibility:  visibility,
+		ShowReviews:         r.Form.Get("show_reviews") == "on",
+		ShowShelves:         r.Form.Get("show_shelves") == "on",
+		ShowStats:           r.Form.Get("show_stats") == "on",
+		ShowFollowedAuthors: r.Form.Get("show_followed_authors") == "on",
+		UpdatedAt:           time.Now(),
+	}
+	if err := m.DB.UpdatePrivacySettings(settings); err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	m.App.Session.Put(r.Context(), "flash", "Privacy Settings Updated")
+	http.Redirect(w, r, "/profile", http.StatusSeeOther)
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/ishanshre/Book-Review-Platform/internals/helpers"
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+	"github.com/ishanshre/Book-Review-Platform/internals/render"
+)
+
+// profileReviewsLimit is the number of reviews on a public profile
+const profileReviewsLimit = 10
+
+// profileGenresLimit is the number of favourite genres in the reading stats of a public profile
+const profileGenresLimit = 5
+
+// UserProfile renders the public profile of the user with their reviews, public shelves, reading stats and followed authors.
+// Every section is left out when the user hid it in their privacy settings, except for the user themself.
+func (m *Repository) UserProfile(w http.ResponseWriter, r *http.Request) {
+	user, err := m.DB.GetUserByUsername(chi.URLParam(r, "username"))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			helpers.PageNotFound(w, r, err)
+			return
+		}
+		helpers.ServerError(w, err)
+		return
+	}
+	viewer_id := m.App.Session.GetInt(r.Context(), "user_id")
+	isOwner := viewer_id == user.ID
+	privacy, err := m.DB.GetPrivacySettings(user.ID)
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	followers, followings, err := m.DB.UserFollowCounts(user.ID)
+	if err != nil {
+		helpers.ServerError(w, err)
+		return
+	}
+	data := make(map[string]interface{})
+	data["profile"] = user
+	data["isOwner"] = isOwner
+	data["followers"] = followers
+	data["followings"] = followings
+	data["showReviews"] = isOwner || privacy.ShowReviews
+	data["showShelves"] = isOwner || privacy.ShowShelves
+	data["showStats"] = isOwner || privacy.ShowStats
+	data["showFollowedAuthors"] = isOwner || privacy.ShowFollowedAuthors
+	if isOwner || privacy.ShowReviews {
+		reviews, err := m.DB.GetUserReviews(user.ID, profileReviewsLimit)
+		if err != nil {
+			helpers.ServerError(w, err)
+			return
+		}
+		data["reviews"] = reviews
+	}
+	if isOwner || privacy.ShowShelves {
+		shelves, err := m.DB.GetPublicShelvesByUserID(user.ID)
+		if err != nil {
+			helpers.ServerError(w, err)
+			return
+		}
+		data["shelves"] = shelves
+	}
+	if isOwner || privacy.ShowStats {
+		stats, err := m.DB.GetReadingStats(user.ID, profileGenresLimit)
+		if err != nil {
+			helpers.ServerError(w, err)
+			return
+		}
+		data["stats"] = stats
+	}
+	if isOwner || privacy.ShowFollowedAuthors {
+		authors, err := m.DB.GetAllFollowingsByUserId(user.ID)
+		if err != nil {
+			helpers.ServerError(w, err)
+			return
+		}
+		data["authors"] = authors
+	}
+	render.Template(w, r, "public_user_profile.page.tmpl", &models.TemplateData{
+		Data: data,
+	})
+}
@@ -52,6 +52,14 @@ func ApiStatusOkData(w http.ResponseWriter, data any) {
 	})
 }
 
+// ApiError writes the error message with the status code
+func ApiError(w http.ResponseWriter, status int, message string) {
+	WriteJson(w, status, Message{
+		Status:  "error",
+		Message: message,
+	})
+}
+
 func StatusInternalServerError(w http.ResponseWriter, message string) {
 	WriteJson(w, http.StatusInternalServerError, Message{
 		Status:  "error",
@@ -101,3 +109,9 @@ func IsAdmin(r *http.Request) bool {
 	access_level := app.Session.GetInt(r.Context(), "access_level")
 	return access_level == 1
 }
+
+// IsModerator returns true if authenticated user is moderator or admin else return false
+func IsModerator(r *http.Request) bool {
+	access_level := app.Session.GetInt(r.Context(), "access_level")
+	return access_level == 1 || access_level == 2
+}
@@ -1,18 +1,228 @@
 package helpers
 
-import "golang.org/x/crypto/bcrypt"
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
 
-// EncryptPassowd return the hashed password stirng and error
-func EncryptPassword(password string) (string, error) {
-	hashed, err := bcrypt.GenerateFromPassword([]byte(password), 14)
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ErrMismatchedPassword is returned when the password does not match the stored hash
+var ErrMismatchedPassword = errors.New("hashed password and password does not match")
+
+// ErrUnknownHashFormat is returned when no hasher can decode the stored hash
+var ErrUnknownHashFormat = errors.New("unknown password hash format")
+
+// Hasher is implemented by every password hashing algorithm.
+// Hashes are stored in a versioned format which carries the algorithm and its parameters,
+// so hashes created with older settings can still be verified.
+type Hasher interface {
+	// Hash returns the encoded hash of the password
+	Hash(password string) (string, error)
+	// Verify reports whether the password matches the encoded hash
+	Verify(encoded, password string) (bool, error)
+	// Matches reports whether the encoded hash was produced by this algorithm
+	Matches(encoded string) bool
+	// NeedsRehash reports whether the encoded hash uses different parameters than the hasher
+	NeedsRehash(encoded string) bool
+}
+
+// BcryptHasher hashes password using bcrypt with configurable cost.
+// Encoded format: $2a$<cost>$<salt+hash>
+type BcryptHasher struct {
+	Cost int
+}
+
+func (h *BcryptHasher) Hash(password string) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), h.Cost)
 	if err != nil {
 		return "", err
 	}
 	return string(hashed), nil
 }
 
-// CheckPassword checks if the hashed passwod and given password matches or not
+func (h *BcryptHasher) Verify(encoded, password string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+	if err == bcrypt.ErrMismatchedHashAndPassword {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (h *BcryptHasher) Matches(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2")
+}
+
+func (h *BcryptHasher) NeedsRehash(encoded string) bool {
+	cost, err := bcrypt.Cost([]byte(encoded))
+	if err != nil {
+		return true
+	}
+	return cost != h.Cost
+}
+
+// Argon2idHasher hashes password using argon2id.
+// Encoded format: $argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<hash>
+type Argon2idHasher struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// argon2idParams holds the parameters decoded from an encoded argon2id hash
+type argon2idParams struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+	salt        []byte
+	key         []byte
+}
+
+func (h *Argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, h.Iterations, h.Memory, h.Parallelism, h.KeyLength)
+	return fmt.Sprintf(
+		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		h.Memory,
+		h.Iterations,
+		h.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (h *Argon2idHasher) Verify(encoded, password string) (bool, error) {
+	p, err := decodeArgon2id(encoded)
+	if err != nil {
+		return false, err
+	}
+	key := argon2.IDKey([]byte(password), p.salt, p.iterations, p.memory, p.parallelism, uint32(len(p.key)))
+	return subtle.ConstantTimeCompare(key, p.key) == 1, nil
+}
+
+func (h *Argon2idHasher) Matches(encoded string) bool {
+	return strings.HasPrefix(encoded, "$argon2id$")
+}
+
+func (h *Argon2idHasher) NeedsRehash(encoded string) bool {
+	p, err := decodeArgon2id(encoded)
+	if err != nil {
+		return true
+	}
+	return p.memory != h.Memory ||
+		p.iterations != h.Iterations ||
+		p.parallelism != h.Parallelism ||
+		uint32(len(p.salt)) != h.SaltLength ||
+		uint32(len(p.key)) != h.KeyLength
+}
+
+// decodeArgon2id parses the encoded argon2id hash into its parameters
+func decodeArgon2id(encoded string) (*argon2idParams, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return nil, ErrUnknownHashFormat
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return nil, err
+	}
+	if version != argon2.Version {
+		return nil, fmt.Errorf("unsupported argon2 version %d", version)
+	}
+	p := &argon2idParams{}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.memory, &p.iterations, &p.parallelism); err != nil {
+		return nil, err
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return nil, err
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return nil, err
+	}
+	p.salt = salt
+	p.key = key
+	return p, nil
+}
+
+// NewPasswordHasher returns the hasher for the algorithm name.
+// Supported algorithms are "bcrypt" and "argon2id". Empty algorithm defaults to bcrypt.
+func NewPasswordHasher(algorithm string, bcryptCost int) (Hasher, error) {
+	switch algorithm {
+	case "", "bcrypt":
+		if bcryptCost < bcrypt.MinCost || bcryptCost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+		return &BcryptHasher{Cost: bcryptCost}, nil
+	case "argon2id":
+		return &Argon2idHasher{
+			Memory:      64 * 1024,
+			Iterations:  1,
+			Parallelism: 4,
+			SaltLength:  16,
+			KeyLength:   32,
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported password hashing algorithm: %s", algorithm)
+}
+
+// passwordHasher is the hasher used for new passwords
+var passwordHasher Hasher = &BcryptHasher{Cost: bcrypt.DefaultCost}
+
+// knownHashers are used to verify stored hashes of every supported format
+var knownHashers = []Hasher{
+	&BcryptHasher{Cost: bcrypt.DefaultCost},
+	&Argon2idHasher{},
+}
+
+// SetPasswordHasher sets the hasher used for hashing new passwords
+func SetPasswordHasher(h Hasher) {
+	passwordHasher = h
+}
+
+// EncryptPassowd return the hashed password stirng and error
+func EncryptPassword(password string) (string, error) {
+	return passwordHasher.Hash(password)
+}
+
+// CheckPassword checks if the hashed passwod and given password matches or not.
+// The algorithm is detected from the hash format, so old hashes keep working after the hasher is changed.
 func CheckPassword(hashedPassword, testPassword string) error {
-	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(testPassword))
-	return err
+	for _, h := range knownHashers {
+		if !h.Matches(hashedPassword) {
+			continue
+		}
+		ok, err := h.Verify(hashedPassword, testPassword)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrMismatchedPassword
+		}
+		return nil
+	}
+	return ErrUnknownHashFormat
+}
+
+// PasswordNeedsRehash reports whether the hashed password was created by other algorithm or parameters than the current hasher
+func PasswordNeedsRehash(hashedPassword string) bool {
+	if !passwordHasher.Matches(hashedPassword) {
+		return true
+	}
+	return passwordHasher.NeedsRehash(hashedPassword)
 }
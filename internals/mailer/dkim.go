@@ -0,0 +1,79 @@
+package mailer
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/toorop/go-dkim"
+	mail "github.com/xhit/go-simple-mail/v2"
+)
+
+// DKIMConfig holds the DKIM signing configuration.
+// Signing is disabled when Domain is empty.
+type DKIMConfig struct {
+	Domain         string
+	Selector       string
+	PrivateKeyPath string
+}
+
+// DKIMSigner signs the composed messages with the private key of the domain.
+// Receivers verify the signature with the public key published at <selector>._domainkey.<domain>.
+type DKIMSigner struct {
+	Domain     string
+	Selector   string
+	privateKey []byte
+}
+
+// NewDKIMSigner loads and validates the RSA private key of the configuration
+func NewDKIMSigner(c DKIMConfig) (*DKIMSigner, error) {
+	if c.Selector == "" {
+		return nil, errors.New("dkim selector is required")
+	}
+	key, err := os.ReadFile(c.PrivateKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("error in reading dkim private key: %s", err)
+	}
+	if err := validateRSAPrivateKey(key); err != nil {
+		return nil, err
+	}
+	return &DKIMSigner{
+		Domain:     c.Domain,
+		Selector:   c.Selector,
+		privateKey: key,
+	}, nil
+}
+
+// Sign adds the DKIM-Signature covering the given headers and the body to the email
+func (s *DKIMSigner) Sign(email *mail.Email, headers []string) error {
+	options := dkim.NewSigOptions()
+	options.PrivateKey = s.privateKey
+	options.Domain = s.Domain
+	options.Selector = s.Selector
+	options.Canonicalization = "relaxed/relaxed"
+	options.Headers = headers
+	email.SetDkim(options)
+	return email.Error
+}
+
+// validateRSAPrivateKey checks that the key is a PEM encoded PKCS1 or PKCS8 RSA private key
+func validateRSAPrivateKey(key []byte) error {
+	block, _ := pem.Decode(key)
+	if block == nil {
+		return errors.New("dkim private key is not PEM encoded")
+	}
+	if _, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("error in parsing dkim private key: %s", err)
+	}
+	if _, ok := parsed.(*rsa.PrivateKey); !ok {
+		return errors.New("dkim private key must be a RSA key")
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+package mailer
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+	"github.com/toorop/go-dkim"
+)
+
+const (
+	testDomain   = "bookworm.test"
+	testSelector = "mail"
+)
+
+// writeKey writes the PEM block into a new file of the test directory and returns its path
+func writeKey(t *testing.T, block *pem.Block) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "dkim.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// newRSAKey returns a new RSA key and the path of its PKCS1 PEM file
+func newRSAKey(t *testing.T) (*rsa.PrivateKey, string) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, writeKey(t, &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+}
+
+// dnsRecord returns the DKIM TXT record publishing the public key
+func dnsRecord(t *testing.T, key *rsa.PublicKey) string {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return "v=DKIM1; k=rsa; p=" + base64.StdEncoding.EncodeToString(der)
+}
+
+// lookup resolves the DKIM record of the test selector and domain only
+func lookup(record string) dkim.DNSOpt {
+	return dkim.DNSOptLookupTXT(func(name string) ([]string, error) {
+		if name != testSelector+"._domainkey."+testDomain {
+			return nil, fmt.Errorf("unexpected lookup of %s", name)
+		}
+		return []string{record}, nil
+	})
+}
+
+// sendSigned sends the message with the file transport signing with the key and returns the written message
+func sendSigned(t *testing.T, keyPath string, unsubscribe string, msg *models.MailData) []byte {
+	t.Helper()
+	dir := t.TempDir()
+	m, err := New(Config{
+		Transport: "file",
+		Dir:       dir,
+		DKIM: DKIMConfig{
+			Domain:         testDomain,
+			Selector:       testSelector,
+			PrivateKeyPath: keyPath,
+		},
+		Unsubscribe: unsubscribe,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Send(msg); err != nil {
+		t.Fatal(err)
+	}
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("expected one mail file, found %d", len(files))
+	}
+	message, err := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return message
+}
+
+func testMail() *models.MailData {
+	return &models.MailData{
+		From:         "BookWorm <no-reply@bookworm.test>",
+		To:           "reader@example.com",
+		Subject:      "Reset your password",
+		Content:      "<p>Click the link to reset your password.</p>",
+		PlainContent: "Click the link to reset your password.",
+	}
+}
+
+func TestSignedMailVerifiesWithPublicKey(t *testing.T) {
+	key, path := newRSAKey(t)
+	tests := []struct {
+		name        string
+		unsubscribe string
+		msg         *models.MailData
+	}{
+		{name: "html with plain alternative", msg: testMail()},
+		{name: "html only", msg: &models.MailData{From: "no-reply@bookworm.test", To: "reader@example.com", Subject: "Hello", Content: "<p>Hello</p>"}},
+		{name: "mailto unsubscribe", unsubscribe: "mailto:unsubscribe@bookworm.test", msg: testMail()},
+		{name: "one click unsubscribe", unsubscribe: "https://bookworm.test/digest/unsubscribe/token", msg: testMail()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := sendSigned(t, path, tt.unsubscribe, tt.msg)
+			if !strings.Contains(string(message), "DKIM-Signature:") {
+				t.Fatal("message is not signed")
+			}
+			status, err := dkim.Verify(&message, lookup(dnsRecord(t, &key.PublicKey)))
+			if err != nil {
+				t.Fatalf("verify: %s", err)
+			}
+			if status != dkim.SUCCESS {
+				t.Fatalf("expected SUCCESS, got %v", status)
+			}
+		})
+	}
+}
+
+func TestSignedMailFailsVerification(t *testing.T) {
+	key, path := newRSAKey(t)
+	other, _ := newRSAKey(t)
+	tests := []struct {
+		name   string
+		record string
+		tamper func(message string) string
+	}{
+		{
+			name:   "other public key",
+			record: dnsRecord(t, &other.PublicKey),
+			tamper: func(message string) string { return message },
+		},
+		{
+			name:   "changed subject",
+			record: dnsRecord(t, &key.PublicKey),
+			tamper: func(message string) string {
+				return strings.Replace(message, "Subject: Reset your password", "Subject: Reset your account", 1)
+			},
+		},
+		{
+			name:   "changed body",
+			record: dnsRecord(t, &key.PublicKey),
+			tamper: func(message string) string {
+				return strings.Replace(message, "Click the link", "Click this link", 1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := []byte(tt.tamper(string(sendSigned(t, path, "", testMail()))))
+			status, err := dkim.Verify(&message, lookup(tt.record))
+			if err == nil || status == dkim.SUCCESS {
+				t.Fatalf("expected verification to fail, got %v", status)
+			}
+		})
+	}
+}
+
+func TestNewDKIMSigner(t *testing.T) {
+	_, rsaPath := newRSAKey(t)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(rsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkcs8Path := writeKey(t, &pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8})
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecDer, err := x509.MarshalPKCS8PrivateKey(ecKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecPath := writeKey(t, &pem.Block{Type: "PRIVATE KEY", Bytes: ecDer})
+
+	notPEM := filepath.Join(t.TempDir(), "key.txt")
+	if err := os.WriteFile(notPEM, []byte("not a key"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		config  DKIMConfig
+		wantErr bool
+	}{
+		{name: "pkcs1 rsa key", config: DKIMConfig{Domain: testDomain, Selector: testSelector, PrivateKeyPath: rsaPath}},
+		{name: "pkcs8 rsa key", config: DKIMConfig{Domain: testDomain, Selector: testSelector, PrivateKeyPath: pkcs8Path}},
+		{name: "missing selector", config: DKIMConfig{Domain: testDomain, PrivateKeyPath: rsaPath}, wantErr: true},
+		{name: "missing key file", config: DKIMConfig{Domain: testDomain, Selector: testSelector, PrivateKeyPath: filepath.Join(t.TempDir(), "missing.pem")}, wantErr: true},
+		{name: "not pem encoded", config: DKIMConfig{Domain: testDomain, Selector: testSelector, PrivateKeyPath: notPEM}, wantErr: true},
+		{name: "ecdsa key", config: DKIMConfig{Domain: testDomain, Selector: testSelector, PrivateKeyPath: ecPath}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewDKIMSigner(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
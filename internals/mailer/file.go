@@ -0,0 +1,40 @@
+package mailer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ishanshre/Book-Review-Platform/internals/helpers"
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+)
+
+// FileMailer writes every message as a .eml file into Dir.
+// It is useful in development to inspect the email without a SMTP server.
+type FileMailer struct {
+	Dir string
+
+	composer *composer
+}
+
+// Send writes the composed message into a new file
+func (f *FileMailer) Send(msg *models.MailData) error {
+	email, err := f.composer.compose(msg)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(f.Dir, os.ModePerm); err != nil {
+		return fmt.Errorf("error in creating directory: %s", err)
+	}
+	name := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102150405"), helpers.RandomAlphaNum(8))
+	// the signed message is only available in DkimMsg
+	message := email.DkimMsg
+	if message == "" {
+		message = email.GetMessage()
+	}
+	if err := os.WriteFile(filepath.Join(f.Dir, name), []byte(message), 0644); err != nil {
+		return fmt.Errorf("error in writing email file: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package mailer
+
+import (
+	"log"
+
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+)
+
+// LogMailer only logs the message instead of delivering it
+type LogMailer struct {
+	Logger *log.Logger
+}
+
+// Send prints the recipient, subject and plain text body to the logger
+func (l *LogMailer) Send(msg *models.MailData) error {
+	body := msg.PlainContent
+	if body == "" {
+		body = msg.Content
+	}
+	l.Logger.Printf("email to=%s from=%s subject=%q\n%s", msg.To, msg.From, msg.Subject, body)
+	return nil
+}
@@ -0,0 +1,162 @@
+package mailer
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ishanshre/Book-Review-Platform/internals/helpers"
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+	mail "github.com/xhit/go-simple-mail/v2"
+)
+
+// Mailer delivers email messages.
+// Implementations must be safe to use from multiple goroutines.
+type Mailer interface {
+	Send(msg *models.MailData) error
+}
+
+// Config holds the mail transport configuration
+type Config struct {
+	// Transport is one of "smtp", "file" or "log"
+	Transport string
+
+	// SMTP transport options
+	Host       string
+	Port       int
+	Username   string
+	Password   string
+	Encryption string
+	Timeout    time.Duration
+
+	// Dir is the directory used by the file transport
+	Dir string
+
+	// Logger is used by the log transport
+	Logger *log.Logger
+
+	// DKIM signs the messages when the domain is set
+	DKIM DKIMConfig
+
+	// Unsubscribe is the default List-Unsubscribe address such as mailto:unsubscribe@example.com
+	Unsubscribe string
+}
+
+// New returns the Mailer for the configured transport
+func New(c Config) (Mailer, error) {
+	composer := &composer{unsubscribe: c.Unsubscribe}
+	if c.DKIM.Domain != "" {
+		signer, err := NewDKIMSigner(c.DKIM)
+		if err != nil {
+			return nil, err
+		}
+		composer.signer = signer
+	}
+
+	switch c.Transport {
+	case "", "smtp":
+		encryption, err := parseEncryption(c.Encryption)
+		if err != nil {
+			return nil, err
+		}
+		if c.Timeout == 0 {
+			c.Timeout = 10 * time.Second
+		}
+		return &SMTPMailer{
+			Host:       c.Host,
+			Port:       c.Port,
+			Username:   c.Username,
+			Password:   c.Password,
+			Encryption: encryption,
+			Timeout:    c.Timeout,
+			composer:   composer,
+		}, nil
+	case "file":
+		if c.Dir == "" {
+			c.Dir = "./mails"
+		}
+		return &FileMailer{Dir: c.Dir, composer: composer}, nil
+	case "log":
+		if c.Logger == nil {
+			c.Logger = log.Default()
+		}
+		return &LogMailer{Logger: c.Logger}, nil
+	}
+	return nil, fmt.Errorf("unsupported mail transport: %s", c.Transport)
+}
+
+// parseEncryption converts the encryption name into go-simple-mail encryption type
+func parseEncryption(name string) (mail.Encryption, error) {
+	switch name {
+	case "", "none":
+		return mail.EncryptionNone, nil
+	case "ssl", "tls":
+		return mail.EncryptionSSLTLS, nil
+	case "starttls":
+		return mail.EncryptionSTARTTLS, nil
+	}
+	return mail.EncryptionNone, fmt.Errorf("unsupported smtp encryption: %s", name)
+}
+
+// composer builds the messages of the transports with the common headers and the DKIM signature
+type composer struct {
+	// signer is nil when DKIM signing is disabled
+	signer *DKIMSigner
+	// unsubscribe is the List-Unsubscribe address used when the message has none
+	unsubscribe string
+}
+
+// compose builds the message with html body and plain text alternative.
+// Every message carries Date and Message-ID headers and is signed when DKIM is configured.
+func (c *composer) compose(msg *models.MailData) (*mail.Email, error) {
+	if c == nil {
+		c = &composer{}
+	}
+	email := mail.NewMSG()
+	email.SetFrom(msg.From).AddTo(msg.To).SetSubject(msg.Subject)
+	email.SetDate(time.Now().UTC().Format("2006-01-02 15:04:05 MST"))
+	email.AddHeader("Message-ID", c.messageID(msg))
+	signed := []string{"from", "to", "subject", "date", "message-id", "mime-version", "content-type"}
+
+	unsubscribe := msg.Unsubscribe
+	if unsubscribe == "" {
+		unsubscribe = c.unsubscribe
+	}
+	if unsubscribe != "" {
+		email.SetListUnsubscribe(fmt.Sprintf("<%s>", unsubscribe))
+		signed = append(signed, "list-unsubscribe")
+		if strings.HasPrefix(unsubscribe, "http://") || strings.HasPrefix(unsubscribe, "https://") {
+			// RFC 8058 one-click unsubscribe
+			email.AddHeader("List-Unsubscribe-Post", "List-Unsubscribe=One-Click")
+			signed = append(signed, "list-unsubscribe-post")
+		}
+	}
+
+	if msg.PlainContent != "" {
+		email.SetBody(mail.TextPlain, msg.PlainContent)
+		email.AddAlternative(mail.TextHTML, msg.Content)
+	} else {
+		email.SetBody(mail.TextHTML, msg.Content)
+	}
+	if email.Error != nil {
+		return nil, email.Error
+	}
+	if c.signer != nil {
+		if err := c.signer.Sign(email, signed); err != nil {
+			return nil, err
+		}
+	}
+	return email, nil
+}
+
+// messageID returns a unique Message-ID using the DKIM domain or the domain of the sender
+func (c *composer) messageID(msg *models.MailData) string {
+	domain := "localhost"
+	if c.signer != nil {
+		domain = c.signer.Domain
+	} else if at := strings.LastIndex(msg.From, "@"); at != -1 {
+		domain = strings.Trim(msg.From[at+1:], "<> ")
+	}
+	return fmt.Sprintf("<%d.%s@%s>", time.Now().UnixNano(), helpers.RandomAlphaNum(12), domain)
+}
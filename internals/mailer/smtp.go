@@ -0,0 +1,52 @@
+package mailer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+	mail "github.com/xhit/go-simple-mail/v2"
+)
+
+// SMTPMailer sends email through a SMTP server with optional authentication and encryption
+type SMTPMailer struct {
+	Host       string
+	Port       int
+	Username   string
+	Password   string
+	Encryption mail.Encryption
+	Timeout    time.Duration
+
+	composer *composer
+}
+
+// Send connects to the SMTP server and sends the message.
+// A new connection is used for every message.
+func (s *SMTPMailer) Send(msg *models.MailData) error {
+	email, err := s.composer.compose(msg)
+	if err != nil {
+		return err
+	}
+
+	server := mail.NewSMTPClient()
+	server.Host = s.Host
+	server.Port = s.Port
+	server.Encryption = s.Encryption
+	server.KeepAlive = false
+	server.ConnectTimeout = s.Timeout
+	server.SendTimeout = s.Timeout
+	if s.Username != "" {
+		server.Username = s.Username
+		server.Password = s.Password
+		server.Authentication = mail.AuthLogin
+	}
+
+	client, err := server.Connect()
+	if err != nil {
+		return fmt.Errorf("could not connect to smtp server %s:%d: %s", s.Host, s.Port, err)
+	}
+	if err := email.Send(client); err != nil {
+		return fmt.Errorf("could not send email to %s: %s", msg.To, err)
+	}
+	return nil
+}
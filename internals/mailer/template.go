@@ -0,0 +1,47 @@
+package mailer
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"path/filepath"
+	texttemplate "text/template"
+
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+)
+
+var pathToTemplates = filepath.Join("templates", "email")
+
+// Render renders the named email template of the message into Content and PlainContent.
+// The html body is parsed from <name>.html.tmpl together with the email layout
+// and the text body from <name>.txt.tmpl. Messages without a template are left untouched.
+func Render(msg *models.MailData) error {
+	if msg.Template == "" {
+		return nil
+	}
+
+	htmlTmpl, err := htmltemplate.ParseFiles(
+		filepath.Join(pathToTemplates, "base.layout.html.tmpl"),
+		filepath.Join(pathToTemplates, fmt.Sprintf("%s.html.tmpl", msg.Template)),
+	)
+	if err != nil {
+		return fmt.Errorf("error in parsing email template %s: %s", msg.Template, err)
+	}
+	var html bytes.Buffer
+	if err := htmlTmpl.ExecuteTemplate(&html, "email", msg.Data); err != nil {
+		return fmt.Errorf("error in rendering email template %s: %s", msg.Template, err)
+	}
+
+	textTmpl, err := texttemplate.ParseFiles(filepath.Join(pathToTemplates, fmt.Sprintf("%s.txt.tmpl", msg.Template)))
+	if err != nil {
+		return fmt.Errorf("error in parsing email template %s: %s", msg.Template, err)
+	}
+	var text bytes.Buffer
+	if err := textTmpl.Execute(&text, msg.Data); err != nil {
+		return fmt.Errorf("error in rendering email template %s: %s", msg.Template, err)
+	}
+
+	msg.Content = html.String()
+	msg.PlainContent = text.String()
+	return nil
+}
@@ -0,0 +1,202 @@
+// Package markdown renders the Markdown subset allowed in the review bodies.
+// It supports paragraphs, line breaks, emphasis, inline code, bullet and numbered lists,
+// block quotes, links and ||spoiler|| blocks. Everything else is shown as plain text.
+// The text is escaped while rendering, the output should still go through the sanitize package.
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// listItem matches the bullet and numbered list items and captures the marker and the text
+var listItem = regexp.MustCompile(`^\s{0,3}([-*+]|\d{1,9}\.)\s+(.*)$`)
+
+// quoteLine matches the block quote lines and captures the quoted text
+var quoteLine = regexp.MustCompile(`^\s{0,3}>\s?(.*)$`)
+
+// Render returns the HTML of the Markdown text
+func Render(src string) string {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	return renderBlocks(strings.Split(src, "\n"))
+}
+
+// renderBlocks renders the lines as paragraphs, lists and block quotes
+func renderBlocks(lines []string) string {
+	var b strings.Builder
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		switch {
+		case strings.TrimSpace(line) == "":
+			i++
+		case quoteLine.MatchString(line):
+			quoted := []string{}
+			for ; i < len(lines) && quoteLine.MatchString(lines[i]); i++ {
+				quoted = append(quoted, quoteLine.FindStringSubmatch(lines[i])[1])
+			}
+			b.WriteString("<blockquote>")
+			b.WriteString(renderBlocks(quoted))
+			b.WriteString("</blockquote>\n")
+		case listItem.MatchString(line):
+			ordered := isOrdered(line)
+			items := []string{}
+			for ; i < len(lines); i++ {
+				match := listItem.FindStringSubmatch(lines[i])
+				if match != nil && isOrdered(lines[i]) == ordered {
+					items = append(items, match[2])
+					continue
+				}
+				// indented lines continue the previous item
+				if match == nil && strings.TrimSpace(lines[i]) != "" && strings.HasPrefix(lines[i], "  ") {
+					items[len(items)-1] += "\n" + strings.TrimSpace(lines[i])
+					continue
+				}
+				break
+			}
+			tag := "ul"
+			if ordered {
+				tag = "ol"
+			}
+			b.WriteString("<" + tag + ">")
+			for _, item := range items {
+				b.WriteString("<li>" + inline(item) + "</li>")
+			}
+			b.WriteString("</" + tag + ">\n")
+		default:
+			paragraph := []string{}
+			for ; i < len(lines); i++ {
+				if strings.TrimSpace(lines[i]) == "" || quoteLine.MatchString(lines[i]) || listItem.MatchString(lines[i]) {
+					break
+				}
+				paragraph = append(paragraph, strings.TrimSpace(lines[i]))
+			}
+			b.WriteString("<p>" + inline(strings.Join(paragraph, "\n")) + "</p>\n")
+		}
+	}
+	return b.String()
+}
+
+// isOrdered returns true if the list item is numbered
+func isOrdered(line string) bool {
+	match := listItem.FindStringSubmatch(line)
+	return match != nil && strings.HasSuffix(match[1], ".")
+}
+
+// spans are the inline delimiters with the tags they render to, the longer delimiters are matched first
+var spans = []struct {
+	delimiter string
+	open      string
+	close     string
+}{
+	{"||", `<span class="spoiler">`, "</span>"},
+	{"**", "<strong>", "</strong>"},
+	{"__", "<strong>", "</strong>"},
+	{"*", "<em>", "</em>"},
+	{"_", "<em>", "</em>"},
+}
+
+// inline renders the emphasis, code, links and spoilers of the text and escapes the rest
+func inline(text string) string {
+	var b, plain strings.Builder
+	flush := func() {
+		b.WriteString(strings.ReplaceAll(html.EscapeString(plain.String()), "\n", "<br>\n"))
+		plain.Reset()
+	}
+	for i := 0; i < len(text); {
+		rest := text[i:]
+
+		// a backslash shows the next punctuation as it is
+		if rest[0] == '\\' && len(rest) > 1 && strings.ContainsRune("\\`*_[]()|>-+.#!", rune(rest[1])) {
+			plain.WriteByte(rest[1])
+			i += 2
+			continue
+		}
+
+		if rest[0] == '`' {
+			if end := strings.IndexByte(rest[1:], '`'); end > 0 {
+				flush()
+				b.WriteString("<code>" + html.EscapeString(rest[1:end+1]) + "</code>")
+				i += end + 2
+				continue
+			}
+		}
+
+		if rest[0] == '[' {
+			if label, url, n, ok := link(rest); ok {
+				flush()
+				b.WriteString(`<a href="` + html.EscapeString(url) + `" rel="nofollow noopener">` + inline(label) + "</a>")
+				i += n
+				continue
+			}
+		}
+
+		matched := false
+		for _, span := range spans {
+			if !strings.HasPrefix(rest, span.delimiter) {
+				continue
+			}
+			// underscores inside words like snake_case are not emphasis
+			if span.delimiter[0] == '_' && i > 0 && isWordByte(text[i-1]) {
+				break
+			}
+			d := len(span.delimiter)
+			end := strings.Index(rest[d:], span.delimiter)
+			if end <= 0 || strings.TrimSpace(rest[d:d+end]) == "" {
+				continue
+			}
+			// emphasis does not start or end with a space, so "2 * 3 * 4" stays as it is
+			if span.delimiter != "||" && (isSpace(rest[d]) || isSpace(rest[d+end-1])) {
+				continue
+			}
+			flush()
+			b.WriteString(span.open + inline(rest[d:d+end]) + span.close)
+			i += d + end + d
+			matched = true
+			break
+		}
+		if matched {
+			continue
+		}
+
+		plain.WriteByte(rest[0])
+		i++
+	}
+	flush()
+	return b.String()
+}
+
+// link parses the [label](url) link at the start of the text.
+// Only http, https and mailto links are accepted.
+func link(text string) (label, url string, n int, ok bool) {
+	closeLabel := strings.Index(text, "](")
+	if closeLabel <= 1 {
+		return "", "", 0, false
+	}
+	closeURL := strings.IndexByte(text[closeLabel+2:], ')')
+	if closeURL <= 0 {
+		return "", "", 0, false
+	}
+	label = text[1:closeLabel]
+	url = strings.TrimSpace(text[closeLabel+2 : closeLabel+2+closeURL])
+	if strings.ContainsAny(label, "\n") || strings.ContainsAny(url, " \n") || !AllowedURL(url) {
+		return "", "", 0, false
+	}
+	return label, url, closeLabel + 2 + closeURL + 1, true
+}
+
+// AllowedURL returns true if the url is an http, https or mailto link
+func AllowedURL(url string) bool {
+	url = strings.ToLower(strings.TrimSpace(url))
+	return strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://") || strings.HasPrefix(url, "mailto:")
+}
+
+// isWordByte returns true if the byte is an ascii letter or digit
+func isWordByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// isSpace returns true if the byte is an ascii whitespace
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n'
+}
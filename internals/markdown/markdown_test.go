@@ -0,0 +1,77 @@
+package markdown
+
+import "testing"
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		// blocks
+		{"paragraphs", "one\ntwo\n\nthree", "<p>one<br>\ntwo</p>\n<p>three</p>\n"},
+		{"lists", "- a\n- b\n\n1. c", "<ul><li>a</li><li>b</li></ul>\n<ol><li>c</li></ol>\n"},
+		{"quote", "> quoted\n> - item", "<blockquote><p>quoted</p>\n<ul><li>item</li></ul>\n</blockquote>\n"},
+
+		// inline
+		{"emphasis", `**bold** and *em* and __strong__ and _em_`, "<p><strong>bold</strong> and <em>em</em> and <strong>strong</strong> and <em>em</em></p>\n"},
+		{"nested emphasis", `**bold _em_**`, "<p><strong>bold <em>em</em></strong></p>\n"},
+		{"spoiler", `||ending||`, "<p><span class=\"spoiler\">ending</span></p>\n"},
+		{"snake case", `snake_case_name`, "<p>snake_case_name</p>\n"},
+		{"spaced asterisks", `2 * 3 * 4`, "<p>2 * 3 * 4</p>\n"},
+		{"escaped delimiter", `\*not em\*`, "<p>*not em*</p>\n"},
+
+		// links
+		{"http link", `[x](https://example.com)`, "<p><a href=\"https://example.com\" rel=\"nofollow noopener\">x</a></p>\n"},
+		{"mailto link", `[x](mailto:me@example.com)`, "<p><a href=\"mailto:me@example.com\" rel=\"nofollow noopener\">x</a></p>\n"},
+		{"emphasis in link", `[**x**](https://example.com)`, "<p><a href=\"https://example.com\" rel=\"nofollow noopener\"><strong>x</strong></a></p>\n"},
+		{"javascript link", `[x](javascript:alert(1))`, "<p>[x](javascript:alert(1))</p>\n"},
+		{"upper case javascript link", `[x](JAVASCRIPT:alert(1))`, "<p>[x](JAVASCRIPT:alert(1))</p>\n"},
+		{"entity encoded link", `[x](&#106;avascript:alert(1))`, "<p>[x](&amp;#106;avascript:alert(1))</p>\n"},
+		{"data link", `[x](data:text/html;base64,PHNjcmlwdD4=)`, "<p>[x](data:text/html;base64,PHNjcmlwdD4=)</p>\n"},
+		{"quote breakout in link", `[x](https://example.com/"onclick="alert(1))`, "<p><a href=\"https://example.com/&#34;onclick=&#34;alert(1\" rel=\"nofollow noopener\">x</a>)</p>\n"},
+
+		// html is text
+		{"script", `<script>alert(1)</script>`, "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>\n"},
+		{"style", `<style>p{}</style>`, "<p>&lt;style&gt;p{}&lt;/style&gt;</p>\n"},
+		{"comment", `<!-- hidden -->`, "<p>&lt;!-- hidden --&gt;</p>\n"},
+		{"attribute", `<img src=x onerror="alert(1)">`, "<p>&lt;img src=x onerror=&#34;alert(1)&#34;&gt;</p>\n"},
+		{"html in code", "`<b>code</b>`", "<p><code>&lt;b&gt;code&lt;/b&gt;</code></p>\n"},
+		{"html in emphasis", `*<b>x</b>*`, "<p><em>&lt;b&gt;x&lt;/b&gt;</em></p>\n"},
+		{"entity", `&#106;avascript:`, "<p>&amp;#106;avascript:</p>\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Render(tt.src); got != tt.want {
+				t.Errorf("Render(%q)\n got: %s\nwant: %s", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAllowedURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"https://example.com", true},
+		{"http://example.com", true},
+		{"HTTPS://EXAMPLE.COM", true},
+		{" https://example.com", true},
+		{"mailto:me@example.com", true},
+		{"javascript:alert(1)", false},
+		{"JavaScript:alert(1)", false},
+		{" javascript:alert(1)", false},
+		{"&#106;avascript:alert(1)", false},
+		{"data:text/html,x", false},
+		{"vbscript:msgbox(1)", false},
+		{"//example.com", false},
+		{"/relative", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := AllowedURL(tt.url); got != tt.want {
+			t.Errorf("AllowedURL(%q) = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+}
@@ -14,14 +14,34 @@ func NewMiddlewareApp(a *config.AppConfig) {
 	app = a
 }
 
-// SessionLoad loads and saves the session on every request
+// SessionLoad loads and saves the session on every request.
+// LoadAndSave buffers the whole response, so event streams only load the session and changes to it are not saved.
 func SessionLoad(next http.Handler) http.Handler {
-	return app.Session.LoadAndSave(next)
+	loadAndSave := app.Session.LoadAndSave(next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "text/event-stream" {
+			loadAndSave.ServeHTTP(w, r)
+			return
+		}
+		var token string
+		if cookie, err := r.Cookie(app.Session.Cookie.Name); err == nil {
+			token = cookie.Value
+		}
+		ctx, err := app.Session.Load(r.Context(), token)
+		if err != nil {
+			helpers.ServerError(w, err)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
 }
 
 // NoSurf implement csrf token middleware
 func NoSurf(next http.Handler) http.Handler {
 	csrfHandler := nosurf.New(next) // creates a new handler
+	// one-click unsubscribe requests from mail clients carry no csrf token
+	csrfHandler.ExemptGlob("/digest/unsubscribe/*")
+	csrfHandler.ExemptGlob("/reading-goal/unsubscribe/*")
 	csrfHandler.SetBaseCookie(http.Cookie{
 		HttpOnly: true,
 		Path:     "/",
@@ -79,6 +99,18 @@ func Admin(next http.Handler) http.Handler {
 	})
 }
 
+// Moderator is a middleware function that checks if the user is a moderator or an admin.
+// If the user is neither, it redirects to the home page.
+func Moderator(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !helpers.IsModerator(r) {
+			http.Redirect(w, r, "/", http.StatusFound)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 func Logger(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		app.InfoLog.Printf("| %s | %s | %s ", r.Method, r.URL.Path, r.Proto)
@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"github.com/ishanshre/Book-Review-Platform/internals/textdiff"
+)
 
 // Embed the book and author data for BookAuthor
 type BookAuthorData struct {
@@ -65,9 +69,19 @@ type FollowerData struct {
 	FollowedAt time.Time
 }
 
+// ReviewRevisionData embeds the revision with its changes from the previous revision
+type ReviewRevisionData struct {
+	Revision       *ReviewRevision
+	PreviousRating float64
+	RatingChanged  bool
+	BodyDiff       []textdiff.Op
+}
+
 type ReviewUserData struct {
-	Review *Review
-	User   *User
+	Review   *Review
+	User     *User
+	Votes    *ReviewVoteCount
+	EditedAt *time.Time
 }
 
 type UserKycData struct {
@@ -48,12 +48,19 @@ type AdminUserListApi struct {
 	Users    []*AdminUserList `json:"users"`
 }
 
-// MailData holds the email message
+// MailData holds the email message.
+// Content holds the html body and PlainContent the text alternative.
+// When Template is set, both bodies are rendered from the named email template using Data.
+// Unsubscribe is the List-Unsubscribe address of the message, a mailto or https url.
 type MailData struct {
-	To      string
-	From    string
-	Subject string
-	Content string
+	To           string
+	From         string
+	Subject      string
+	Content      string
+	PlainContent string
+	Template     string
+	Data         map[string]interface{}
+	Unsubscribe  string
 }
 
 // ResetPassword stores the new and confirm password
@@ -69,6 +76,36 @@ type Genre struct {
 	Title string
 }
 
+// Series holds the series table data with the number of its books
+type Series struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	BookCount   int       `json:"book_count"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// SeriesBook is a book of a series at its position. Positions can be fractional, e.g. 2.5 for a novella between 2 and 3.
+type SeriesBook struct {
+	SeriesID int     `json:"series_id"`
+	BookID   int     `json:"book_id"`
+	Position float64 `json:"position"`
+	Title    string  `json:"title"`
+	Isbn     int64   `json:"isbn"`
+	Cover    string  `json:"cover"`
+	IsActive bool    `json:"is_active"`
+}
+
+// BookSeries is a series a book belongs to with the position of the book and the next active book of the series.
+// Next is nil when the book is the last of the series.
+type BookSeries struct {
+	SeriesID   int         `json:"series_id"`
+	SeriesName string      `json:"series_name"`
+	Position   float64     `json:"position"`
+	Next       *SeriesBook `json:"next"`
+}
+
 // Publisher holds the publishers table model
 type Publisher struct {
 	ID              int
@@ -179,9 +216,51 @@ type BookLanguage struct {
 
 // ReadList holds the the book id, user id and created at of readLists table
 type ReadList struct {
-	UserID    int
-	BookID    int
-	CreatedAt time.Time
+	UserID      int        `json:"user_id"`
+	BookID      int        `json:"book_id"`
+	CreatedAt   time.Time  `json:"created_at"`
+	Status      string     `json:"status"`
+	CurrentPage int        `json:"current_page"`
+	StartedAt   *time.Time `json:"started_at"`
+	FinishedAt  *time.Time `json:"finished_at"`
+	UpdatedAt   *time.Time `json:"updated_at"`
+}
+
+// Reading statuses of the books in read list
+const (
+	ReadingWantToRead = "want_to_read"
+	ReadingCurrently  = "currently_reading"
+	ReadingFinished   = "finished"
+	ReadingAbandoned  = "abandoned"
+)
+
+// ReadingStatuses lists the reading statuses a user can choose
+var ReadingStatuses = []string{
+	ReadingWantToRead,
+	ReadingCurrently,
+	ReadingFinished,
+	ReadingAbandoned,
+}
+
+// ReadListRead holds the read_list_reads table data, a finished read of the book by the user.
+type ReadListRead struct {
+	ID         int        `json:"id"`
+	UserID     int        `json:"user_id"`
+	BookID     int        `json:"book_id"`
+	StartedAt  *time.Time `json:"started_at"`
+	FinishedAt time.Time  `json:"finished_at"`
+}
+
+// ReadingProgress is the reading state of the book for the user shown by the progress widget
+type ReadingProgress struct {
+	InReadList  bool       `json:"in_read_list"`
+	Status      string     `json:"status"`
+	CurrentPage int        `json:"current_page"`
+	Paperback   int        `json:"paperback"`
+	Percent     int        `json:"percent"`
+	StartedAt   *time.Time `json:"started_at"`
+	FinishedAt  *time.Time `json:"finished_at"`
+	ReadCount   int        `json:"read_count"`
 }
 
 type ReadListFilter struct {
@@ -199,6 +278,22 @@ type ReadListFilterApi struct {
 	ReadListFilters []*ReadListFilter `json:"read_lists"`
 }
 
+// Shelf holds the shelves table data, a named collection of books made by a user.
+// Public shelves can be viewed by anyone with the share url.
+type Shelf struct {
+	ID          int       `json:"id"`
+	UserID      int       `json:"user_id"`
+	Username    string    `json:"username"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	IsPublic    bool      `json:"is_public"`
+	ShareToken  string    `json:"share_token"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	BookCount   int       `json:"book_count"`
+	HasBook     bool      `json:"has_book"`
+}
+
 // BuyList holds the the book id, user id and created at of BuyLists table
 type BuyList struct {
 	UserID    int
@@ -256,6 +351,56 @@ type Review struct {
 	UpdatedAt time.Time
 }
 
+// roles of the editors of a review revision
+const (
+	ReviewEditorAuthor = "author"
+	ReviewEditorAdmin  = "admin"
+)
+
+// ReviewRevision holds the review_revisions table data.
+// Revision 0 is the review as it was written and every edit of the rating or body adds the next revision.
+type ReviewRevision struct {
+	ID             int       `json:"id"`
+	ReviewID       int       `json:"review_id"`
+	Revision       int       `json:"revision"`
+	Rating         float64   `json:"rating"`
+	Body           string    `json:"body"`
+	EditorID       int       `json:"editor_id"`
+	EditorUsername string    `json:"editor_username"`
+	EditorRole     string    `json:"editor_role"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// Review votes
+const (
+	ReviewVoteHelpful   = "helpful"
+	ReviewVoteUnhelpful = "unhelpful"
+)
+
+// ReviewVote is the helpful or unhelpful vote of a user on a review.
+// A user has one vote per review.
+type ReviewVote struct {
+	ID        int       `json:"id"`
+	ReviewID  int       `json:"review_id"`
+	UserID    int       `json:"user_id"`
+	IsHelpful bool      `json:"is_helpful"`
+	VotedAt   time.Time `json:"voted_at"`
+}
+
+// ReviewVoteCount holds the vote counters of a review and the vote of the current user.
+// UserVote is helpful, unhelpful or empty when the user has not voted.
+type ReviewVoteCount struct {
+	ReviewID  int    `json:"review_id"`
+	Helpful   int    `json:"helpful"`
+	Unhelpful int    `json:"unhelpful"`
+	UserVote  string `json:"user_vote"`
+}
+
+// Score returns the helpfulness of the review used to sort the reviews
+func (c *ReviewVoteCount) Score() int {
+	return c.Helpful - c.Unhelpful
+}
+
 type ReviewFilter struct {
 	ID        int       `json:"id"`
 	Rating    float64   `json:"rating"`
@@ -274,6 +419,153 @@ type ReviewFilterApi struct {
 	ReviewFilters []*ReviewFilter `json:"reviews"`
 }
 
+// Review comment moderation statuses used to filter the admin list
+const (
+	ReviewCommentFlagged = "flagged"
+	ReviewCommentHidden  = "hidden"
+	ReviewCommentDeleted = "deleted"
+)
+
+// ReviewComment holds the review_comments table data.
+// ParentID is 0 for the comments on the review and the id of the parent comment for replies.
+type ReviewComment struct {
+	ID        int              `json:"id"`
+	ReviewID  int              `json:"review_id"`
+	UserID    int              `json:"user_id"`
+	Username  string           `json:"username"`
+	ParentID  int              `json:"parent_id"`
+	Body      string           `json:"body"`
+	IsActive  bool             `json:"is_active"`
+	IsFlagged bool             `json:"is_flagged"`
+	IsDeleted bool             `json:"is_deleted"`
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+	BookTitle string           `json:"book_title,omitempty"`
+	Replies   []*ReviewComment `json:"replies,omitempty"`
+}
+
+type ReviewCommentFilterApi struct {
+	Total    int              `json:"total"`
+	Page     int              `json:"page"`
+	LastPage int              `json:"last_page"`
+	Comments []*ReviewComment `json:"comments"`
+}
+
+// Report target types
+const (
+	ReportTargetReview  = "review"
+	ReportTargetComment = "comment"
+)
+
+// Report reasons
+const (
+	ReportReasonSpam       = "spam"
+	ReportReasonOffensive  = "offensive"
+	ReportReasonHarassment = "harassment"
+	ReportReasonSpoiler    = "spoiler"
+	ReportReasonOffTopic   = "off_topic"
+	ReportReasonOther      = "other"
+)
+
+// ReportReasons lists the reasons a user can choose when reporting
+var ReportReasons = []string{
+	ReportReasonSpam,
+	ReportReasonOffensive,
+	ReportReasonHarassment,
+	ReportReasonSpoiler,
+	ReportReasonOffTopic,
+	ReportReasonOther,
+}
+
+// Report statuses
+const (
+	ReportOpen     = "open"
+	ReportResolved = "resolved"
+)
+
+// Report resolutions
+const (
+	ReportDismissed = "dismissed"
+	ReportHidden    = "hidden"
+	ReportDeleted   = "deleted"
+	ReportWarned    = "warned"
+)
+
+// ReportResolutions lists the actions a moderator can take on a report
+var ReportResolutions = []string{
+	ReportDismissed,
+	ReportHidden,
+	ReportDeleted,
+	ReportWarned,
+}
+
+// Report holds the reports table data.
+// TargetUserID and TargetBody keep the author and content of the reported review or comment so the resolution can be audited after it is deleted.
+type Report struct {
+	ID                 int        `json:"id"`
+	TargetType         string     `json:"target_type"`
+	TargetID           int        `json:"target_id"`
+	TargetUserID       int        `json:"target_user_id"`
+	TargetUsername     string     `json:"target_username"`
+	TargetBody         string     `json:"target_body"`
+	ReporterID         int        `json:"reporter_id"`
+	ReporterUsername   string     `json:"reporter_username"`
+	Reason             string     `json:"reason"`
+	Details            string     `json:"details"`
+	Status             string     `json:"status"`
+	Resolution         string     `json:"resolution"`
+	ResolutionNote     string     `json:"resolution_note"`
+	ResolvedBy         int        `json:"resolved_by"`
+	ResolvedByUsername string     `json:"resolved_by_username"`
+	CreatedAt          time.Time  `json:"created_at"`
+	ResolvedAt         *time.Time `json:"resolved_at"`
+	OpenReports        int        `json:"open_reports"`
+}
+
+type ReportFilterApi struct {
+	Total    int       `json:"total"`
+	Page     int       `json:"page"`
+	LastPage int       `json:"last_page"`
+	Reports  []*Report `json:"reports"`
+}
+
+// kinds of the submissions checked by the content filter
+const (
+	ContentFilterReview  = "review"
+	ContentFilterContact = "contact"
+)
+
+// FilterWord holds the filter_words table data.
+// Verdict is hold or reject.
+type FilterWord struct {
+	ID        int       `json:"id"`
+	Word      string    `json:"word"`
+	Verdict   string    `json:"verdict"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ContentFilterLog holds the content_filter_logs table data.
+// TargetID is the stored review or contact and is zero when the submission was rejected.
+type ContentFilterLog struct {
+	ID        int       `json:"id"`
+	Kind      string    `json:"kind"`
+	TargetID  int       `json:"target_id"`
+	UserID    int       `json:"user_id"`
+	Username  string    `json:"username"`
+	Verdict   string    `json:"verdict"`
+	Reasons   string    `json:"reasons"`
+	Content   string    `json:"content"`
+	IpAddress string    `json:"ip_address"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ContentFilterLogFilterApi struct {
+	Total    int                 `json:"total"`
+	Page     int                 `json:"page"`
+	LastPage int                 `json:"last_page"`
+	Logs     []*ContentFilterLog `json:"logs"`
+}
+
 type Contact struct {
 	ID            int
 	FirstName     string
@@ -286,6 +578,7 @@ type Contact struct {
 	IpAddress     string
 	BrowserInfo   string
 	ReferringPage string
+	IsActive      bool
 }
 
 type RequestedBook struct {
@@ -311,3 +604,445 @@ type RequestedBookFilterApi struct {
 	LastPage       int                  `json:"last_page"`
 	RequestedBooks []*RequestedBookUser `json:"requested_books"`
 }
+
+// UserSession holds the user_sessions table data.
+// Each row points to a session token stored in the session store.
+type UserSession struct {
+	ID        int       `json:"id"`
+	UserID    int       `json:"user_id"`
+	Token     string    `json:"-"`
+	IpAddress string    `json:"ip_address"`
+	UserAgent string    `json:"user_agent"`
+	Device    string    `json:"device"`
+	CreatedAt time.Time `json:"created_at"`
+	LastSeen  time.Time `json:"last_seen"`
+}
+
+// Email outbox statuses
+const (
+	EmailPending = "pending"
+	EmailSent    = "sent"
+	EmailFailed  = "failed"
+)
+
+// EmailOutbox holds the email_outbox table data.
+// Mails are stored before sending so they survive restarts and can be retried.
+type EmailOutbox struct {
+	ID            int        `json:"id"`
+	To            string     `json:"to"`
+	From          string     `json:"from"`
+	Subject       string     `json:"subject"`
+	Template      string     `json:"template"`
+	Data          string     `json:"-"`
+	Content       string     `json:"-"`
+	PlainContent  string     `json:"-"`
+	Unsubscribe   string     `json:"-"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	MaxAttempts   int        `json:"max_attempts"`
+	LastError     string     `json:"last_error"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	SentAt        *time.Time `json:"sent_at"`
+}
+
+// EmailOutboxFilterApi holds the filtered email outbox list with pagination
+type EmailOutboxFilterApi struct {
+	Total    int            `json:"total"`
+	Page     int            `json:"page"`
+	LastPage int            `json:"last_page"`
+	Emails   []*EmailOutbox `json:"emails"`
+}
+
+// Notification types
+const (
+	NotificationNewBook              = "new_book"
+	NotificationRequestBookFulfilled = "request_book_fulfilled"
+	NotificationKycApproved          = "kyc_approved"
+	NotificationKycRejected          = "kyc_rejected"
+	NotificationReviewReply          = "review_reply"
+	NotificationModerationWarning    = "moderation_warning"
+	NotificationNewFollower          = "new_follower"
+)
+
+// Notification holds the notifications table data
+type Notification struct {
+	ID        int       `json:"id"`
+	UserID    int       `json:"user_id"`
+	Type      string    `json:"type"`
+	Message   string    `json:"message"`
+	Link      string    `json:"link"`
+	IsRead    bool      `json:"is_read"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NotificationApi holds the notifications of a user with pagination and unread count
+type NotificationApi struct {
+	Total         int             `json:"total"`
+	Unread        int             `json:"unread"`
+	Page          int             `json:"page"`
+	LastPage      int             `json:"last_page"`
+	Notifications []*Notification `json:"notifications"`
+}
+
+// Digest frequencies
+const (
+	DigestNever   = "never"
+	DigestDaily   = "daily"
+	DigestWeekly  = "weekly"
+	DigestMonthly = "monthly"
+)
+
+// DigestPreference holds the digest_preferences table data
+type DigestPreference struct {
+	UserID           int        `json:"user_id"`
+	Frequency        string     `json:"frequency"`
+	UnsubscribeToken string     `json:"-"`
+	LastSentAt       *time.Time `json:"last_sent_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// DigestRecipient holds the user whose digest is due
+type DigestRecipient struct {
+	UserID           int
+	Username         string
+	Email            string
+	Frequency        string
+	UnsubscribeToken string
+	LastSentAt       *time.Time
+}
+
+// DigestBook is a new book by a followed author
+type DigestBook struct {
+	Title  string `json:"title"`
+	Isbn   int64  `json:"isbn"`
+	Author string `json:"author"`
+}
+
+// DigestReview is a new review on a book in the read list
+type DigestReview struct {
+	BookTitle string  `json:"book_title"`
+	Isbn      int64   `json:"isbn"`
+	Username  string  `json:"username"`
+	Rating    float64 `json:"rating"`
+}
+
+// Digest holds the activity since the last digest of a user
+type Digest struct {
+	Books   []*DigestBook   `json:"books"`
+	Reviews []*DigestReview `json:"reviews"`
+	Genres  []*Genre        `json:"genres"`
+}
+
+// ReadingGoal holds the reading_goals table data, the number of books a user wants to read in a year.
+// Finished is the number of reads the user finished in the year.
+type ReadingGoal struct {
+	ID               int        `json:"id"`
+	UserID           int        `json:"user_id"`
+	Year             int        `json:"year"`
+	Target           int        `json:"target"`
+	Remind           bool       `json:"remind"`
+	UnsubscribeToken string     `json:"-"`
+	LastRemindedAt   *time.Time `json:"last_reminded_at"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+	Finished         int        `json:"finished"`
+}
+
+// Percent returns the finished share of the target, at most 100
+func (g *ReadingGoal) Percent() int {
+	if g.Target <= 0 || g.Finished >= g.Target {
+		return 100
+	}
+	return g.Finished * 100 / g.Target
+}
+
+// Expected returns the number of books that should be finished by the time to reach the target at an even pace
+func (g *ReadingGoal) Expected(now time.Time) int {
+	switch {
+	case g.Year < now.Year():
+		return g.Target
+	case g.Year > now.Year():
+		return 0
+	}
+	days := time.Date(g.Year, time.December, 31, 0, 0, 0, 0, now.Location()).YearDay()
+	return g.Target * now.YearDay() / days
+}
+
+// Behind returns true if fewer books are finished than expected by the time
+func (g *ReadingGoal) Behind(now time.Time) bool {
+	return g.Finished < g.Expected(now)
+}
+
+// ReadingChallengeEntry is a user on the reading challenge leaderboard
+type ReadingChallengeEntry struct {
+	Rank       int    `json:"rank"`
+	UserID     int    `json:"user_id"`
+	Username   string `json:"username"`
+	ProfilePic string `json:"profile_pic"`
+	Target     int    `json:"target"`
+	Finished   int    `json:"finished"`
+	Percent    int    `json:"percent"`
+}
+
+// ReadingChallengeApi is the paginated reading challenge leaderboard of a year
+type ReadingChallengeApi struct {
+	Year     int                      `json:"year"`
+	Total    int                      `json:"total"`
+	Page     int                      `json:"page"`
+	LastPage int                      `json:"last_page"`
+	Entries  []*ReadingChallengeEntry `json:"entries"`
+}
+
+// ReadingGoalRecipient holds the user with reminders turned on for the reading goal
+type ReadingGoalRecipient struct {
+	Username string
+	Email    string
+	Goal     *ReadingGoal
+}
+
+// BookInteraction is the interest of a user in a book, weighted from their reviews, lists and shelves
+type BookInteraction struct {
+	UserID int
+	BookID int
+	Weight float64
+}
+
+// BookFeatures are the genres, authors, languages and description of a book compared by the content similarity
+type BookFeatures struct {
+	BookID      int
+	Description string
+	GenreIDs    []int
+	AuthorIDs   []int
+	LanguageIDs []int
+}
+
+// BookSimilarity holds the book_similarities and book_content_similarities table data.
+// Support is the number of readers who showed interest in both books, it is zero for the content similarities.
+type BookSimilarity struct {
+	BookID        int       `json:"book_id"`
+	SimilarBookID int       `json:"similar_book_id"`
+	Score         float64   `json:"score"`
+	Support       int       `json:"support"`
+	ComputedAt    time.Time `json:"computed_at"`
+}
+
+// Recommendation sources
+const (
+	RecommendationPersonalized = "personalized"
+	RecommendationPopular      = "popular"
+	RecommendationContent      = "content"
+)
+
+// RecommendedBook is a book recommended to a user with the source of the recommendation
+type RecommendedBook struct {
+	ID     int     `json:"id"`
+	Title  string  `json:"title"`
+	Isbn   int64   `json:"isbn"`
+	Cover  string  `json:"cover"`
+	Score  float64 `json:"score"`
+	Source string  `json:"source"`
+}
+
+// FeedBook is a book of the home feed with the reason it is in the feed
+type FeedBook struct {
+	ID     int    `json:"id"`
+	Title  string `json:"title"`
+	Isbn   int64  `json:"isbn"`
+	Cover  string `json:"cover"`
+	Reason string `json:"reason"`
+}
+
+// FeedReview is a review with the title and isbn of its book, shown in the home feed and on the public profiles
+type FeedReview struct {
+	ID        int       `json:"id"`
+	Rating    float64   `json:"rating"`
+	Body      string    `json:"body"`
+	Username  string    `json:"username"`
+	BookTitle string    `json:"book_title"`
+	BookIsbn  int64     `json:"book_isbn"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// HomeFeed is the personalised part of the home page of a logged in user
+type HomeFeed struct {
+	AuthorBooks  []*FeedBook   `json:"author_books"`
+	Genres       []string      `json:"genres"`
+	GenreBooks   []*FeedBook   `json:"genre_books"`
+	ShelfReviews []*FeedReview `json:"shelf_reviews"`
+}
+
+// Empty returns true when the feed has nothing to show
+func (f *HomeFeed) Empty() bool {
+	return len(f.AuthorBooks) == 0 && len(f.GenreBooks) == 0 && len(f.ShelfReviews) == 0
+}
+
+// UserFollowStatus is the follow state of a user for the authenticated user with the follow counts of the user
+type UserFollowStatus struct {
+	Username   string `json:"username"`
+	Following  bool   `json:"following"`
+	Followers  int    `json:"followers"`
+	Followings int    `json:"followings"`
+}
+
+// Activity visibilities.
+// There is no followers only visibility because anyone can follow a user without their approval.
+const (
+	VisibilityPublic  = "public"
+	VisibilityPrivate = "private"
+)
+
+// Visibilities are the valid activity visibilities
+var Visibilities = []string{VisibilityPublic, VisibilityPrivate}
+
+// PrivacySettings holds the privacy_settings table data.
+// The Show fields choose the sections of the public profile of the user that other users can see.
+type PrivacySettings struct {
+	UserID              int       `json:"user_id"`
+	ActivityVisibility  string    `json:"activity_visibility"`
+	ShowReviews         bool      `json:"show_reviews"`
+	ShowShelves         bool      `json:"show_shelves"`
+	ShowStats           bool      `json:"show_stats"`
+	ShowFollowedAuthors bool      `json:"show_followed_authors"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// Activity types
+const (
+	ActivityReviewPosted   = "review_posted"
+	ActivityBookFinished   = "book_finished"
+	ActivityShelfCreated   = "shelf_created"
+	ActivityAuthorFollowed = "author_followed"
+)
+
+// Activity holds the activities table data with the title and link of its subject.
+// BookID, ReviewID, ShelfID and AuthorID are zero when the activity is not about one.
+type Activity struct {
+	ID        int       `json:"id"`
+	UserID    int       `json:"user_id"`
+	Username  string    `json:"username"`
+	Type      string    `json:"type"`
+	BookID    int       `json:"book_id,omitempty"`
+	ReviewID  int       `json:"review_id,omitempty"`
+	ShelfID   int       `json:"shelf_id,omitempty"`
+	AuthorID  int       `json:"author_id,omitempty"`
+	Rating    float64   `json:"rating,omitempty"`
+	Subject   string    `json:"subject"`
+	Link      string    `json:"link"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ActivityApi holds the activities with pagination
+type ActivityApi struct {
+	Total      int         `json:"total"`
+	Page       int         `json:"page"`
+	LastPage   int         `json:"last_page"`
+	Activities []*Activity `json:"activities"`
+}
+
+// YearCount is the number of books finished in a year
+type YearCount struct {
+	Year  int `json:"year"`
+	Count int `json:"count"`
+}
+
+// ReadingStats holds the reading statistics shown on the public profile of a user
+type ReadingStats struct {
+	FinishedPerYear []*YearCount `json:"finished_per_year"`
+	TotalFinished   int          `json:"total_finished"`
+	FavouriteGenres []*Genre     `json:"favourite_genres"`
+	ReviewCount     int          `json:"review_count"`
+	AverageRating   float64      `json:"average_rating"`
+}
+
+// Webhook events
+const (
+	WebhookBookCreated          = "book.created"
+	WebhookBookUpdated          = "book.updated"
+	WebhookReviewCreated        = "review.created"
+	WebhookReviewDeactivated    = "review.deactivated"
+	WebhookRequestBookFulfilled = "request_book.fulfilled"
+	WebhookUserRegistered       = "user.registered"
+	WebhookPing                 = "ping"
+)
+
+// WebhookEvents lists the events a webhook can subscribe to
+var WebhookEvents = []string{
+	WebhookBookCreated,
+	WebhookBookUpdated,
+	WebhookReviewCreated,
+	WebhookReviewDeactivated,
+	WebhookRequestBookFulfilled,
+	WebhookUserRegistered,
+}
+
+// Webhook delivery statuses
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// Webhook holds the webhooks table data.
+// Secret is used to sign the payloads so the receiver can verify them.
+type Webhook struct {
+	ID        int       `json:"id"`
+	Url       string    `json:"url"`
+	Secret    string    `json:"-"`
+	Events    []string  `json:"events"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Subscribed reports whether the webhook is subscribed to the event
+func (w *Webhook) Subscribed(event string) bool {
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookPayload is the JSON body posted to the webhook endpoints
+type WebhookPayload struct {
+	Event     string      `json:"event"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// WebhookDelivery holds the webhook_deliveries table data.
+// Url and Secret are copied from the webhook when the delivery is claimed for sending.
+type WebhookDelivery struct {
+	ID             int        `json:"id"`
+	WebhookID      int        `json:"webhook_id"`
+	Url            string     `json:"-"`
+	Secret         string     `json:"-"`
+	Event          string     `json:"event"`
+	Payload        string     `json:"payload"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	MaxAttempts    int        `json:"max_attempts"`
+	ResponseStatus int        `json:"response_status"`
+	ResponseBody   string     `json:"response_body"`
+	LastError      string     `json:"last_error"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+}
+
+// WebhookDeliveryFilterApi holds the filtered deliveries of a webhook with pagination
+type WebhookDeliveryFilterApi struct {
+	Total      int                `json:"total"`
+	Page       int                `json:"page"`
+	LastPage   int                `json:"last_page"`
+	Deliveries []*WebhookDelivery `json:"deliveries"`
+}
+
+// BookRating holds the average rating and number of reviews of a book
+type BookRating struct {
+	BookID  int     `json:"book_id"`
+	Average float64 `json:"average"`
+	Count   int     `json:"count"`
+}
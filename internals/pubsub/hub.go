@@ -0,0 +1,172 @@
+package pubsub
+
+import (
+	"encoding/json"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// subscriberBuffer is the number of messages kept for a slow subscriber before new messages are dropped
+const subscriberBuffer = 16
+
+// Message is published on a topic and delivered to every subscriber of the topic
+type Message struct {
+	Topic string          `json:"topic"`
+	Event string          `json:"event"`
+	Data  json.RawMessage `json:"data"`
+}
+
+// Subscription receives the messages of a topic on C until it is closed
+type Subscription struct {
+	C     <-chan *Message
+	c     chan *Message
+	topic string
+	hub   *Hub
+}
+
+// Close stops the subscription
+func (s *Subscription) Close() {
+	s.hub.unsubscribe(s)
+}
+
+// Hub fans out the published messages to the local subscribers of their topic.
+// With a redis pool the messages go through redis pub/sub, so subscribers connected to other instances receive them too.
+// Without a pool the messages are only delivered within the process.
+type Hub struct {
+	pool     *redis.Pool
+	channel  string
+	errorLog *log.Logger
+
+	mu          sync.RWMutex
+	subscribers map[string]map[*Subscription]struct{}
+}
+
+// NewHub returns a hub using the redis channels starting with prefix.
+// Start must be called to receive the messages from redis.
+func NewHub(pool *redis.Pool, prefix string, errorLog *log.Logger) *Hub {
+	if errorLog == nil {
+		errorLog = log.Default()
+	}
+	return &Hub{
+		pool:        pool,
+		channel:     prefix,
+		errorLog:    errorLog,
+		subscribers: make(map[string]map[*Subscription]struct{}),
+	}
+}
+
+// Subscribe returns a subscription receiving the messages of the topic
+func (h *Hub) Subscribe(topic string) *Subscription {
+	c := make(chan *Message, subscriberBuffer)
+	s := &Subscription{C: c, c: c, topic: topic, hub: h}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.subscribers[topic] == nil {
+		h.subscribers[topic] = make(map[*Subscription]struct{})
+	}
+	h.subscribers[topic][s] = struct{}{}
+	return s
+}
+
+func (h *Hub) unsubscribe(s *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.subscribers[s.topic][s]; !ok {
+		return
+	}
+	delete(h.subscribers[s.topic], s)
+	if len(h.subscribers[s.topic]) == 0 {
+		delete(h.subscribers, s.topic)
+	}
+	close(s.c)
+}
+
+// Publish sends the event with the data encoded as JSON to the subscribers of the topic.
+// When redis is not reachable the message is still delivered to the local subscribers.
+func (h *Hub) Publish(topic, event string, data interface{}) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	msg := &Message{Topic: topic, Event: event, Data: raw}
+	if h.pool == nil {
+		h.deliver(msg)
+		return nil
+	}
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	conn := h.pool.Get()
+	defer conn.Close()
+	if _, err := conn.Do("PUBLISH", h.channel+topic, payload); err != nil {
+		h.deliver(msg)
+		return err
+	}
+	return nil
+}
+
+// deliver sends the message to the local subscribers of its topic.
+// Subscribers whose buffer is full miss the message instead of blocking the hub.
+func (h *Hub) deliver(msg *Message) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for s := range h.subscribers[msg.Topic] {
+		select {
+		case s.c <- msg:
+		default:
+			h.errorLog.Printf("pubsub: dropped %s message for a slow subscriber of %s", msg.Event, msg.Topic)
+		}
+	}
+}
+
+// Start starts a goroutine that receives the messages from redis and delivers them to the local subscribers.
+// The redis subscription is re-established with backoff when the connection is lost.
+func (h *Hub) Start() {
+	if h.pool == nil {
+		return
+	}
+	go func() {
+		backoff := time.Second
+		for {
+			started := time.Now()
+			if err := h.listen(); err != nil {
+				h.errorLog.Printf("pubsub: redis subscription failed: %s", err)
+			}
+			if time.Since(started) > time.Minute {
+				backoff = time.Second
+			}
+			time.Sleep(backoff)
+			if backoff < 30*time.Second {
+				backoff *= 2
+			}
+		}
+	}()
+}
+
+// listen receives the messages of the hub channels until the connection fails
+func (h *Hub) listen() error {
+	psc := redis.PubSubConn{Conn: h.pool.Get()}
+	defer psc.Close()
+	if err := psc.PSubscribe(h.channel + "*"); err != nil {
+		return err
+	}
+	for {
+		switch v := psc.Receive().(type) {
+		case redis.Message:
+			msg := &Message{}
+			if err := json.Unmarshal(v.Data, msg); err != nil {
+				h.errorLog.Printf("pubsub: invalid message on %s: %s", v.Channel, err)
+				continue
+			}
+			msg.Topic = strings.TrimPrefix(v.Channel, h.channel)
+			h.deliver(msg)
+		case error:
+			return v
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package recommend
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+)
+
+// Weights of the parts of the content similarity. They add up to one.
+const (
+	genreWeight       = 0.35
+	authorWeight      = 0.30
+	languageWeight    = 0.10
+	descriptionWeight = 0.25
+)
+
+// ContentOptions tunes the content similarity computation
+type ContentOptions struct {
+	// MinScore is the least score two books must have to be similar
+	MinScore float64
+	// TopK is the number of similar books kept for every book
+	TopK int
+}
+
+// stopWords are the common english words left out of the descriptions
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "are": true, "but": true, "not": true, "you": true, "all": true,
+	"any": true, "can": true, "had": true, "her": true, "was": true, "one": true, "our": true, "out": true,
+	"has": true, "him": true, "his": true, "how": true, "its": true, "who": true, "she": true, "they": true,
+	"this": true, "that": true, "with": true, "from": true, "have": true, "been": true, "were": true,
+	"what": true, "when": true, "where": true, "which": true, "will": true, "would": true, "their": true,
+	"there": true, "them": true, "then": true, "than": true, "into": true, "about": true, "after": true,
+	"before": true, "more": true, "most": true, "some": true, "such": true, "only": true, "also": true,
+	"each": true, "other": true, "over": true, "your": true, "book": true, "books": true,
+}
+
+// ContentIndex compares the books by their genres, authors, languages and the TF-IDF cosine similarity of their descriptions.
+// The inverse document frequencies are computed from the books the index is built from.
+type ContentIndex struct {
+	books   []*models.BookFeatures
+	byID    map[int]*models.BookFeatures
+	vectors map[int]map[string]float64
+}
+
+// NewContentIndex builds the index of the books
+func NewContentIndex(books []*models.BookFeatures) *ContentIndex {
+	ix := &ContentIndex{
+		books:   books,
+		byID:    make(map[int]*models.BookFeatures),
+		vectors: make(map[int]map[string]float64),
+	}
+	counts := make(map[int]map[string]int)
+	df := make(map[string]int)
+	for _, book := range books {
+		ix.byID[book.BookID] = book
+		tf := make(map[string]int)
+		for _, term := range tokenize(book.Description) {
+			tf[term]++
+		}
+		for term := range tf {
+			df[term]++
+		}
+		counts[book.BookID] = tf
+	}
+	n := float64(len(books))
+	for id, tf := range counts {
+		vector := make(map[string]float64)
+		var norm float64
+		for term, count := range tf {
+			w := (1 + math.Log(float64(count))) * (math.Log((n+1)/(float64(df[term])+1)) + 1)
+			vector[term] = w
+			norm += w * w
+		}
+		norm = math.Sqrt(norm)
+		for term := range vector {
+			vector[term] /= norm
+		}
+		ix.vectors[id] = vector
+	}
+	return ix
+}
+
+// Similar returns the TopK books most similar to the book ordered by score.
+// It returns nothing when the book is not in the index.
+func (ix *ContentIndex) Similar(book_id int, opts ContentOptions, computedAt time.Time) []*models.BookSimilarity {
+	book, ok := ix.byID[book_id]
+	if !ok {
+		return nil
+	}
+	similarities := []*models.BookSimilarity{}
+	for _, other := range ix.books {
+		if other.BookID == book.BookID {
+			continue
+		}
+		score := genreWeight*jaccard(book.GenreIDs, other.GenreIDs) +
+			authorWeight*jaccard(book.AuthorIDs, other.AuthorIDs) +
+			languageWeight*jaccard(book.LanguageIDs, other.LanguageIDs) +
+			descriptionWeight*cosine(ix.vectors[book.BookID], ix.vectors[other.BookID])
+		if score <= 0 || score < opts.MinScore {
+			continue
+		}
+		similarities = append(similarities, &models.BookSimilarity{
+			BookID:        book.BookID,
+			SimilarBookID: other.BookID,
+			Score:         score,
+			ComputedAt:    computedAt,
+		})
+	}
+	sort.Slice(similarities, func(i, j int) bool {
+		if similarities[i].Score != similarities[j].Score {
+			return similarities[i].Score > similarities[j].Score
+		}
+		return similarities[i].SimilarBookID < similarities[j].SimilarBookID
+	})
+	if opts.TopK > 0 && len(similarities) > opts.TopK {
+		similarities = similarities[:opts.TopK]
+	}
+	return similarities
+}
+
+// All returns the similar books of every book in the index
+func (ix *ContentIndex) All(opts ContentOptions, computedAt time.Time) []*models.BookSimilarity {
+	similarities := []*models.BookSimilarity{}
+	for _, book := range ix.books {
+		similarities = append(similarities, ix.Similar(book.BookID, opts, computedAt)...)
+	}
+	return similarities
+}
+
+// tokenize splits the text into lower case words of at least three letters leaving out the stop words
+func tokenize(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	terms := []string{}
+	for _, word := range words {
+		if len([]rune(word)) < 3 || stopWords[word] {
+			continue
+		}
+		terms = append(terms, word)
+	}
+	return terms
+}
+
+// jaccard returns the number of ids the lists share divided by the number of distinct ids in both of them
+func jaccard(a, b []int) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	set := make(map[int]bool, len(a))
+	for _, id := range a {
+		set[id] = true
+	}
+	union := len(set)
+	shared := 0
+	seen := make(map[int]bool, len(b))
+	for _, id := range b {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if set[id] {
+			shared++
+		} else {
+			union++
+		}
+	}
+	return float64(shared) / float64(union)
+}
+
+// cosine returns the cosine similarity of the normalized vectors
+func cosine(a, b map[string]float64) float64 {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	var dot float64
+	for term, w := range a {
+		dot += w * b[term]
+	}
+	return dot
+}
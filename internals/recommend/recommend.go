@@ -0,0 +1,96 @@
+// Package recommend computes the item to item similarity of the books from the interest readers showed in them.
+// Two books are similar when the same readers reviewed, listed or shelved both of them.
+// Books nobody showed interest in yet are compared by their content instead, see ContentIndex.
+package recommend
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+)
+
+// Options tunes the similarity computation
+type Options struct {
+	// MinSupport is the least number of readers two books must share to be similar
+	MinSupport int
+	// TopK is the number of similar books kept for every book
+	TopK int
+	// MaxPerUser is the number of the heaviest interactions of a reader taken into account,
+	// so that a few readers with huge lists do not dominate the computation. Zero keeps all of them.
+	MaxPerUser int
+}
+
+// pair is an unordered pair of books with the smaller id first
+type pair struct {
+	a, b int
+}
+
+// cooccurrence is the sum of the products of the weights of a pair and the number of readers sharing it
+type cooccurrence struct {
+	dot     float64
+	support int
+}
+
+// Similarities returns the cosine similarity of the books sharing at least MinSupport readers.
+// Every book keeps its TopK most similar books ordered by score.
+func Similarities(interactions []*models.BookInteraction, opts Options, computedAt time.Time) []*models.BookSimilarity {
+	byUser := make(map[int][]*models.BookInteraction)
+	norms := make(map[int]float64)
+	for _, interaction := range interactions {
+		if interaction.Weight <= 0 {
+			continue
+		}
+		byUser[interaction.UserID] = append(byUser[interaction.UserID], interaction)
+	}
+
+	pairs := make(map[pair]*cooccurrence)
+	for _, books := range byUser {
+		if opts.MaxPerUser > 0 && len(books) > opts.MaxPerUser {
+			sort.Slice(books, func(i, j int) bool { return books[i].Weight > books[j].Weight })
+			books = books[:opts.MaxPerUser]
+		}
+		for i, x := range books {
+			norms[x.BookID] += x.Weight * x.Weight
+			for _, y := range books[i+1:] {
+				key := pair{x.BookID, y.BookID}
+				if key.a > key.b {
+					key.a, key.b = key.b, key.a
+				}
+				c, ok := pairs[key]
+				if !ok {
+					c = &cooccurrence{}
+					pairs[key] = c
+				}
+				c.dot += x.Weight * y.Weight
+				c.support++
+			}
+		}
+	}
+
+	similar := make(map[int][]*models.BookSimilarity)
+	for key, c := range pairs {
+		if c.support < opts.MinSupport {
+			continue
+		}
+		score := c.dot / (math.Sqrt(norms[key.a]) * math.Sqrt(norms[key.b]))
+		similar[key.a] = append(similar[key.a], &models.BookSimilarity{BookID: key.a, SimilarBookID: key.b, Score: score, Support: c.support, ComputedAt: computedAt})
+		similar[key.b] = append(similar[key.b], &models.BookSimilarity{BookID: key.b, SimilarBookID: key.a, Score: score, Support: c.support, ComputedAt: computedAt})
+	}
+
+	similarities := []*models.BookSimilarity{}
+	for _, books := range similar {
+		sort.Slice(books, func(i, j int) bool {
+			if books[i].Score != books[j].Score {
+				return books[i].Score > books[j].Score
+			}
+			return books[i].SimilarBookID < books[j].SimilarBookID
+		})
+		if opts.TopK > 0 && len(books) > opts.TopK {
+			books = books[:opts.TopK]
+		}
+		similarities = append(similarities, books...)
+	}
+	return similarities
+}
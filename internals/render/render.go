@@ -4,14 +4,15 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"html/template"
 	"log"
 	"net/http"
 	"path/filepath"
-	"text/template"
 	"time"
 
 	"github.com/ishanshre/Book-Review-Platform/internals/config"
 	"github.com/ishanshre/Book-Review-Platform/internals/models"
+	"github.com/ishanshre/Book-Review-Platform/internals/sanitize"
 	"github.com/justinas/nosurf"
 )
 
@@ -88,6 +89,7 @@ func CreateTemplateCache() (map[string]*template.Template, error) {
 	funcMap := template.FuncMap{
 		"TimeSince": TimeSince,
 		"DateOnly":  DateOnly,
+		"Markdown":  Markdown,
 	}
 
 	// path pattern to layout and pages template.
@@ -171,3 +173,8 @@ func TimeSince(t time.Time) string {
 func DateOnly(t time.Time) string {
 	return t.Format(time.DateOnly)
 }
+
+// Markdown renders the Markdown text as sanitized HTML that the templates do not escape again
+func Markdown(src string) template.HTML {
+	return template.HTML(sanitize.Markdown(src))
+}
@@ -0,0 +1,147 @@
+package dbrepo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+)
+
+// activityFrom joins the activities with their users and subjects. Activities about inactive books or reviews
+// and private shelves are left out, so is the activity of users who made it private.
+const activityFrom = `
+	FROM activities AS a
+	JOIN users AS u ON u.id = a.user_id
+	LEFT JOIN privacy_settings AS ps ON ps.user_id = a.user_id
+	LEFT JOIN books AS b ON b.id = a.book_id
+	LEFT JOIN reviews AS r ON r.id = a.review_id
+	LEFT JOIN shelves AS s ON s.id = a.shelf_id
+	LEFT JOIN authors AS au ON au.id = a.author_id
+	WHERE (a.book_id IS NULL OR b.is_active)
+		AND (a.review_id IS NULL OR r.is_active)
+		AND (a.shelf_id IS NULL OR s.is_public)
+`
+
+// activityShown leaves out the activities about the reviews, finished books, shelves and followed authors
+// the user hid from their public profile
+const activityShown = `
+	AND (a.type <> 'review_posted' OR COALESCE(ps.show_reviews, TRUE))
+	AND (a.type <> 'book_finished' OR COALESCE(ps.show_stats, TRUE))
+	AND (a.type <> 'shelf_created' OR COALESCE(ps.show_shelves, TRUE))
+	AND (a.type <> 'author_followed' OR COALESCE(ps.show_followed_authors, TRUE))
+`
+
+// activityColumns are the columns of an activity in the order they are scanned by activities
+const activityColumns = `
+	a.id, a.user_id, u.username, a.type,
+	COALESCE(a.book_id, 0), COALESCE(a.review_id, 0), COALESCE(a.shelf_id, 0), COALESCE(a.author_id, 0),
+	COALESCE(r.rating, 0), COALESCE(b.isbn, 0), COALESCE(s.share_token, ''),
+	COALESCE(b.title, s.name, au.first_name || ' ' || au.last_name, ''),
+	a.created_at
+`
+
+// InsertActivity records the activity of the user
+func (m *postgresDBRepo) InsertActivity(activity *models.Activity) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	stmt := `
+		INSERT INTO activities (user_id, type, book_id, review_id, shelf_id, author_id, created_at)
+		VALUES ($1, $2, NULLIF($3, 0), NULLIF($4, 0), NULLIF($5, 0), NULLIF($6, 0), $7)
+		RETURNING id
+	`
+	return m.DB.QueryRowContext(ctx, stmt,
+		activity.UserID,
+		activity.Type,
+		activity.BookID,
+		activity.ReviewID,
+		activity.ShelfID,
+		activity.AuthorID,
+		activity.CreatedAt,
+	).Scan(&activity.ID)
+}
+
+// GetFollowedActivities returns the newest activities of the users the user follows
+func (m *postgresDBRepo) GetFollowedActivities(user_id, limit, page int) (*models.ActivityApi, error) {
+	filter := activityFrom + `
+		AND a.user_id IN (SELECT followee_id FROM user_follows WHERE follower_id = $1)
+		AND COALESCE(ps.activity_visibility, 'public') <> 'private'
+	` + activityShown
+	return m.activities(filter, user_id, limit, page)
+}
+
+// GetUserActivities returns the newest activities of the user without checking their activity visibility.
+// The activities hidden from their public profile are left out unless owner is true.
+func (m *postgresDBRepo) GetUserActivities(user_id, limit, page int, owner bool) (*models.ActivityApi, error) {
+	filter := activityFrom + ` AND a.user_id = $1`
+	if !owner {
+		filter += activityShown
+	}
+	return m.activities(filter, user_id, limit, page)
+}
+
+// activities returns the page of the activities matching the filter, the user id is its first argument
+func (m *postgresDBRepo) activities(filter string, user_id, limit, page int) (*models.ActivityApi, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if limit <= 0 {
+		limit = 20
+	}
+	if page <= 0 {
+		page = 1
+	}
+	offset := (page - 1) * limit
+	var count int
+	if err := m.DB.QueryRowContext(ctx, `SELECT COUNT(*) `+filter, user_id).Scan(&count); err != nil {
+		return nil, err
+	}
+	query := `SELECT ` + activityColumns + filter + ` ORDER BY a.created_at DESC, a.id DESC LIMIT $2 OFFSET $3`
+	rows, err := m.DB.QueryContext(ctx, query, user_id, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	activities := []*models.Activity{}
+	for rows.Next() {
+		activity := &models.Activity{}
+		var isbn int64
+		var shareToken string
+		if err := rows.Scan(
+			&activity.ID,
+			&activity.UserID,
+			&activity.Username,
+			&activity.Type,
+			&activity.BookID,
+			&activity.ReviewID,
+			&activity.ShelfID,
+			&activity.AuthorID,
+			&activity.Rating,
+			&isbn,
+			&shareToken,
+			&activity.Subject,
+			&activity.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		switch activity.Type {
+		case models.ActivityReviewPosted:
+			activity.Link = fmt.Sprintf("/books/%d#review-%d", isbn, activity.ReviewID)
+		case models.ActivityBookFinished:
+			activity.Link = fmt.Sprintf("/books/%d", isbn)
+		case models.ActivityShelfCreated:
+			activity.Link = "/shelves/" + shareToken
+		case models.ActivityAuthorFollowed:
+			activity.Link = fmt.Sprintf("/authors/%d", activity.AuthorID)
+		}
+		activities = append(activities, activity)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return &models.ActivityApi{
+		Total:      count,
+		Page:       page,
+		LastPage:   m.CalculateLastPage(limit, count),
+		Activities: activities,
+	}, nil
+}
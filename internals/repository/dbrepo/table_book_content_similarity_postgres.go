@@ -0,0 +1,151 @@
+package dbrepo
+
+import (
+	"context"
+	"time"
+
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+)
+
+// BookFeatures returns the description, genres, authors and languages of every active book
+func (m *postgresDBRepo) BookFeatures() ([]*models.BookFeatures, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	rows, err := m.DB.QueryContext(ctx, `SELECT id, COALESCE(description, '') FROM books WHERE is_active ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	books := []*models.BookFeatures{}
+	byID := make(map[int]*models.BookFeatures)
+	for rows.Next() {
+		book := &models.BookFeatures{}
+		if err := rows.Scan(&book.BookID, &book.Description); err != nil {
+			return nil, err
+		}
+		books = append(books, book)
+		byID[book.BookID] = book
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	relations := []struct {
+		query string
+		add   func(book *models.BookFeatures, id int)
+	}{
+		{`SELECT book_id, genre_id FROM book_genres`, func(book *models.BookFeatures, id int) { book.GenreIDs = append(book.GenreIDs, id) }},
+		{`SELECT book_id, author_id FROM book_authors`, func(book *models.BookFeatures, id int) { book.AuthorIDs = append(book.AuthorIDs, id) }},
+		{`SELECT book_id, language_id FROM book_languages`, func(book *models.BookFeatures, id int) { book.LanguageIDs = append(book.LanguageIDs, id) }},
+	}
+	for _, relation := range relations {
+		if err := m.scanBookRelation(ctx, relation.query, byID, relation.add); err != nil {
+			return nil, err
+		}
+	}
+	return books, nil
+}
+
+// scanBookRelation adds the related ids returned by the query to the features of their books
+func (m *postgresDBRepo) scanBookRelation(ctx context.Context, query string, byID map[int]*models.BookFeatures, add func(book *models.BookFeatures, id int)) error {
+	rows, err := m.DB.QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var book_id, id int
+		if err := rows.Scan(&book_id, &id); err != nil {
+			return err
+		}
+		if book, ok := byID[book_id]; ok {
+			add(book, id)
+		}
+	}
+	return rows.Err()
+}
+
+// ReplaceBookContentSimilarities replaces the content similarities of the book in a transaction.
+// Every similarity is also stored the other way round so the book shows up among the similar books of its neighbours.
+func (m *postgresDBRepo) ReplaceBookContentSimilarities(book_id int, similarities []*models.BookSimilarity) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM book_content_similarities WHERE book_id = $1 OR similar_book_id = $1`,
+		book_id,
+	); err != nil {
+		return err
+	}
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO book_content_similarities (book_id, similar_book_id, score, computed_at)
+		VALUES ($1, $2, $3, $4), ($2, $1, $3, $4)
+		ON CONFLICT (book_id, similar_book_id) DO UPDATE SET score = EXCLUDED.score, computed_at = EXCLUDED.computed_at
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for _, similarity := range similarities {
+		if _, err := stmt.ExecContext(ctx,
+			similarity.BookID,
+			similarity.SimilarBookID,
+			similarity.Score,
+			similarity.ComputedAt,
+		); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// ReplaceAllBookContentSimilarities replaces all the content similarities with the newly computed ones in a transaction
+func (m *postgresDBRepo) ReplaceAllBookContentSimilarities(similarities []*models.BookSimilarity) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, `DELETE FROM book_content_similarities`); err != nil {
+		return err
+	}
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO book_content_similarities (book_id, similar_book_id, score, computed_at)
+		VALUES ($1, $2, $3, $4)
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for _, similarity := range similarities {
+		if _, err := stmt.ExecContext(ctx,
+			similarity.BookID,
+			similarity.SimilarBookID,
+			similarity.Score,
+			similarity.ComputedAt,
+		); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetContentSimilarBooks returns the active books whose genres, authors, languages and description are the most similar to the book
+func (m *postgresDBRepo) GetContentSimilarBooks(book_id, limit int) ([]*models.RecommendedBook, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `
+		SELECT b.id, b.title, b.isbn, COALESCE(b.cover, ''), bcs.score
+		FROM book_content_similarities AS bcs
+		JOIN books AS b ON b.id = bcs.similar_book_id
+		WHERE bcs.book_id = $1 AND b.is_active
+		ORDER BY bcs.score DESC, b.id ASC
+		LIMIT $2
+	`
+	return m.recommendedBooks(ctx, query, models.RecommendationContent, book_id, limit)
+}
@@ -215,9 +215,10 @@ func (m *postgresDBRepo) InsertBook(u *models.Book) error {
 	defer cancel()
 	stmt := `
 		INSERT INTO books (title, description, cover, isbn, published_date, paperback, is_active, added_at, updated_at, publisher_id)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10);
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id;
 	`
-	_, err := m.DB.ExecContext(
+	err := m.DB.QueryRowContext(
 		ctx,
 		stmt,
 		u.Title,
@@ -230,7 +231,7 @@ func (m *postgresDBRepo) InsertBook(u *models.Book) error {
 		u.AddedAt,
 		u.UpdatedAt,
 		u.PublisherID,
-	)
+	).Scan(&u.ID)
 	if err != nil {
 		return err
 	}
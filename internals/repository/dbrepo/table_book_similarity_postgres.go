@@ -0,0 +1,184 @@
+package dbrepo
+
+import (
+	"context"
+	"time"
+
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+)
+
+// bookInteractions weights the interest of every user in every book.
+// Positive reviews weigh the most, abandoned books and negative reviews are left out and
+// the weight of a book is capped so that a single reader cannot dominate its similarity.
+const bookInteractions = `
+	SELECT user_id, book_id, LEAST(SUM(weight), 4) AS weight
+	FROM (
+		SELECT user_id, book_id, CASE WHEN rating >= 4 THEN 2.0 ELSE 1.0 END AS weight
+		FROM reviews
+		WHERE is_active AND rating >= 3
+		UNION ALL
+		SELECT user_id, book_id, CASE WHEN status = 'finished' THEN 1.5 ELSE 1.0 END
+		FROM read_lists
+		WHERE status <> 'abandoned'
+		UNION ALL
+		SELECT user_id, book_id, 1.0
+		FROM buy_lists
+		UNION ALL
+		SELECT s.user_id, sb.book_id, 0.5
+		FROM shelf_books AS sb
+		JOIN shelves AS s ON s.id = sb.shelf_id
+	) AS interactions
+	WHERE user_id IS NOT NULL AND book_id IS NOT NULL
+	GROUP BY user_id, book_id
+`
+
+// BookInteractions returns the weighted interest of every user in every book
+func (m *postgresDBRepo) BookInteractions() ([]*models.BookInteraction, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	rows, err := m.DB.QueryContext(ctx, bookInteractions)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	interactions := []*models.BookInteraction{}
+	for rows.Next() {
+		interaction := &models.BookInteraction{}
+		if err := rows.Scan(&interaction.UserID, &interaction.BookID, &interaction.Weight); err != nil {
+			return nil, err
+		}
+		interactions = append(interactions, interaction)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return interactions, nil
+}
+
+// ReplaceBookSimilarities replaces all the book similarities with the newly computed ones in a transaction
+func (m *postgresDBRepo) ReplaceBookSimilarities(similarities []*models.BookSimilarity) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, `DELETE FROM book_similarities`); err != nil {
+		return err
+	}
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO book_similarities (book_id, similar_book_id, score, support, computed_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for _, similarity := range similarities {
+		if _, err := stmt.ExecContext(ctx,
+			similarity.BookID,
+			similarity.SimilarBookID,
+			similarity.Score,
+			similarity.Support,
+			similarity.ComputedAt,
+		); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetSimilarBooks returns the active books most similar to the book
+func (m *postgresDBRepo) GetSimilarBooks(book_id, limit int) ([]*models.Book, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `
+		SELECT b.id, b.title, b.isbn, COALESCE(b.cover, '')
+		FROM book_similarities AS bs
+		JOIN books AS b ON b.id = bs.similar_book_id
+		WHERE bs.book_id = $1 AND b.is_active
+		ORDER BY bs.score DESC, b.id ASC
+		LIMIT $2
+	`
+	rows, err := m.DB.QueryContext(ctx, query, book_id, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	books := []*models.Book{}
+	for rows.Next() {
+		book := &models.Book{}
+		if err := rows.Scan(&book.ID, &book.Title, &book.Isbn, &book.Cover); err != nil {
+			return nil, err
+		}
+		books = append(books, book)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return books, nil
+}
+
+// GetRecommendations returns the active books most similar to the books the user showed interest in,
+// weighted by that interest. The books the user already interacted with are left out.
+func (m *postgresDBRepo) GetRecommendations(user_id, limit int) ([]*models.RecommendedBook, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `
+		WITH seeds AS (
+			SELECT book_id, weight FROM (` + bookInteractions + `) AS interactions
+			WHERE user_id = $1
+		)
+		SELECT b.id, b.title, b.isbn, COALESCE(b.cover, ''), SUM(s.weight * bs.score) AS score
+		FROM seeds AS s
+		JOIN book_similarities AS bs ON bs.book_id = s.book_id
+		JOIN books AS b ON b.id = bs.similar_book_id
+		WHERE b.is_active AND bs.similar_book_id NOT IN (SELECT book_id FROM seeds)
+		GROUP BY b.id
+		ORDER BY score DESC, b.id ASC
+		LIMIT $2
+	`
+	return m.recommendedBooks(ctx, query, models.RecommendationPersonalized, user_id, limit)
+}
+
+// PopularBooks returns the active books readers showed the most interest in, then the newest books,
+// leaving out the books the user interacted with. The user id is zero for anonymous visitors.
+func (m *postgresDBRepo) PopularBooks(user_id, limit int) ([]*models.RecommendedBook, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `
+		WITH interactions AS (` + bookInteractions + `),
+		popularity AS (
+			SELECT book_id, SUM(weight) AS score FROM interactions GROUP BY book_id
+		)
+		SELECT b.id, b.title, b.isbn, COALESCE(b.cover, ''), COALESCE(p.score, 0) AS score
+		FROM books AS b
+		LEFT JOIN popularity AS p ON p.book_id = b.id
+		WHERE b.is_active AND b.id NOT IN (SELECT book_id FROM interactions WHERE user_id = $1)
+		ORDER BY score DESC, b.added_at DESC NULLS LAST, b.id ASC
+		LIMIT $2
+	`
+	return m.recommendedBooks(ctx, query, models.RecommendationPopular, user_id, limit)
+}
+
+// recommendedBooks runs the recommendation query and scans the books with their score
+func (m *postgresDBRepo) recommendedBooks(ctx context.Context, query, source string, args ...any) ([]*models.RecommendedBook, error) {
+	rows, err := m.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	books := []*models.RecommendedBook{}
+	for rows.Next() {
+		book := &models.RecommendedBook{Source: source}
+		if err := rows.Scan(&book.ID, &book.Title, &book.Isbn, &book.Cover, &book.Score); err != nil {
+			return nil, err
+		}
+		books = append(books, book)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return books, nil
+}
@@ -7,6 +7,9 @@ import (
 	"github.com/ishanshre/Book-Review-Platform/internals/models"
 )
 
+// contactColumns selects the contact in the order scanned by the contact methods
+const contactColumns = `id, first_name, last_name, email, phone, subject, message, submitted_at, ip_address, browser_info, referring_page, is_active`
+
 // AllContacts fetches all the records from contacts db table.
 func (m *postgresDBRepo) AllContacts() ([]*models.Contact, error) {
 	// Create a timeout of 3 second with context
@@ -14,7 +17,7 @@ func (m *postgresDBRepo) AllContacts() ([]*models.Contact, error) {
 	defer cancel()
 
 	// prepare the sql statement
-	query := `SELECT * FROM contacts`
+	query := `SELECT ` + contactColumns + ` FROM contacts ORDER BY id`
 
 	// Execute the query using Query Context.
 	// If any error occurs, nil and error is returned
@@ -43,6 +46,7 @@ func (m *postgresDBRepo) AllContacts() ([]*models.Contact, error) {
 			&contact.IpAddress,
 			&contact.BrowserInfo,
 			&contact.ReferringPage,
+			&contact.IsActive,
 		); err != nil {
 			return nil, err
 		}
@@ -66,7 +70,7 @@ func (m *postgresDBRepo) GetContactByID(id int) (*models.Contact, error) {
 
 	// Preparing the query statement
 	query := `
-		SELECT * FROM contacts
+		SELECT ` + contactColumns + ` FROM contacts
 		WHERE id=$1
 	`
 
@@ -89,6 +93,7 @@ func (m *postgresDBRepo) GetContactByID(id int) (*models.Contact, error) {
 		&contact.IpAddress,
 		&contact.BrowserInfo,
 		&contact.ReferringPage,
+		&contact.IsActive,
 	); err != nil {
 		return nil, err
 	}
@@ -118,7 +123,7 @@ func (m *postgresDBRepo) DeleteContact(id int) error {
 // InsertContact add new contact to contacts table to db
 // Takes Contact model as a parameter
 // Returns an error if something goes wrong
-func (m *postgresDBRepo) InsertContact(u *models.Contact) error {
+func (m *postgresDBRepo) InsertContact(u *models.Contact, mails ...*models.MailData) error {
 
 	// Create a timeout of 3 second with context
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
@@ -126,27 +131,36 @@ func (m *postgresDBRepo) InsertContact(u *models.Contact) error {
 
 	// Prepare a insert query statement
 	stmt := `
-		INSERT INTO contacts (first_name, last_name, email, phone, subject, message, submitted_at, ip_address, browser_info, referring_page)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10);
+		INSERT INTO contacts (first_name, last_name, email, phone, subject, message, submitted_at, ip_address, browser_info, referring_page, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id;
 	`
 
-	// Executing the query
-	_, err := m.DB.ExecContext(
-		ctx,
-		stmt,
-		u.FirstName,
-		u.LastName,
-		u.Email,
-		u.Phone,
-		u.Subject,
-		u.Message,
-		u.SubmittedAt,
-		u.IpAddress,
-		u.BrowserInfo,
-		u.ReferringPage,
-	)
-	if err != nil {
-		return err
-	}
-	return nil
+	// Executing the query and store the id of the new contact
+	return m.execWithMails(ctx, mails, func(db execer) error {
+		return db.QueryRowContext(
+			ctx,
+			stmt,
+			u.FirstName,
+			u.LastName,
+			u.Email,
+			u.Phone,
+			u.Subject,
+			u.Message,
+			u.SubmittedAt,
+			u.IpAddress,
+			u.BrowserInfo,
+			u.ReferringPage,
+			u.IsActive,
+		).Scan(&u.ID)
+	})
+}
+
+// SetContactActive approves or holds the contact message
+func (m *postgresDBRepo) SetContactActive(id int, is_active bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `UPDATE contacts SET is_active = $1 WHERE id = $2`
+	_, err := m.DB.ExecContext(ctx, query, is_active, id)
+	return err
 }
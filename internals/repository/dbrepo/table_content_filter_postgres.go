@@ -0,0 +1,187 @@
+package dbrepo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+)
+
+// AllFilterWords returns the word list of the content filter ordered by word
+func (m *postgresDBRepo) AllFilterWords() ([]*models.FilterWord, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `SELECT id, word, verdict, created_at FROM filter_words ORDER BY word`
+	rows, err := m.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	words := []*models.FilterWord{}
+	for rows.Next() {
+		word := &models.FilterWord{}
+		if err := rows.Scan(&word.ID, &word.Word, &word.Verdict, &word.CreatedAt); err != nil {
+			return nil, err
+		}
+		words = append(words, word)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return words, nil
+}
+
+// FilterWordExists returns true if the word is already in the word list
+func (m *postgresDBRepo) FilterWordExists(word string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `SELECT EXISTS(SELECT 1 FROM filter_words WHERE LOWER(word) = LOWER($1))`
+	var exists bool
+	if err := m.DB.QueryRowContext(ctx, query, word).Scan(&exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// InsertFilterWord adds the word to the word list
+func (m *postgresDBRepo) InsertFilterWord(w *models.FilterWord) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `
+		INSERT INTO filter_words (word, verdict, created_at)
+		VALUES ($1, $2, $3)
+		RETURNING id
+	`
+	return m.DB.QueryRowContext(ctx, query, w.Word, w.Verdict, w.CreatedAt).Scan(&w.ID)
+}
+
+// DeleteFilterWord removes the word from the word list
+func (m *postgresDBRepo) DeleteFilterWord(id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	_, err := m.DB.ExecContext(ctx, `DELETE FROM filter_words WHERE id = $1`, id)
+	return err
+}
+
+// InsertContentFilterLog stores the verdict of the content filter on a submission
+func (m *postgresDBRepo) InsertContentFilterLog(l *models.ContentFilterLog) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `
+		INSERT INTO content_filter_logs (kind, target_id, user_id, verdict, reasons, content, ip_address, created_at)
+		VALUES ($1, NULLIF($2, 0), NULLIF($3, 0), $4, $5, $6, $7, $8)
+		RETURNING id
+	`
+	return m.DB.QueryRowContext(ctx, query,
+		l.Kind,
+		l.TargetID,
+		l.UserID,
+		l.Verdict,
+		l.Reasons,
+		l.Content,
+		l.IpAddress,
+		l.CreatedAt,
+	).Scan(&l.ID)
+}
+
+// RecentContentFilterContents returns the contents of the latest submissions of the kind since the given time
+// leaving out the submissions of the excluded user, if any.
+// Rejected submissions are included so that repeated spam is caught even though it was never stored.
+func (m *postgresDBRepo) RecentContentFilterContents(kind string, exclude_user_id int, since time.Time, limit int) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `
+		SELECT content FROM content_filter_logs
+		WHERE kind = $1 AND created_at >= $2
+		AND ($3::int = 0 OR user_id IS DISTINCT FROM $3::int)
+		ORDER BY created_at DESC
+		LIMIT $4
+	`
+	rows, err := m.DB.QueryContext(ctx, query, kind, since, exclude_user_id, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	contents := []string{}
+	for rows.Next() {
+		var content string
+		if err := rows.Scan(&content); err != nil {
+			return nil, err
+		}
+		contents = append(contents, content)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return contents, nil
+}
+
+// ContentFilterLogFilter returns the filtered content filter logs.
+// The verdict filters the logs by allow, hold or reject.
+func (m *postgresDBRepo) ContentFilterLogFilter(limit, page int, searchKey, verdict, sort string) (*models.ContentFilterLogFilterApi, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	if limit <= 0 {
+		limit = 10
+	}
+	if page <= 0 {
+		page = 1
+	}
+	offset := (page - 1) * limit
+	where := `
+		FROM content_filter_logs l
+		LEFT JOIN users u ON u.id = l.user_id
+		WHERE (l.content ILIKE $1 OR l.reasons ILIKE $1 OR l.ip_address ILIKE $1 OR COALESCE(u.username, '') ILIKE $1)
+		AND ($2::text = '' OR l.verdict = $2::text)
+	`
+	if sort != "asc" {
+		sort = "desc"
+	}
+	query := fmt.Sprintf(`
+		SELECT l.id, l.kind, COALESCE(l.target_id, 0), COALESCE(l.user_id, 0), COALESCE(u.username, ''),
+			l.verdict, l.reasons, l.content, l.ip_address, l.created_at
+		%s ORDER BY l.created_at %s LIMIT %d OFFSET %d
+	`, where, sort, limit, offset)
+	countQuery := fmt.Sprintf("SELECT COUNT(*) %s", where)
+	search := fmt.Sprintf("%%%s%%", searchKey)
+	var count int
+	if err := m.DB.QueryRowContext(ctx, countQuery, search, verdict).Scan(&count); err != nil {
+		return nil, err
+	}
+	rows, err := m.DB.QueryContext(ctx, query, search, verdict)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	logs := []*models.ContentFilterLog{}
+	for rows.Next() {
+		log := &models.ContentFilterLog{}
+		if err := rows.Scan(
+			&log.ID,
+			&log.Kind,
+			&log.TargetID,
+			&log.UserID,
+			&log.Username,
+			&log.Verdict,
+			&log.Reasons,
+			&log.Content,
+			&log.IpAddress,
+			&log.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		logs = append(logs, log)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	lastPage := m.CalculateLastPage(limit, count)
+	return &models.ContentFilterLogFilterApi{
+		Total:    count,
+		Page:     page,
+		LastPage: lastPage,
+		Logs:     logs,
+	}, nil
+}
@@ -0,0 +1,223 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ishanshre/Book-Review-Platform/internals/helpers"
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+)
+
+// GetDigestPreference returns the digest preference of the user.
+// A preference with a new unsubscribe token is created when the user has none.
+func (m *postgresDBRepo) GetDigestPreference(user_id int) (*models.DigestPreference, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	token, err := helpers.GenerateRandomToken(24)
+	if err != nil {
+		return nil, err
+	}
+	stmt := `
+		INSERT INTO digest_preferences (user_id, frequency, unsubscribe_token, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id) DO NOTHING
+	`
+	if _, err := m.DB.ExecContext(ctx, stmt, user_id, models.DigestNever, token, time.Now()); err != nil {
+		return nil, err
+	}
+	query := `
+		SELECT user_id, frequency, unsubscribe_token, last_sent_at, updated_at
+		FROM digest_preferences
+		WHERE user_id = $1
+	`
+	preference := &models.DigestPreference{}
+	if err := m.DB.QueryRowContext(ctx, query, user_id).Scan(
+		&preference.UserID,
+		&preference.Frequency,
+		&preference.UnsubscribeToken,
+		&preference.LastSentAt,
+		&preference.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return preference, nil
+}
+
+// UpdateDigestFrequency changes how often the user receives the digest
+func (m *postgresDBRepo) UpdateDigestFrequency(user_id int, frequency string) error {
+	if _, err := m.GetDigestPreference(user_id); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	stmt := `
+		UPDATE digest_preferences
+		SET frequency = $2, updated_at = $3
+		WHERE user_id = $1
+	`
+	_, err := m.DB.ExecContext(ctx, stmt, user_id, frequency, time.Now())
+	return err
+}
+
+// UnsubscribeDigest turns off the digest of the user owning the token.
+// It returns false when no user has the token.
+func (m *postgresDBRepo) UnsubscribeDigest(token string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	stmt := `
+		UPDATE digest_preferences
+		SET frequency = $2, updated_at = $3
+		WHERE unsubscribe_token = $1
+	`
+	res, err := m.DB.ExecContext(ctx, stmt, token, models.DigestNever, time.Now())
+	if err != nil {
+		return false, err
+	}
+	rows_affected, _ := res.RowsAffected()
+	return rows_affected > 0, nil
+}
+
+// DigestUnsubscribeTokenExists returns true if the token is the unsubscribe token of a digest preference
+func (m *postgresDBRepo) DigestUnsubscribeTokenExists(token string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM digest_preferences WHERE unsubscribe_token = $1)`
+	if err := m.DB.QueryRowContext(ctx, query, token).Scan(&exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// DueDigestRecipients returns the opted-in users whose last digest is older than their frequency
+func (m *postgresDBRepo) DueDigestRecipients() ([]*models.DigestRecipient, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `
+		SELECT u.id, u.username, u.email, d.frequency, d.unsubscribe_token, d.last_sent_at
+		FROM digest_preferences AS d
+		JOIN users AS u ON u.id = d.user_id
+		WHERE d.frequency <> $1
+		AND (
+			d.last_sent_at IS NULL
+			OR d.last_sent_at <= NOW() - CASE d.frequency
+				WHEN 'daily' THEN INTERVAL '1 day'
+				WHEN 'weekly' THEN INTERVAL '7 days'
+				ELSE INTERVAL '1 month'
+			END
+		)
+	`
+	rows, err := m.DB.QueryContext(ctx, query, models.DigestNever)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	recipients := []*models.DigestRecipient{}
+	for rows.Next() {
+		recipient := &models.DigestRecipient{}
+		if err := rows.Scan(
+			&recipient.UserID,
+			&recipient.Username,
+			&recipient.Email,
+			&recipient.Frequency,
+			&recipient.UnsubscribeToken,
+			&recipient.LastSentAt,
+		); err != nil {
+			return nil, err
+		}
+		recipients = append(recipients, recipient)
+	}
+	return recipients, nil
+}
+
+// MarkDigestSent stores the time the digest of the user was sent
+func (m *postgresDBRepo) MarkDigestSent(user_id int, sent_at time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	stmt := `UPDATE digest_preferences SET last_sent_at = $2 WHERE user_id = $1`
+	_, err := m.DB.ExecContext(ctx, stmt, user_id, sent_at)
+	return err
+}
+
+// GetDigest returns the new books from followed authors, new reviews on books in the read list
+// and the new genres added since the given time
+func (m *postgresDBRepo) GetDigest(user_id int, since time.Time) (*models.Digest, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	digest := &models.Digest{
+		Books:   []*models.DigestBook{},
+		Reviews: []*models.DigestReview{},
+		Genres:  []*models.Genre{},
+	}
+
+	bookQuery := `
+		SELECT DISTINCT b.title, b.isbn, CONCAT(a.first_name, ' ', a.last_name)
+		FROM followers AS f
+		JOIN book_authors AS ba ON ba.author_id = f.author_id
+		JOIN books AS b ON b.id = ba.book_id
+		JOIN authors AS a ON a.id = f.author_id
+		WHERE f.user_id = $1 AND b.added_at > $2 AND b.is_active = true
+		ORDER BY b.title
+		LIMIT 20
+	`
+	if err := m.scanDigestRows(ctx, bookQuery, []interface{}{user_id, since}, func(rows *sql.Rows) error {
+		book := &models.DigestBook{}
+		if err := rows.Scan(&book.Title, &book.Isbn, &book.Author); err != nil {
+			return err
+		}
+		digest.Books = append(digest.Books, book)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	reviewQuery := `
+		SELECT b.title, b.isbn, u.username, r.rating
+		FROM read_lists AS rl
+		JOIN reviews AS r ON r.book_id = rl.book_id
+		JOIN books AS b ON b.id = r.book_id
+		JOIN users AS u ON u.id = r.user_id
+		WHERE rl.user_id = $1 AND r.user_id <> $1 AND r.is_active = true AND r.created_at > $2
+		ORDER BY r.created_at DESC
+		LIMIT 20
+	`
+	if err := m.scanDigestRows(ctx, reviewQuery, []interface{}{user_id, since}, func(rows *sql.Rows) error {
+		review := &models.DigestReview{}
+		if err := rows.Scan(&review.BookTitle, &review.Isbn, &review.Username, &review.Rating); err != nil {
+			return err
+		}
+		digest.Reviews = append(digest.Reviews, review)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	genreQuery := `SELECT id, title FROM genres WHERE created_at > $1 ORDER BY title`
+	if err := m.scanDigestRows(ctx, genreQuery, []interface{}{since}, func(rows *sql.Rows) error {
+		genre := &models.Genre{}
+		if err := rows.Scan(&genre.ID, &genre.Title); err != nil {
+			return err
+		}
+		digest.Genres = append(digest.Genres, genre)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return digest, nil
+}
+
+// scanDigestRows runs the query and calls scan for every row
+func (m *postgresDBRepo) scanDigestRows(ctx context.Context, query string, args []interface{}, scan func(rows *sql.Rows) error) error {
+	rows, err := m.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
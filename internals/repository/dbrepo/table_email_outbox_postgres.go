@@ -0,0 +1,258 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+)
+
+// execer is implemented by both *sql.DB and *sql.Tx
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// insertEmailOutbox stores the mail in the outbox using the given connection or transaction
+func insertEmailOutbox(ctx context.Context, db execer, msg *models.MailData) error {
+	data, err := json.Marshal(msg.Data)
+	if err != nil {
+		return err
+	}
+	stmt := `
+		INSERT INTO email_outbox (to_email, from_email, subject, template, data, content, plain_content, unsubscribe, status, next_attempt_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+	_, err = db.ExecContext(
+		ctx,
+		stmt,
+		msg.To,
+		msg.From,
+		msg.Subject,
+		msg.Template,
+		string(data),
+		msg.Content,
+		msg.PlainContent,
+		msg.Unsubscribe,
+		models.EmailPending,
+		time.Now(),
+		time.Now(),
+	)
+	return err
+}
+
+// execWithMails runs fn and stores the mails in the outbox.
+// When there are mails, both run inside one transaction so the mails are only queued if the change is committed.
+func (m *postgresDBRepo) execWithMails(ctx context.Context, mails []*models.MailData, fn func(db execer) error) error {
+	if len(mails) == 0 {
+		return fn(m.DB)
+	}
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := fn(tx); err != nil {
+		return err
+	}
+	for _, msg := range mails {
+		if err := insertEmailOutbox(ctx, tx, msg); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// EnqueueMail stores the mail in the outbox to be sent by the mail worker
+func (m *postgresDBRepo) EnqueueMail(msg *models.MailData) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	return insertEmailOutbox(ctx, m.DB, msg)
+}
+
+// ClaimPendingEmails returns the pending mails that are due and locks them for the lease duration,
+// so that other workers do not pick them up while they are being sent.
+func (m *postgresDBRepo) ClaimPendingEmails(limit int, lease time.Duration) ([]*models.EmailOutbox, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `
+		UPDATE email_outbox
+		SET next_attempt_at = $3
+		WHERE id IN (
+			SELECT id FROM email_outbox
+			WHERE status = $1 AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, to_email, from_email, subject, template, data, content, plain_content, unsubscribe, status, attempts, max_attempts, last_error, next_attempt_at, created_at, sent_at
+	`
+	rows, err := m.DB.QueryContext(ctx, query, models.EmailPending, limit, time.Now().Add(lease))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	emails := []*models.EmailOutbox{}
+	for rows.Next() {
+		email, err := scanEmailOutbox(rows)
+		if err != nil {
+			return nil, err
+		}
+		emails = append(emails, email)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return emails, nil
+}
+
+// MarkEmailSent marks the mail as sent
+func (m *postgresDBRepo) MarkEmailSent(id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	stmt := `
+		UPDATE email_outbox
+		SET status = $2, attempts = attempts + 1, last_error = '', sent_at = $3
+		WHERE id = $1
+	`
+	_, err := m.DB.ExecContext(ctx, stmt, id, models.EmailSent, time.Now())
+	return err
+}
+
+// MarkEmailAttemptFailed records a failed attempt and schedules the next one.
+// The mail is marked as failed once it has reached its max attempts.
+func (m *postgresDBRepo) MarkEmailAttemptFailed(id int, lastError string, nextAttemptAt time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	stmt := `
+		UPDATE email_outbox
+		SET
+			attempts = attempts + 1,
+			last_error = $2,
+			next_attempt_at = $3,
+			status = CASE WHEN attempts + 1 >= max_attempts THEN $4 ELSE status END
+		WHERE id = $1
+	`
+	_, err := m.DB.ExecContext(ctx, stmt, id, lastError, nextAttemptAt, models.EmailFailed)
+	return err
+}
+
+// ResendEmail puts the mail back to the queue with a fresh attempt count
+func (m *postgresDBRepo) ResendEmail(id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	stmt := `
+		UPDATE email_outbox
+		SET status = $2, attempts = 0, last_error = '', next_attempt_at = $3, sent_at = NULL
+		WHERE id = $1
+	`
+	res, err := m.DB.ExecContext(ctx, stmt, id, models.EmailPending, time.Now())
+	if err != nil {
+		return err
+	}
+	rows_affected, _ := res.RowsAffected()
+	if rows_affected == 0 {
+		return fmt.Errorf("email with id %d does not exists", id)
+	}
+	return nil
+}
+
+// GetEmailOutboxByID returns the mail from the outbox
+func (m *postgresDBRepo) GetEmailOutboxByID(id int) (*models.EmailOutbox, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `
+		SELECT id, to_email, from_email, subject, template, data, content, plain_content, unsubscribe, status, attempts, max_attempts, last_error, next_attempt_at, created_at, sent_at
+		FROM email_outbox
+		WHERE id = $1
+	`
+	return scanEmailOutbox(m.DB.QueryRowContext(ctx, query, id))
+}
+
+// EmailOutboxFilter returns the filtered mails with pagination.
+// Status filters the mails by pending, sent or failed, empty status returns all.
+func (m *postgresDBRepo) EmailOutboxFilter(limit, page int, searchKey, status, sort string) (*models.EmailOutboxFilterApi, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	if limit <= 0 {
+		limit = 10
+	}
+	if page <= 0 {
+		page = 1
+	}
+	offset := (page - 1) * limit
+	sql := `
+		SELECT id, to_email, from_email, subject, template, data, content, plain_content, unsubscribe, status, attempts, max_attempts, last_error, next_attempt_at, created_at, sent_at
+		FROM email_outbox
+		WHERE (to_email ILIKE $1 OR subject ILIKE $1) AND ($2::text = '' OR status = $2::text)
+	`
+	countSql := `
+		SELECT COUNT(*)
+		FROM email_outbox
+		WHERE (to_email ILIKE $1 OR subject ILIKE $1) AND ($2::text = '' OR status = $2::text)
+	`
+	if sort != "asc" {
+		sort = "desc"
+	}
+	sql = fmt.Sprintf("%s ORDER BY created_at %s LIMIT %d OFFSET %d", sql, sort, limit, offset)
+	search := fmt.Sprintf("%%%s%%", searchKey)
+	var count int
+	if err := m.DB.QueryRowContext(ctx, countSql, search, status).Scan(&count); err != nil {
+		return nil, err
+	}
+	rows, err := m.DB.QueryContext(ctx, sql, search, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	emails := []*models.EmailOutbox{}
+	for rows.Next() {
+		email, err := scanEmailOutbox(rows)
+		if err != nil {
+			return nil, err
+		}
+		emails = append(emails, email)
+	}
+	lastPage := m.CalculateLastPage(limit, count)
+	return &models.EmailOutboxFilterApi{
+		Total:    count,
+		Page:     page,
+		LastPage: lastPage,
+		Emails:   emails,
+	}, nil
+}
+
+// scanner is implemented by both *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanEmailOutbox scans the email_outbox columns into EmailOutbox
+func scanEmailOutbox(row scanner) (*models.EmailOutbox, error) {
+	email := &models.EmailOutbox{}
+	if err := row.Scan(
+		&email.ID,
+		&email.To,
+		&email.From,
+		&email.Subject,
+		&email.Template,
+		&email.Data,
+		&email.Content,
+		&email.PlainContent,
+		&email.Unsubscribe,
+		&email.Status,
+		&email.Attempts,
+		&email.MaxAttempts,
+		&email.LastError,
+		&email.NextAttemptAt,
+		&email.CreatedAt,
+		&email.SentAt,
+	); err != nil {
+		return nil, err
+	}
+	return email, nil
+}
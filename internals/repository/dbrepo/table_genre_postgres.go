@@ -14,7 +14,7 @@ import (
 func (m *postgresDBRepo) AllGenre() ([]*models.Genre, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
-	query := `SELECT * FROM genres`
+	query := `SELECT id, title FROM genres`
 	rows, err := m.DB.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
@@ -84,7 +84,7 @@ func (m *postgresDBRepo) DeleteGenre(id int) error {
 func (m *postgresDBRepo) GetGenreByID(id int) (*models.Genre, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
-	query := `SELECT * FROM genres WHERE id=$1`
+	query := `SELECT id, title FROM genres WHERE id=$1`
 	row := m.DB.QueryRowContext(ctx, query, id)
 	u := &models.Genre{}
 	if err := row.Scan(&u.ID, &u.Title); err != nil {
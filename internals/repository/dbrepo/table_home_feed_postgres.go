@@ -0,0 +1,183 @@
+package dbrepo
+
+import (
+	"context"
+	"time"
+
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+	"github.com/lib/pq"
+)
+
+// FeedAuthorBooks returns the newest active books of the authors that are not in the read list of the user.
+// The reason of every book is the name of its author.
+func (m *postgresDBRepo) FeedAuthorBooks(user_id int, author_ids []int, limit int) ([]*models.FeedBook, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `
+		SELECT b.id, b.title, b.isbn, COALESCE(b.cover, ''), MIN(a.first_name || ' ' || a.last_name)
+		FROM book_authors AS ba
+		JOIN books AS b ON b.id = ba.book_id
+		JOIN authors AS a ON a.id = ba.author_id
+		WHERE ba.author_id = ANY($2) AND b.is_active
+			AND NOT EXISTS (SELECT 1 FROM read_lists AS rl WHERE rl.user_id = $1 AND rl.book_id = b.id)
+		GROUP BY b.id
+		ORDER BY b.added_at DESC NULLS LAST, b.id DESC
+		LIMIT $3
+	`
+	return m.feedBooks(ctx, query, user_id, pq.Array(author_ids), limit)
+}
+
+// FavouriteGenres returns the genres of the books the user reviewed positively or put in their read list, most frequent first.
+// Abandoned books do not count.
+func (m *postgresDBRepo) FavouriteGenres(user_id, limit int) ([]*models.Genre, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `
+		SELECT g.id, g.title
+		FROM (
+			SELECT book_id FROM reviews WHERE user_id = $1 AND is_active AND rating >= 3
+			UNION ALL
+			SELECT book_id FROM read_lists WHERE user_id = $1 AND status <> 'abandoned'
+		) AS liked
+		JOIN book_genres AS bg ON bg.book_id = liked.book_id
+		JOIN genres AS g ON g.id = bg.genre_id
+		GROUP BY g.id
+		ORDER BY COUNT(*) DESC, g.title ASC
+		LIMIT $2
+	`
+	rows, err := m.DB.QueryContext(ctx, query, user_id, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	genres := []*models.Genre{}
+	for rows.Next() {
+		genre := &models.Genre{}
+		if err := rows.Scan(&genre.ID, &genre.Title); err != nil {
+			return nil, err
+		}
+		genres = append(genres, genre)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return genres, nil
+}
+
+// FeedGenreBooks returns the best rated active books of the genres the user has not reviewed or put in their read list.
+// The reason of every book is the title of its genre.
+func (m *postgresDBRepo) FeedGenreBooks(user_id int, genre_ids []int, limit int) ([]*models.FeedBook, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `
+		SELECT b.id, b.title, b.isbn, COALESCE(b.cover, ''), MIN(g.title)
+		FROM book_genres AS bg
+		JOIN books AS b ON b.id = bg.book_id
+		JOIN genres AS g ON g.id = bg.genre_id
+		WHERE bg.genre_id = ANY($2) AND b.is_active
+			AND NOT EXISTS (SELECT 1 FROM read_lists AS rl WHERE rl.user_id = $1 AND rl.book_id = b.id)
+			AND NOT EXISTS (SELECT 1 FROM reviews AS r WHERE r.user_id = $1 AND r.book_id = b.id)
+		GROUP BY b.id
+		ORDER BY
+			(SELECT COALESCE(AVG(r.rating), 0) FROM reviews AS r WHERE r.book_id = b.id AND r.is_active) DESC,
+			b.added_at DESC NULLS LAST,
+			b.id DESC
+		LIMIT $3
+	`
+	return m.feedBooks(ctx, query, user_id, pq.Array(genre_ids), limit)
+}
+
+// feedBooks runs the feed query and scans the books with their reason
+func (m *postgresDBRepo) feedBooks(ctx context.Context, query string, args ...any) ([]*models.FeedBook, error) {
+	rows, err := m.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	books := []*models.FeedBook{}
+	for rows.Next() {
+		book := &models.FeedBook{}
+		if err := rows.Scan(&book.ID, &book.Title, &book.Isbn, &book.Cover, &book.Reason); err != nil {
+			return nil, err
+		}
+		books = append(books, book)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return books, nil
+}
+
+// FeedShelfReviews returns the newest active reviews other users wrote on the books of the shelves of the user
+func (m *postgresDBRepo) FeedShelfReviews(user_id, limit int) ([]*models.FeedReview, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `
+		SELECT r.id, r.rating, r.body, u.username, b.title, b.isbn, r.created_at
+		FROM reviews AS r
+		JOIN books AS b ON b.id = r.book_id
+		JOIN users AS u ON u.id = r.user_id
+		WHERE r.is_active AND r.user_id <> $1 AND b.is_active
+			AND r.book_id IN (
+				SELECT sb.book_id FROM shelf_books AS sb
+				JOIN shelves AS s ON s.id = sb.shelf_id
+				WHERE s.user_id = $1
+			)
+		ORDER BY r.created_at DESC, r.id DESC
+		LIMIT $2
+	`
+	rows, err := m.DB.QueryContext(ctx, query, user_id, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	reviews := []*models.FeedReview{}
+	for rows.Next() {
+		review := &models.FeedReview{}
+		if err := rows.Scan(
+			&review.ID,
+			&review.Rating,
+			&review.Body,
+			&review.Username,
+			&review.BookTitle,
+			&review.BookIsbn,
+			&review.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, review)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return reviews, nil
+}
+
+// ShelfOwnerIDs returns the users having the book on one of their shelves
+func (m *postgresDBRepo) ShelfOwnerIDs(book_id int) ([]int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `
+		SELECT DISTINCT s.user_id
+		FROM shelf_books AS sb
+		JOIN shelves AS s ON s.id = sb.shelf_id
+		WHERE sb.book_id = $1
+	`
+	rows, err := m.DB.QueryContext(ctx, query, book_id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	user_ids := []int{}
+	for rows.Next() {
+		var user_id int
+		if err := rows.Scan(&user_id); err != nil {
+			return nil, err
+		}
+		user_ids = append(user_ids, user_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return user_ids, nil
+}
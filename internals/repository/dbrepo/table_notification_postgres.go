@@ -0,0 +1,115 @@
+package dbrepo
+
+import (
+	"context"
+	"time"
+
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+)
+
+// InsertNotification stores a notification for a single user
+func (m *postgresDBRepo) InsertNotification(n *models.Notification) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	stmt := `
+		INSERT INTO notifications (user_id, type, message, link, is_read, created_at)
+		VALUES ($1, $2, $3, $4, false, $5)
+	`
+	_, err := m.DB.ExecContext(ctx, stmt, n.UserID, n.Type, n.Message, n.Link, time.Now())
+	return err
+}
+
+// InsertNotificationForAuthorFollowers stores the notification for every follower of the author
+func (m *postgresDBRepo) InsertNotificationForAuthorFollowers(author_id int, n *models.Notification) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	stmt := `
+		INSERT INTO notifications (user_id, type, message, link, is_read, created_at)
+		SELECT user_id, $2, $3, $4, false, $5
+		FROM followers
+		WHERE author_id = $1
+	`
+	_, err := m.DB.ExecContext(ctx, stmt, author_id, n.Type, n.Message, n.Link, time.Now())
+	return err
+}
+
+// GetNotificationsByUserID returns the latest notifications of the user with pagination.
+// When unreadOnly is true, only the unread notifications are returned.
+func (m *postgresDBRepo) GetNotificationsByUserID(user_id, limit, page int, unreadOnly bool) (*models.NotificationApi, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	if limit <= 0 {
+		limit = 10
+	}
+	if page <= 0 {
+		page = 1
+	}
+	offset := (page - 1) * limit
+
+	var total, unread int
+	countQuery := `
+		SELECT COUNT(*), COUNT(*) FILTER (WHERE NOT is_read)
+		FROM notifications
+		WHERE user_id = $1
+	`
+	if err := m.DB.QueryRowContext(ctx, countQuery, user_id).Scan(&total, &unread); err != nil {
+		return nil, err
+	}
+	query := `
+		SELECT id, user_id, type, message, link, is_read, created_at
+		FROM notifications
+		WHERE user_id = $1 AND (NOT $2 OR NOT is_read)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3 OFFSET $4
+	`
+	rows, err := m.DB.QueryContext(ctx, query, user_id, unreadOnly, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	notifications := []*models.Notification{}
+	for rows.Next() {
+		n := &models.Notification{}
+		if err := rows.Scan(
+			&n.ID,
+			&n.UserID,
+			&n.Type,
+			&n.Message,
+			&n.Link,
+			&n.IsRead,
+			&n.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, n)
+	}
+	if unreadOnly {
+		total = unread
+	}
+	return &models.NotificationApi{
+		Total:         total,
+		Unread:        unread,
+		Page:          page,
+		LastPage:      m.CalculateLastPage(limit, total),
+		Notifications: notifications,
+	}, nil
+}
+
+// MarkNotificationRead marks the notification of the user as read
+func (m *postgresDBRepo) MarkNotificationRead(id, user_id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	stmt := `UPDATE notifications SET is_read = true WHERE id = $1 AND user_id = $2`
+	_, err := m.DB.ExecContext(ctx, stmt, id, user_id)
+	return err
+}
+
+// MarkAllNotificationsRead marks every notification of the user as read
+func (m *postgresDBRepo) MarkAllNotificationsRead(user_id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	stmt := `UPDATE notifications SET is_read = true WHERE user_id = $1 AND NOT is_read`
+	_, err := m.DB.ExecContext(ctx, stmt, user_id)
+	return err
+}
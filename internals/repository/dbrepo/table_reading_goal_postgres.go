@@ -0,0 +1,218 @@
+package dbrepo
+
+import (
+	"context"
+	"time"
+
+	"github.com/ishanshre/Book-Review-Platform/internals/helpers"
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+)
+
+// readingGoalFinished counts the books finished by the user of the goal in the year of the goal.
+// A book read again or marked as read again in the same year is counted once.
+const readingGoalFinished = `(
+	SELECT COUNT(DISTINCT rr.book_id) FROM read_list_reads AS rr
+	WHERE rr.user_id = g.user_id AND EXTRACT(YEAR FROM rr.finished_at) = g.year
+)`
+
+// GetReadingGoal returns the reading goal of the user for the year with the number of finished reads.
+// It returns sql.ErrNoRows when the user has no goal for the year.
+func (m *postgresDBRepo) GetReadingGoal(user_id, year int) (*models.ReadingGoal, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `
+		SELECT g.id, g.user_id, g.year, g.target, g.remind, g.unsubscribe_token, g.last_reminded_at, g.created_at, g.updated_at,
+			` + readingGoalFinished + `
+		FROM reading_goals AS g
+		WHERE g.user_id = $1 AND g.year = $2
+	`
+	goal := &models.ReadingGoal{}
+	if err := m.DB.QueryRowContext(ctx, query, user_id, year).Scan(
+		&goal.ID,
+		&goal.UserID,
+		&goal.Year,
+		&goal.Target,
+		&goal.Remind,
+		&goal.UnsubscribeToken,
+		&goal.LastRemindedAt,
+		&goal.CreatedAt,
+		&goal.UpdatedAt,
+		&goal.Finished,
+	); err != nil {
+		return nil, err
+	}
+	return goal, nil
+}
+
+// UpsertReadingGoal sets the target and reminders of the reading goal of the user for the year.
+// The goal is created with a new unsubscribe token when the user has none for the year.
+func (m *postgresDBRepo) UpsertReadingGoal(goal *models.ReadingGoal) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	token, err := helpers.GenerateRandomToken(24)
+	if err != nil {
+		return err
+	}
+	stmt := `
+		INSERT INTO reading_goals (user_id, year, target, remind, unsubscribe_token, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+		ON CONFLICT (user_id, year) DO UPDATE
+		SET target = EXCLUDED.target, remind = EXCLUDED.remind, updated_at = EXCLUDED.updated_at
+		RETURNING id
+	`
+	return m.DB.QueryRowContext(ctx, stmt, goal.UserID, goal.Year, goal.Target, goal.Remind, token, goal.UpdatedAt).Scan(&goal.ID)
+}
+
+// DeleteReadingGoal removes the reading goal of the user for the year
+func (m *postgresDBRepo) DeleteReadingGoal(user_id, year int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	_, err := m.DB.ExecContext(ctx, `DELETE FROM reading_goals WHERE user_id = $1 AND year = $2`, user_id, year)
+	return err
+}
+
+// ReadingChallengeLeaderboard returns the users with a reading goal for the year
+// ordered by the finished books and then by the share of their target.
+// The users who hid their reading stats from their public profile are left out.
+func (m *postgresDBRepo) ReadingChallengeLeaderboard(year, limit, page int) (*models.ReadingChallengeApi, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if limit <= 0 {
+		limit = 10
+	}
+	if page <= 0 {
+		page = 1
+	}
+	offset := (page - 1) * limit
+
+	// the goals of the users showing their reading stats
+	goals := `
+		FROM reading_goals AS g
+		JOIN users AS u ON u.id = g.user_id
+		LEFT JOIN privacy_settings AS ps ON ps.user_id = g.user_id
+		WHERE g.year = $1 AND COALESCE(ps.show_stats, TRUE)
+	`
+	var count int
+	if err := m.DB.QueryRowContext(ctx, `SELECT COUNT(*) `+goals, year).Scan(&count); err != nil {
+		return nil, err
+	}
+	query := `
+		SELECT user_id, username, profile_pic, target, finished
+		FROM (
+			SELECT g.user_id, u.username, COALESCE(u.profile_pic, '') AS profile_pic, g.target,
+				` + readingGoalFinished + ` AS finished
+			` + goals + `
+		) AS challenge
+		ORDER BY finished DESC, finished::numeric / target DESC, username ASC
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := m.DB.QueryContext(ctx, query, year, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	entries := []*models.ReadingChallengeEntry{}
+	for rows.Next() {
+		entry := &models.ReadingChallengeEntry{}
+		if err := rows.Scan(
+			&entry.UserID,
+			&entry.Username,
+			&entry.ProfilePic,
+			&entry.Target,
+			&entry.Finished,
+		); err != nil {
+			return nil, err
+		}
+		goal := &models.ReadingGoal{Target: entry.Target, Finished: entry.Finished}
+		entry.Rank = offset + len(entries) + 1
+		entry.Percent = goal.Percent()
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return &models.ReadingChallengeApi{
+		Year:     year,
+		Total:    count,
+		Page:     page,
+		LastPage: m.CalculateLastPage(limit, count),
+		Entries:  entries,
+	}, nil
+}
+
+// ReadingGoalReminderRecipients returns the users with reminders turned on for their goal of the year
+// who were not reminded since the given time
+func (m *postgresDBRepo) ReadingGoalReminderRecipients(year int, since time.Time) ([]*models.ReadingGoalRecipient, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `
+		SELECT u.username, u.email, g.id, g.user_id, g.year, g.target, g.remind, g.unsubscribe_token, g.last_reminded_at, g.created_at, g.updated_at,
+			` + readingGoalFinished + `
+		FROM reading_goals AS g
+		JOIN users AS u ON u.id = g.user_id
+		WHERE g.year = $1 AND g.remind AND (g.last_reminded_at IS NULL OR g.last_reminded_at < $2)
+	`
+	rows, err := m.DB.QueryContext(ctx, query, year, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	recipients := []*models.ReadingGoalRecipient{}
+	for rows.Next() {
+		recipient := &models.ReadingGoalRecipient{Goal: &models.ReadingGoal{}}
+		if err := rows.Scan(
+			&recipient.Username,
+			&recipient.Email,
+			&recipient.Goal.ID,
+			&recipient.Goal.UserID,
+			&recipient.Goal.Year,
+			&recipient.Goal.Target,
+			&recipient.Goal.Remind,
+			&recipient.Goal.UnsubscribeToken,
+			&recipient.Goal.LastRemindedAt,
+			&recipient.Goal.CreatedAt,
+			&recipient.Goal.UpdatedAt,
+			&recipient.Goal.Finished,
+		); err != nil {
+			return nil, err
+		}
+		recipients = append(recipients, recipient)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return recipients, nil
+}
+
+// MarkReadingGoalReminded stores the time the reminder of the goal was sent
+func (m *postgresDBRepo) MarkReadingGoalReminded(id int, reminded_at time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	_, err := m.DB.ExecContext(ctx, `UPDATE reading_goals SET last_reminded_at = $2 WHERE id = $1`, id, reminded_at)
+	return err
+}
+
+// UnsubscribeReadingGoalReminders turns off the reminders of the goal with the token.
+// It returns false when no goal has the token.
+func (m *postgresDBRepo) UnsubscribeReadingGoalReminders(token string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	res, err := m.DB.ExecContext(ctx, `UPDATE reading_goals SET remind = FALSE, updated_at = $2 WHERE unsubscribe_token = $1`, token, time.Now())
+	if err != nil {
+		return false, err
+	}
+	rows_affected, _ := res.RowsAffected()
+	return rows_affected > 0, nil
+}
+
+// ReadingGoalUnsubscribeTokenExists returns true if the token is the unsubscribe token of a reading goal
+func (m *postgresDBRepo) ReadingGoalUnsubscribeTokenExists(token string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM reading_goals WHERE unsubscribe_token = $1)`
+	if err := m.DB.QueryRowContext(ctx, query, token).Scan(&exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
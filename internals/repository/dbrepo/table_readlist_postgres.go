@@ -2,12 +2,16 @@ package dbrepo
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"time"
 
 	"github.com/ishanshre/Book-Review-Platform/internals/models"
 )
 
+// readListColumns are the columns of read_lists in the order they are scanned
+const readListColumns = `user_id, book_id, created_at, status, current_page, started_at, finished_at, updated_at`
+
 // AllReadList fetches all the records from readLists db table.
 func (m *postgresDBRepo) AllReadList() ([]*models.ReadList, error) {
 	// Create a timeout of 3 second with context
@@ -15,7 +19,7 @@ func (m *postgresDBRepo) AllReadList() ([]*models.ReadList, error) {
 	defer cancel()
 
 	// prepare the sql statement
-	query := `SELECT * FROM read_lists`
+	query := `SELECT ` + readListColumns + ` FROM read_lists`
 
 	// Execute the query using Query Context.
 	// If any error occurs, nil and error is returned
@@ -32,7 +36,16 @@ func (m *postgresDBRepo) AllReadList() ([]*models.ReadList, error) {
 
 		// Store the record in ReadList instance
 		readList := new(models.ReadList)
-		if err := rows.Scan(&readList.UserID, &readList.BookID, &readList.CreatedAt); err != nil {
+		if err := rows.Scan(
+			&readList.UserID,
+			&readList.BookID,
+			&readList.CreatedAt,
+			&readList.Status,
+			&readList.CurrentPage,
+			&readList.StartedAt,
+			&readList.FinishedAt,
+			&readList.UpdatedAt,
+		); err != nil {
 			return nil, err
 		}
 
@@ -82,10 +95,15 @@ func (m *postgresDBRepo) InsertReadList(u *models.ReadList) error {
 
 	// Prepare a insert query statement
 	stmt := `
-		INSERT INTO read_lists (user_id, book_id, created_at)
-		VALUES ($1, $2, $3);
+		INSERT INTO read_lists (user_id, book_id, created_at, status, current_page, started_at, finished_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
 	`
 
+	// New books are want to read unless a status is given
+	if u.Status == "" {
+		u.Status = models.ReadingWantToRead
+	}
+
 	// Executing the query
 	_, err := m.DB.ExecContext(
 		ctx,
@@ -93,6 +111,11 @@ func (m *postgresDBRepo) InsertReadList(u *models.ReadList) error {
 		u.UserID,
 		u.BookID,
 		u.CreatedAt,
+		u.Status,
+		u.CurrentPage,
+		u.StartedAt,
+		u.FinishedAt,
+		u.UpdatedAt,
 	)
 	if err != nil {
 		return err
@@ -111,7 +134,7 @@ func (m *postgresDBRepo) GetReadListByID(user_id, book_id int) (*models.ReadList
 
 	// Preparing the query statement
 	query := `
-		SELECT * FROM read_lists
+		SELECT ` + readListColumns + ` FROM read_lists
 		WHERE (user_id=$1 AND book_id=$2)
 	`
 
@@ -126,6 +149,11 @@ func (m *postgresDBRepo) GetReadListByID(user_id, book_id int) (*models.ReadList
 		&readList.UserID,
 		&readList.BookID,
 		&readList.CreatedAt,
+		&readList.Status,
+		&readList.CurrentPage,
+		&readList.StartedAt,
+		&readList.FinishedAt,
+		&readList.UpdatedAt,
 	); err != nil {
 		return nil, err
 	}
@@ -175,6 +203,70 @@ func (m *postgresDBRepo) UpdateReadList(u *models.ReadList, book_id, user_id int
 	return nil
 }
 
+// UpdateReadingProgress updates the reading status, current page and dates of the book in the read list of the user.
+// The finished reads are stored within the same transaction.
+func (m *postgresDBRepo) UpdateReadingProgress(u *models.ReadList, reads ...*models.ReadListRead) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	if len(reads) == 0 {
+		return updateReadingProgress(ctx, m.DB, u)
+	}
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := updateReadingProgress(ctx, tx, u); err != nil {
+		return err
+	}
+	stmt := `
+		INSERT INTO read_list_reads (user_id, book_id, started_at, finished_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+	for _, read := range reads {
+		if err := tx.QueryRowContext(ctx, stmt, read.UserID, read.BookID, read.StartedAt, read.FinishedAt).Scan(&read.ID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// updateReadingProgress updates the reading columns of the read list using the given connection or transaction
+func updateReadingProgress(ctx context.Context, db execer, u *models.ReadList) error {
+	stmt := `
+		UPDATE read_lists
+		SET status = $3, current_page = $4, started_at = $5, finished_at = $6, updated_at = $7
+		WHERE (user_id = $1 AND book_id = $2)
+	`
+	result, err := db.ExecContext(ctx, stmt, u.UserID, u.BookID, u.Status, u.CurrentPage, u.StartedAt, u.FinishedAt, u.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// ReadListReadCount returns the number of times the user finished reading the book
+func (m *postgresDBRepo) ReadListReadCount(user_id, book_id int) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var count int
+	query := `SELECT COUNT(*) FROM read_list_reads WHERE user_id = $1 AND book_id = $2`
+	if err := m.DB.QueryRowContext(ctx, query, user_id, book_id).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
 func (m *postgresDBRepo) ReadListCount(user_id int) (int, error) {
 	// create a timeout of 3 second with context
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
@@ -188,7 +280,9 @@ func (m *postgresDBRepo) ReadListCount(user_id int) (int, error) {
 	return count, nil
 }
 
-func (m *postgresDBRepo) GetAllBooksFromReadListByUserId(limit, page, user_id int, searchKey, sort string) (*models.BookApiFilter, error) {
+// GetAllBooksFromReadListByUserId returns the books in the read list of the user.
+// The books are filtered by the reading status when it is not empty.
+func (m *postgresDBRepo) GetAllBooksFromReadListByUserId(limit, page, user_id int, searchKey, sort, status string) (*models.BookApiFilter, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 	if limit <= 0 {
@@ -209,7 +303,7 @@ func (m *postgresDBRepo) GetAllBooksFromReadListByUserId(limit, page, user_id in
 			read_lists AS rl
 		LEFT JOIN
 			books AS b ON b.id = rl.book_id
-		where rl.user_id = $1
+		where rl.user_id = $1 AND ($2::text = '' OR rl.status = $2)
 	`
 	countQuery := `
 			SELECT 
@@ -218,7 +312,7 @@ func (m *postgresDBRepo) GetAllBooksFromReadListByUserId(limit, page, user_id in
 				read_lists AS rl
 			LEFT JOIN
 				books AS b ON b.id = rl.book_id
-			where rl.user_id = $1
+			where rl.user_id = $1 AND ($2::text = '' OR rl.status = $2)
 	`
 	if searchKey != "" {
 		query = fmt.Sprintf("%s AND (b.title LIKE '%%%s%%' OR CAST(b.isbn AS TEXT) LIKE '%%%s%%')", query, searchKey, searchKey)
@@ -229,12 +323,12 @@ func (m *postgresDBRepo) GetAllBooksFromReadListByUserId(limit, page, user_id in
 	}
 
 	var count int
-	if err := m.DB.QueryRowContext(ctx, countQuery, user_id).Scan(&count); err != nil {
+	if err := m.DB.QueryRowContext(ctx, countQuery, user_id, status).Scan(&count); err != nil {
 		return nil, err
 	}
 
 	query = fmt.Sprintf("%s LIMIT %d OFFSET %d", query, limit, offset)
-	rows, err := m.DB.QueryContext(ctx, query, user_id)
+	rows, err := m.DB.QueryContext(ctx, query, user_id, status)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,204 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+)
+
+// reportColumns selects the report joined as rp with the usernames of the author as tu, the reporter as ru and the moderator as mu
+const reportColumns = `
+	rp.id, rp.target_type, rp.target_id, COALESCE(rp.target_user_id, 0), COALESCE(tu.username, ''), rp.target_body,
+	rp.reporter_id, ru.username, rp.reason, rp.details, rp.status, rp.resolution, rp.resolution_note,
+	COALESCE(rp.resolved_by, 0), COALESCE(mu.username, ''), rp.created_at, rp.resolved_at
+`
+
+// reportJoins joins the users of the report used by reportColumns
+const reportJoins = `
+	FROM reports rp
+	JOIN users ru ON ru.id = rp.reporter_id
+	LEFT JOIN users tu ON tu.id = rp.target_user_id
+	LEFT JOIN users mu ON mu.id = rp.resolved_by
+`
+
+// ReportExists returns true if the user already reported the review or comment
+func (m *postgresDBRepo) ReportExists(target_type string, target_id, reporter_id int) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `SELECT EXISTS(SELECT 1 FROM reports WHERE target_type = $1 AND target_id = $2 AND reporter_id = $3)`
+	var exists bool
+	if err := m.DB.QueryRowContext(ctx, query, target_type, target_id, reporter_id).Scan(&exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// InsertReport adds new open report and sets its id
+func (m *postgresDBRepo) InsertReport(r *models.Report) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `
+		INSERT INTO reports (target_type, target_id, target_user_id, target_body, reporter_id, reason, details, status, created_at)
+		VALUES ($1, $2, NULLIF($3, 0), $4, $5, $6, $7, 'open', $8)
+		RETURNING id
+	`
+	r.Status = models.ReportOpen
+	return m.DB.QueryRowContext(ctx, query,
+		r.TargetType,
+		r.TargetID,
+		r.TargetUserID,
+		r.TargetBody,
+		r.ReporterID,
+		r.Reason,
+		r.Details,
+		r.CreatedAt,
+	).Scan(&r.ID)
+}
+
+// CountOpenReports returns the number of open reports of the review or comment
+func (m *postgresDBRepo) CountOpenReports(target_type string, target_id int) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `SELECT COUNT(*) FROM reports WHERE target_type = $1 AND target_id = $2 AND status = 'open'`
+	var count int
+	if err := m.DB.QueryRowContext(ctx, query, target_type, target_id).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// MarkReportsHidTarget records that the open reports of the review or comment hid it
+func (m *postgresDBRepo) MarkReportsHidTarget(target_type string, target_id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `UPDATE reports SET hid_target = true WHERE target_type = $1 AND target_id = $2 AND status = 'open'`
+	_, err := m.DB.ExecContext(ctx, query, target_type, target_id)
+	return err
+}
+
+// OpenReportsHidTarget reports whether the open reports of the review or comment hid it
+func (m *postgresDBRepo) OpenReportsHidTarget(target_type string, target_id int) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `SELECT EXISTS(SELECT 1 FROM reports WHERE target_type = $1 AND target_id = $2 AND status = 'open' AND hid_target)`
+	var exists bool
+	if err := m.DB.QueryRowContext(ctx, query, target_type, target_id).Scan(&exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// GetReportByID returns the report with the usernames of the author, reporter and moderator
+func (m *postgresDBRepo) GetReportByID(id int) (*models.Report, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `SELECT ` + reportColumns + reportJoins + ` WHERE rp.id = $1`
+	report := &models.Report{}
+	if err := scanReport(m.DB.QueryRowContext(ctx, query, id), report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// ResolveReports resolves every open report of the review or comment with the resolution of the moderator
+func (m *postgresDBRepo) ResolveReports(target_type string, target_id int, resolution, note string, resolved_by int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `
+		UPDATE reports
+		SET status = 'resolved', resolution = $1, resolution_note = $2, resolved_by = $3, resolved_at = $4
+		WHERE target_type = $5 AND target_id = $6 AND status = 'open'
+	`
+	_, err := m.DB.ExecContext(ctx, query, resolution, note, resolved_by, time.Now(), target_type, target_id)
+	return err
+}
+
+// ReportFilter returns the filtered reports with the number of open reports of their target and pagination.
+// Status filters the reports by open or resolved, empty status returns all.
+func (m *postgresDBRepo) ReportFilter(limit, page int, searchKey, status, sort string) (*models.ReportFilterApi, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	if limit <= 0 {
+		limit = 10
+	}
+	if page <= 0 {
+		page = 1
+	}
+	offset := (page - 1) * limit
+	where := `
+		WHERE (ru.username ILIKE $1 OR tu.username ILIKE $1 OR rp.target_body ILIKE $1 OR rp.reason ILIKE $1)
+		AND ($2::text = '' OR rp.status = $2::text)
+	`
+	if sort != "asc" {
+		sort = "desc"
+	}
+	query := fmt.Sprintf(`
+		SELECT %s,
+			(SELECT COUNT(*) FROM reports o WHERE o.target_type = rp.target_type AND o.target_id = rp.target_id AND o.status = 'open')
+		%s %s ORDER BY rp.created_at %s LIMIT %d OFFSET %d
+	`, reportColumns, reportJoins, where, sort, limit, offset)
+	countQuery := fmt.Sprintf("SELECT COUNT(*) %s %s", reportJoins, where)
+	search := fmt.Sprintf("%%%s%%", searchKey)
+	var count int
+	if err := m.DB.QueryRowContext(ctx, countQuery, search, status).Scan(&count); err != nil {
+		return nil, err
+	}
+	rows, err := m.DB.QueryContext(ctx, query, search, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	reports := []*models.Report{}
+	for rows.Next() {
+		report := &models.Report{}
+		if err := scanReport(rows, report, &report.OpenReports); err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	lastPage := m.CalculateLastPage(limit, count)
+	return &models.ReportFilterApi{
+		Total:    count,
+		Page:     page,
+		LastPage: lastPage,
+		Reports:  reports,
+	}, nil
+}
+
+// scanReport scans the reportColumns into the report followed by the extra destinations
+func scanReport(row scanner, report *models.Report, extra ...interface{}) error {
+	var resolvedAt sql.NullTime
+	dest := []interface{}{
+		&report.ID,
+		&report.TargetType,
+		&report.TargetID,
+		&report.TargetUserID,
+		&report.TargetUsername,
+		&report.TargetBody,
+		&report.ReporterID,
+		&report.ReporterUsername,
+		&report.Reason,
+		&report.Details,
+		&report.Status,
+		&report.Resolution,
+		&report.ResolutionNote,
+		&report.ResolvedBy,
+		&report.ResolvedByUsername,
+		&report.CreatedAt,
+		&resolvedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return err
+	}
+	if resolvedAt.Valid {
+		report.ResolvedAt = &resolvedAt.Time
+	}
+	return nil
+}
@@ -8,7 +8,7 @@ import (
 	"github.com/ishanshre/Book-Review-Platform/internals/models"
 )
 
-func (m *postgresDBRepo) InsertRequestedBook(i *models.RequestedBook) error {
+func (m *postgresDBRepo) InsertRequestedBook(i *models.RequestedBook, mails ...*models.MailData) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
@@ -16,18 +16,17 @@ func (m *postgresDBRepo) InsertRequestedBook(i *models.RequestedBook) error {
 		INSERT INTO request_books (book_title, author, requested_by, requested_date)
 		VALUES ($1, $2, $3, $4)
 	`
-	_, err := m.DB.ExecContext(
-		ctx,
-		query,
-		i.BookTitle,
-		i.Author,
-		i.RequestedBy,
-		i.RequestedDate,
-	)
-	if err != nil {
+	return m.execWithMails(ctx, mails, func(db execer) error {
+		_, err := db.ExecContext(
+			ctx,
+			query,
+			i.BookTitle,
+			i.Author,
+			i.RequestedBy,
+			i.RequestedDate,
+		)
 		return err
-	}
-	return nil
+	})
 }
 
 func (m *postgresDBRepo) AllRequestBooks() ([]*models.RequestedBook, error) {
@@ -158,7 +157,7 @@ func (m *postgresDBRepo) RequestedBooksListFilter(limit, page int, searchKey, so
 	}, nil
 }
 
-func (m *postgresDBRepo) UpdateBookRequestStatus(request_id int) error {
+func (m *postgresDBRepo) UpdateBookRequestStatus(request_id int, mails ...*models.MailData) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 	stmt := `
@@ -166,9 +165,8 @@ func (m *postgresDBRepo) UpdateBookRequestStatus(request_id int) error {
 		SET is_added = true
 		WHERE id = $1
 	`
-	_, err := m.DB.ExecContext(ctx, stmt, request_id)
-	if err != nil {
+	return m.execWithMails(ctx, mails, func(db execer) error {
+		_, err := db.ExecContext(ctx, stmt, request_id)
 		return err
-	}
-	return nil
+	})
 }
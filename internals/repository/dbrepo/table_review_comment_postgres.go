@@ -0,0 +1,232 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+)
+
+// reviewCommentColumns selects the comment joined as c with the username of its user joined as u
+const reviewCommentColumns = `
+	c.id, c.review_id, c.user_id, u.username, c.parent_id, c.body, c.is_active, c.is_flagged, c.is_deleted, c.created_at, c.updated_at
+`
+
+// InsertReviewComment adds new comment and sets its id
+func (m *postgresDBRepo) InsertReviewComment(c *models.ReviewComment) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `
+		INSERT INTO review_comments (review_id, user_id, parent_id, body, is_active, is_flagged, is_deleted, created_at, updated_at)
+		VALUES ($1, $2, NULLIF($3, 0), $4, $5, false, false, $6, $7)
+		RETURNING id
+	`
+	return m.DB.QueryRowContext(ctx, query,
+		c.ReviewID,
+		c.UserID,
+		c.ParentID,
+		c.Body,
+		c.IsActive,
+		c.CreatedAt,
+		c.UpdatedAt,
+	).Scan(&c.ID)
+}
+
+// GetReviewCommentByID returns the comment with the username of its user
+func (m *postgresDBRepo) GetReviewCommentByID(id int) (*models.ReviewComment, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `SELECT ` + reviewCommentColumns + `
+		FROM review_comments c
+		JOIN users u ON u.id = c.user_id
+		WHERE c.id = $1
+	`
+	comment := &models.ReviewComment{}
+	if err := scanReviewComment(m.DB.QueryRowContext(ctx, query, id), comment); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// GetReviewCommentsByReviewID returns every comment of the review in the order they were written.
+// Hidden and deleted comments are included so the thread can be built around them.
+func (m *postgresDBRepo) GetReviewCommentsByReviewID(review_id int) ([]*models.ReviewComment, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `SELECT ` + reviewCommentColumns + `
+		FROM review_comments c
+		JOIN users u ON u.id = c.user_id
+		WHERE c.review_id = $1
+		ORDER BY c.created_at, c.id
+	`
+	rows, err := m.DB.QueryContext(ctx, query, review_id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	comments := []*models.ReviewComment{}
+	for rows.Next() {
+		comment := &models.ReviewComment{}
+		if err := scanReviewComment(rows, comment); err != nil {
+			return nil, err
+		}
+		comments = append(comments, comment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// UpdateReviewCommentBody updates the body of the comment
+func (m *postgresDBRepo) UpdateReviewCommentBody(c *models.ReviewComment) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `UPDATE review_comments SET body = $1, updated_at = $2 WHERE id = $3 AND NOT is_deleted`
+	result, err := m.DB.ExecContext(ctx, query, c.Body, c.UpdatedAt, c.ID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("row not updated")
+	}
+	return nil
+}
+
+// MarkReviewCommentDeleted removes the body of the comment but keeps the row so its replies stay in the thread
+func (m *postgresDBRepo) MarkReviewCommentDeleted(id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `UPDATE review_comments SET body = '', is_deleted = true, is_flagged = false, updated_at = $1 WHERE id = $2`
+	_, err := m.DB.ExecContext(ctx, query, time.Now(), id)
+	return err
+}
+
+// FlagReviewComment flags the comment for the moderators
+func (m *postgresDBRepo) FlagReviewComment(id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `UPDATE review_comments SET is_flagged = true WHERE id = $1 AND NOT is_deleted`
+	_, err := m.DB.ExecContext(ctx, query, id)
+	return err
+}
+
+// ModerateReviewComment shows or hides the comment and clears its flag
+func (m *postgresDBRepo) ModerateReviewComment(id int, is_active bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `UPDATE review_comments SET is_active = $1, is_flagged = false WHERE id = $2`
+	_, err := m.DB.ExecContext(ctx, query, is_active, id)
+	return err
+}
+
+// DeleteReviewComment deletes the comment along with its replies
+func (m *postgresDBRepo) DeleteReviewComment(id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `DELETE FROM review_comments WHERE id = $1`
+	_, err := m.DB.ExecContext(ctx, query, id)
+	return err
+}
+
+// ReviewCommentFilter returns the filtered comments with the book title and pagination.
+// Status filters the comments by flagged, hidden or deleted, empty status returns all.
+func (m *postgresDBRepo) ReviewCommentFilter(limit, page int, searchKey, status, sort string) (*models.ReviewCommentFilterApi, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	if limit <= 0 {
+		limit = 10
+	}
+	if page <= 0 {
+		page = 1
+	}
+	offset := (page - 1) * limit
+	where := `
+		WHERE (u.username ILIKE $1 OR c.body ILIKE $1 OR b.title ILIKE $1)
+		AND (
+			$2::text = ''
+			OR ($2::text = 'flagged' AND c.is_flagged)
+			OR ($2::text = 'hidden' AND NOT c.is_active)
+			OR ($2::text = 'deleted' AND c.is_deleted)
+		)
+	`
+	from := `
+		FROM review_comments c
+		JOIN users u ON u.id = c.user_id
+		JOIN reviews r ON r.id = c.review_id
+		JOIN books b ON b.id = r.book_id
+	`
+	if sort != "asc" {
+		sort = "desc"
+	}
+	query := fmt.Sprintf("SELECT %s, b.title %s %s ORDER BY c.created_at %s LIMIT %d OFFSET %d", reviewCommentColumns, from, where, sort, limit, offset)
+	countQuery := fmt.Sprintf("SELECT COUNT(*) %s %s", from, where)
+	search := fmt.Sprintf("%%%s%%", searchKey)
+	var count int
+	if err := m.DB.QueryRowContext(ctx, countQuery, search, status).Scan(&count); err != nil {
+		return nil, err
+	}
+	rows, err := m.DB.QueryContext(ctx, query, search, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	comments := []*models.ReviewComment{}
+	for rows.Next() {
+		comment := &models.ReviewComment{}
+		if err := scanReviewComment(rows, comment, &comment.BookTitle); err != nil {
+			return nil, err
+		}
+		comments = append(comments, comment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	lastPage := m.CalculateLastPage(limit, count)
+	return &models.ReviewCommentFilterApi{
+		Total:    count,
+		Page:     page,
+		LastPage: lastPage,
+		Comments: comments,
+	}, nil
+}
+
+// scanReviewComment scans the reviewCommentColumns into the comment followed by the extra destinations
+func scanReviewComment(row scanner, comment *models.ReviewComment, extra ...interface{}) error {
+	var parentID sql.NullInt64
+	dest := []interface{}{
+		&comment.ID,
+		&comment.ReviewID,
+		&comment.UserID,
+		&comment.Username,
+		&parentID,
+		&comment.Body,
+		&comment.IsActive,
+		&comment.IsFlagged,
+		&comment.IsDeleted,
+		&comment.CreatedAt,
+		&comment.UpdatedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return err
+	}
+	comment.ParentID = int(parentID.Int64)
+	return nil
+}
+
+// SetReviewCommentActive shows or hides the comment keeping its flag
+func (m *postgresDBRepo) SetReviewCommentActive(id int, is_active bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `UPDATE review_comments SET is_active = $1 WHERE id = $2`
+	_, err := m.DB.ExecContext(ctx, query, is_active, id)
+	return err
+}
@@ -16,7 +16,7 @@ func (m *postgresDBRepo) AllReviews() ([]*models.Review, error) {
 	defer cancel()
 
 	// prepare the sql statement
-	query := `SELECT * FROM reviews`
+	query := `SELECT id, rating, body, book_id, user_id, is_active, created_at, updated_at FROM reviews`
 
 	// Execute the query using Query Context.
 	// If any error occurs, nil and error is returned
@@ -83,6 +83,7 @@ func (m *postgresDBRepo) ReviewExists(u *models.Review) (bool, error) {
 
 // InsertReview add new book user review relation table to db
 // Takes Review model as a parameter
+// An inactive review is stored as not published yet
 // Returns an error if something goes wrong
 func (m *postgresDBRepo) InsertReview(u *models.Review) error {
 
@@ -92,12 +93,13 @@ func (m *postgresDBRepo) InsertReview(u *models.Review) error {
 
 	// Prepare a insert query statement
 	stmt := `
-		INSERT INTO reviews (rating, body, book_id, user_id, is_active, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7);
+		INSERT INTO reviews (rating, body, book_id, user_id, is_active, published, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $5, $6, $7)
+		RETURNING id;
 	`
 
-	// Executing the query
-	_, err := m.DB.ExecContext(
+	// Executing the query and store the id of the new review
+	err := m.DB.QueryRowContext(
 		ctx,
 		stmt,
 		u.Rating,
@@ -107,7 +109,7 @@ func (m *postgresDBRepo) InsertReview(u *models.Review) error {
 		u.IsActive,
 		u.CreatedAt,
 		u.UpdatedAt,
-	)
+	).Scan(&u.ID)
 	if err != nil {
 		return err
 	}
@@ -125,7 +127,7 @@ func (m *postgresDBRepo) GetReviewByID(id int) (*models.Review, error) {
 
 	// Preparing the query statement
 	query := `
-		SELECT * FROM reviews
+		SELECT id, rating, body, book_id, user_id, is_active, created_at, updated_at FROM reviews
 		WHERE id=$1
 	`
 
@@ -164,7 +166,7 @@ func (m *postgresDBRepo) GetReviewByUserID(id int) (*models.Review, error) {
 
 	// Preparing the query statement
 	query := `
-		SELECT * FROM reviews
+		SELECT id, rating, body, book_id, user_id, is_active, created_at, updated_at FROM reviews
 		WHERE user_id=$1
 	`
 
@@ -212,7 +214,8 @@ func (m *postgresDBRepo) DeleteReview(id int) error {
 
 // UpdateReview updates the Review
 // Takes update value Review model and id of review to be updated as paramaters
-func (m *postgresDBRepo) UpdateReview(u *models.Review) error {
+// The revisions of the edit are stored within the same transaction.
+func (m *postgresDBRepo) UpdateReview(u *models.Review, revisions ...*models.ReviewRevision) error {
 
 	// create a timeout of 3 second with context
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
@@ -226,28 +229,27 @@ func (m *postgresDBRepo) UpdateReview(u *models.Review) error {
 	`
 
 	// Executing the sql query
-	_, err := m.DB.ExecContext(
-		ctx,
-		stmt,
-		u.ID,
-		u.Rating,
-		u.Body,
-		u.BookID,
-		u.UserID,
-		u.IsActive,
-		u.UpdatedAt,
-	)
-	if err != nil {
+	return m.execWithRevisions(ctx, revisions, func(db execer) error {
+		_, err := db.ExecContext(
+			ctx,
+			stmt,
+			u.ID,
+			u.Rating,
+			u.Body,
+			u.BookID,
+			u.UserID,
+			u.IsActive,
+			u.UpdatedAt,
+		)
 		return err
-	}
-	return nil
+	})
 }
 
 func (m *postgresDBRepo) GetReviewsByBookID(bookID int) ([]*models.Review, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
-	query := "SELECT * FROM reviews WHERE book_id=$1 ORDER BY id"
+	query := "SELECT id, rating, body, book_id, user_id, is_active, created_at, updated_at FROM reviews WHERE book_id=$1 ORDER BY id"
 	rows, err := m.DB.QueryContext(ctx, query, bookID)
 	if err != nil {
 		return nil, err
@@ -272,33 +274,36 @@ func (m *postgresDBRepo) GetReviewsByBookID(bookID int) ([]*models.Review, error
 	return reviews, nil
 }
 
-func (m *postgresDBRepo) UpdateReviewBook(update *models.Review) error {
+func (m *postgresDBRepo) UpdateReviewBook(update *models.Review, revisions ...*models.ReviewRevision) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
 	query := `
 		UPDATE reviews
-		SET rating = $4, body = $5, updated_at = $6
+		SET rating = $4, body = $5, is_active = $6, updated_at = $7
 		WHERE id = $1 AND book_id = $2 AND user_id = $3
 	`
-	res, err := m.DB.ExecContext(
-		ctx,
-		query,
-		update.ID,
-		update.BookID,
-		update.UserID,
-		update.Rating,
-		update.Body,
-		update.UpdatedAt,
-	)
-	if err != nil {
-		return err
-	}
-	affected, _ := res.RowsAffected()
-	if affected == 0 {
-		return errors.New("row not updated")
-	}
-	return nil
+	return m.execWithRevisions(ctx, revisions, func(db execer) error {
+		res, err := db.ExecContext(
+			ctx,
+			query,
+			update.ID,
+			update.BookID,
+			update.UserID,
+			update.Rating,
+			update.Body,
+			update.IsActive,
+			update.UpdatedAt,
+		)
+		if err != nil {
+			return err
+		}
+		affected, _ := res.RowsAffected()
+		if affected == 0 {
+			return errors.New("row not updated")
+		}
+		return nil
+	})
 }
 
 func (m *postgresDBRepo) ReviewFilter(limit, page int, searchKey, sort string) (*models.ReviewFilterApi, error) {
@@ -374,6 +379,32 @@ func (m *postgresDBRepo) ReviewFilter(limit, page int, searchKey, sort string) (
 	}, nil
 }
 
+// SetReviewActive activates or deactivates the review
+func (m *postgresDBRepo) SetReviewActive(id int, is_active bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `UPDATE reviews SET is_active = $1, updated_at = $2 WHERE id = $3`
+	_, err := m.DB.ExecContext(ctx, query, is_active, time.Now(), id)
+	return err
+}
+
+// PublishReview marks the review as published.
+// It returns true only the first time, when the review was held since it was added.
+func (m *postgresDBRepo) PublishReview(id int) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `UPDATE reviews SET published = true WHERE id = $1 AND NOT published`
+	res, err := m.DB.ExecContext(ctx, query, id)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
 // Get total count of reviews from the database
 func (m *postgresDBRepo) TotalReviewsCount() (int, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
@@ -385,3 +416,19 @@ func (m *postgresDBRepo) TotalReviewsCount() (int, error) {
 	}
 	return count, nil
 }
+
+// GetBookRating returns the average rating and number of active reviews of the book
+func (m *postgresDBRepo) GetBookRating(book_id int) (*models.BookRating, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `
+		SELECT COALESCE(AVG(rating), 0), COUNT(*)
+		FROM reviews
+		WHERE book_id = $1 AND is_active
+	`
+	rating := &models.BookRating{BookID: book_id}
+	if err := m.DB.QueryRowContext(ctx, query, book_id).Scan(&rating.Average, &rating.Count); err != nil {
+		return nil, err
+	}
+	return rating, nil
+}
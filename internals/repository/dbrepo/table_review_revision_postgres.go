@@ -0,0 +1,134 @@
+package dbrepo
+
+import (
+	"context"
+	"time"
+
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+)
+
+// execWithRevisions runs the review update and stores its revisions within a transaction.
+// The review as it was written is stored as revision 0 before its first edit.
+func (m *postgresDBRepo) execWithRevisions(ctx context.Context, revisions []*models.ReviewRevision, fn func(db execer) error) error {
+	if len(revisions) == 0 {
+		return fn(m.DB)
+	}
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// the reviews are locked until the commit so that concurrent edits number their revisions one after the other
+	lock := `SELECT id FROM reviews WHERE id = $1 FOR UPDATE`
+	for _, revision := range revisions {
+		if _, err := tx.ExecContext(ctx, lock, revision.ReviewID); err != nil {
+			return err
+		}
+	}
+	original := `
+		INSERT INTO review_revisions (review_id, revision, rating, body, editor_id, editor_role, created_at)
+		SELECT id, 0, rating, COALESCE(body, ''), user_id, 'author', COALESCE(created_at, NOW())
+		FROM reviews
+		WHERE id = $1 AND NOT EXISTS (SELECT 1 FROM review_revisions WHERE review_id = $1)
+	`
+	for _, revision := range revisions {
+		if _, err := tx.ExecContext(ctx, original, revision.ReviewID); err != nil {
+			return err
+		}
+	}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	query := `
+		INSERT INTO review_revisions (review_id, revision, rating, body, editor_id, editor_role, created_at)
+		SELECT $1, COALESCE(MAX(revision), 0) + 1, $2::numeric, $3::text, NULLIF($4::int, 0), $5::text, $6::timestamptz
+		FROM review_revisions
+		WHERE review_id = $1
+		RETURNING id, revision
+	`
+	for _, revision := range revisions {
+		if err := tx.QueryRowContext(ctx, query,
+			revision.ReviewID,
+			revision.Rating,
+			revision.Body,
+			revision.EditorID,
+			revision.EditorRole,
+			revision.CreatedAt,
+		).Scan(&revision.ID, &revision.Revision); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetReviewRevisions returns the revisions of the review from the latest to the original
+func (m *postgresDBRepo) GetReviewRevisions(review_id int) ([]*models.ReviewRevision, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `
+		SELECT rr.id, rr.review_id, rr.revision, rr.rating, rr.body, COALESCE(rr.editor_id, 0), COALESCE(u.username, ''), rr.editor_role, rr.created_at
+		FROM review_revisions rr
+		LEFT JOIN users u ON u.id = rr.editor_id
+		WHERE rr.review_id = $1
+		ORDER BY rr.revision DESC
+	`
+	rows, err := m.DB.QueryContext(ctx, query, review_id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	revisions := []*models.ReviewRevision{}
+	for rows.Next() {
+		revision := &models.ReviewRevision{}
+		if err := rows.Scan(
+			&revision.ID,
+			&revision.ReviewID,
+			&revision.Revision,
+			&revision.Rating,
+			&revision.Body,
+			&revision.EditorID,
+			&revision.EditorUsername,
+			&revision.EditorRole,
+			&revision.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+// GetReviewEditTimesByBookID returns the time of the latest edit of the edited reviews of the book by review id
+func (m *postgresDBRepo) GetReviewEditTimesByBookID(book_id int) (map[int]time.Time, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `
+		SELECT rr.review_id, MAX(rr.created_at)
+		FROM review_revisions rr
+		JOIN reviews r ON r.id = rr.review_id
+		WHERE r.book_id = $1 AND rr.revision > 0
+		GROUP BY rr.review_id
+	`
+	rows, err := m.DB.QueryContext(ctx, query, book_id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	editTimes := make(map[int]time.Time)
+	for rows.Next() {
+		var review_id int
+		var editedAt time.Time
+		if err := rows.Scan(&review_id, &editedAt); err != nil {
+			return nil, err
+		}
+		editTimes[review_id] = editedAt
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return editTimes, nil
+}
@@ -0,0 +1,97 @@
+package dbrepo
+
+import (
+	"context"
+	"time"
+
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+)
+
+// reviewVoteCountColumns selects the vote counters of the review joined as r with its votes joined as v.
+// The vote of the user passed as $2 is returned as helpful, unhelpful or empty.
+const reviewVoteCountColumns = `
+	r.id,
+	COUNT(v.id) FILTER (WHERE v.is_helpful),
+	COUNT(v.id) FILTER (WHERE NOT v.is_helpful),
+	COALESCE(MAX(CASE WHEN v.user_id = $2 THEN CASE WHEN v.is_helpful THEN 'helpful' ELSE 'unhelpful' END END), '')
+`
+
+// UpsertReviewVote stores the vote of the user on the review, replacing the previous vote of the user
+func (m *postgresDBRepo) UpsertReviewVote(v *models.ReviewVote) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `
+		INSERT INTO review_votes (review_id, user_id, is_helpful, voted_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (review_id, user_id)
+		DO UPDATE SET is_helpful = EXCLUDED.is_helpful, voted_at = EXCLUDED.voted_at
+		RETURNING id
+	`
+	return m.DB.QueryRowContext(ctx, query, v.ReviewID, v.UserID, v.IsHelpful, v.VotedAt).Scan(&v.ID)
+}
+
+// DeleteReviewVote removes the vote of the user on the review
+func (m *postgresDBRepo) DeleteReviewVote(review_id, user_id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `DELETE FROM review_votes WHERE review_id = $1 AND user_id = $2`
+	_, err := m.DB.ExecContext(ctx, query, review_id, user_id)
+	return err
+}
+
+// GetReviewVoteCount returns the vote counters of the review along with the vote of the user
+func (m *postgresDBRepo) GetReviewVoteCount(review_id, user_id int) (*models.ReviewVoteCount, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `SELECT ` + reviewVoteCountColumns + `
+		FROM reviews r
+		LEFT JOIN review_votes v ON v.review_id = r.id
+		WHERE r.id = $1
+		GROUP BY r.id
+	`
+	count := &models.ReviewVoteCount{}
+	if err := m.DB.QueryRowContext(ctx, query, review_id, user_id).Scan(
+		&count.ReviewID,
+		&count.Helpful,
+		&count.Unhelpful,
+		&count.UserVote,
+	); err != nil {
+		return nil, err
+	}
+	return count, nil
+}
+
+// GetReviewVoteCountsByBookID returns the vote counters of every review of the book keyed by the review id.
+// The vote of the user is included, pass 0 for anonymous readers.
+func (m *postgresDBRepo) GetReviewVoteCountsByBookID(book_id, user_id int) (map[int]*models.ReviewVoteCount, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `SELECT ` + reviewVoteCountColumns + `
+		FROM reviews r
+		LEFT JOIN review_votes v ON v.review_id = r.id
+		WHERE r.book_id = $1
+		GROUP BY r.id
+	`
+	rows, err := m.DB.QueryContext(ctx, query, book_id, user_id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	counts := make(map[int]*models.ReviewVoteCount)
+	for rows.Next() {
+		count := &models.ReviewVoteCount{}
+		if err := rows.Scan(
+			&count.ReviewID,
+			&count.Helpful,
+			&count.Unhelpful,
+			&count.UserVote,
+		); err != nil {
+			return nil, err
+		}
+		counts[count.ReviewID] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
@@ -0,0 +1,265 @@
+package dbrepo
+
+import (
+	"context"
+	"time"
+
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+)
+
+// Series interface implementations
+
+// AllSeries returns all the series with their book counts ordered by name
+func (m *postgresDBRepo) AllSeries() ([]*models.Series, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `
+		SELECT s.id, s.name, s.description, s.created_at, s.updated_at,
+			(SELECT COUNT(*) FROM book_series AS bs WHERE bs.series_id = s.id)
+		FROM series AS s
+		ORDER BY LOWER(s.name)
+	`
+	rows, err := m.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	series := []*models.Series{}
+	for rows.Next() {
+		s := &models.Series{}
+		if err := rows.Scan(
+			&s.ID,
+			&s.Name,
+			&s.Description,
+			&s.CreatedAt,
+			&s.UpdatedAt,
+			&s.BookCount,
+		); err != nil {
+			return nil, err
+		}
+		series = append(series, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+// InsertSeries adds the new series to db
+func (m *postgresDBRepo) InsertSeries(s *models.Series) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	stmt := `
+		INSERT INTO series (name, description, created_at, updated_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+	return m.DB.QueryRowContext(ctx, stmt, s.Name, s.Description, s.CreatedAt, s.UpdatedAt).Scan(&s.ID)
+}
+
+// UpdateSeries updates the name and description of the existing series
+func (m *postgresDBRepo) UpdateSeries(s *models.Series) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	stmt := `
+		UPDATE series
+		SET name = $2, description = $3, updated_at = $4
+		WHERE id = $1
+	`
+	_, err := m.DB.ExecContext(ctx, stmt, s.ID, s.Name, s.Description, s.UpdatedAt)
+	return err
+}
+
+// DeleteSeries deletes the series with its book memberships
+func (m *postgresDBRepo) DeleteSeries(id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	_, err := m.DB.ExecContext(ctx, `DELETE FROM series WHERE id = $1`, id)
+	return err
+}
+
+// GetSeriesByID returns the series with its book count
+func (m *postgresDBRepo) GetSeriesByID(id int) (*models.Series, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `
+		SELECT s.id, s.name, s.description, s.created_at, s.updated_at,
+			(SELECT COUNT(*) FROM book_series AS bs WHERE bs.series_id = s.id)
+		FROM series AS s
+		WHERE s.id = $1
+	`
+	s := &models.Series{}
+	if err := m.DB.QueryRowContext(ctx, query, id).Scan(
+		&s.ID,
+		&s.Name,
+		&s.Description,
+		&s.CreatedAt,
+		&s.UpdatedAt,
+		&s.BookCount,
+	); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// SeriesExists returns true if another series than the one with the id has the name.
+// Pass a zero id when adding a new series.
+func (m *postgresDBRepo) SeriesExists(name string, id int) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM series WHERE LOWER(name) = LOWER($1) AND id <> $2)`
+	if err := m.DB.QueryRowContext(ctx, query, name, id).Scan(&exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// GetSeriesBooks returns the books of the series ordered by their position.
+// Inactive books are left out unless all is true.
+func (m *postgresDBRepo) GetSeriesBooks(series_id int, all bool) ([]*models.SeriesBook, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `
+		SELECT bs.series_id, bs.book_id, bs.position, b.title, b.isbn, COALESCE(b.cover, ''), COALESCE(b.is_active, FALSE)
+		FROM book_series AS bs
+		JOIN books AS b ON b.id = bs.book_id
+		WHERE bs.series_id = $1 AND ($2 OR b.is_active)
+		ORDER BY bs.position ASC
+	`
+	rows, err := m.DB.QueryContext(ctx, query, series_id, all)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	books := []*models.SeriesBook{}
+	for rows.Next() {
+		book := &models.SeriesBook{}
+		if err := rows.Scan(
+			&book.SeriesID,
+			&book.BookID,
+			&book.Position,
+			&book.Title,
+			&book.Isbn,
+			&book.Cover,
+			&book.IsActive,
+		); err != nil {
+			return nil, err
+		}
+		books = append(books, book)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return books, nil
+}
+
+// InsertSeriesBook adds the book to the series at its position
+func (m *postgresDBRepo) InsertSeriesBook(book *models.SeriesBook) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	stmt := `
+		INSERT INTO book_series (series_id, book_id, position)
+		VALUES ($1, $2, $3)
+	`
+	_, err := m.DB.ExecContext(ctx, stmt, book.SeriesID, book.BookID, book.Position)
+	return err
+}
+
+// UpdateSeriesBookPosition moves the book to another position in the series
+func (m *postgresDBRepo) UpdateSeriesBookPosition(book *models.SeriesBook) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	stmt := `
+		UPDATE book_series
+		SET position = $3
+		WHERE series_id = $1 AND book_id = $2
+	`
+	_, err := m.DB.ExecContext(ctx, stmt, book.SeriesID, book.BookID, book.Position)
+	return err
+}
+
+// DeleteSeriesBook removes the book from the series
+func (m *postgresDBRepo) DeleteSeriesBook(series_id, book_id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	_, err := m.DB.ExecContext(ctx, `DELETE FROM book_series WHERE series_id = $1 AND book_id = $2`, series_id, book_id)
+	return err
+}
+
+// SeriesBookExists returns true if the book is in the series
+func (m *postgresDBRepo) SeriesBookExists(series_id, book_id int) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM book_series WHERE series_id = $1 AND book_id = $2)`
+	if err := m.DB.QueryRowContext(ctx, query, series_id, book_id).Scan(&exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// SeriesPositionTaken returns true if another book than the one with the id is at the position in the series
+func (m *postgresDBRepo) SeriesPositionTaken(series_id, book_id int, position float64) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	var taken bool
+	query := `SELECT EXISTS (SELECT 1 FROM book_series WHERE series_id = $1 AND book_id <> $2 AND position = $3)`
+	if err := m.DB.QueryRowContext(ctx, query, series_id, book_id, position).Scan(&taken); err != nil {
+		return false, err
+	}
+	return taken, nil
+}
+
+// GetBookSeries returns the series the book belongs to with its position and the next active book of every series
+func (m *postgresDBRepo) GetBookSeries(book_id int) ([]*models.BookSeries, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `
+		SELECT s.id, s.name, bs.position,
+			COALESCE(n.book_id, 0), COALESCE(n.position, 0), COALESCE(n.title, ''), COALESCE(n.isbn, 0), COALESCE(n.cover, '')
+		FROM book_series AS bs
+		JOIN series AS s ON s.id = bs.series_id
+		LEFT JOIN LATERAL (
+			SELECT nbs.book_id, nbs.position, b.title, b.isbn, b.cover
+			FROM book_series AS nbs
+			JOIN books AS b ON b.id = nbs.book_id
+			WHERE nbs.series_id = bs.series_id AND nbs.position > bs.position AND b.is_active
+			ORDER BY nbs.position ASC
+			LIMIT 1
+		) AS n ON TRUE
+		WHERE bs.book_id = $1
+		ORDER BY LOWER(s.name)
+	`
+	rows, err := m.DB.QueryContext(ctx, query, book_id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	series := []*models.BookSeries{}
+	for rows.Next() {
+		s := &models.BookSeries{}
+		next := &models.SeriesBook{IsActive: true}
+		if err := rows.Scan(
+			&s.SeriesID,
+			&s.SeriesName,
+			&s.Position,
+			&next.BookID,
+			&next.Position,
+			&next.Title,
+			&next.Isbn,
+			&next.Cover,
+		); err != nil {
+			return nil, err
+		}
+		if next.BookID != 0 {
+			next.SeriesID = s.SeriesID
+			s.Next = next
+		}
+		series = append(series, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return series, nil
+}
@@ -0,0 +1,281 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+)
+
+// shelfColumns are the columns of a shelf with its owner and book count in the order they are scanned by scanShelf
+const shelfColumns = `
+	s.id, s.user_id, u.username, s.name, s.description, s.is_public, s.share_token, s.created_at, s.updated_at,
+	(SELECT COUNT(*) FROM shelf_books AS sb WHERE sb.shelf_id = s.id)
+`
+
+// scanShelf scans the shelfColumns of the row into a shelf
+func scanShelf(row interface{ Scan(...any) error }) (*models.Shelf, error) {
+	shelf := &models.Shelf{}
+	if err := row.Scan(
+		&shelf.ID,
+		&shelf.UserID,
+		&shelf.Username,
+		&shelf.Name,
+		&shelf.Description,
+		&shelf.IsPublic,
+		&shelf.ShareToken,
+		&shelf.CreatedAt,
+		&shelf.UpdatedAt,
+		&shelf.BookCount,
+	); err != nil {
+		return nil, err
+	}
+	return shelf, nil
+}
+
+// InsertShelf adds the new shelf of the user
+func (m *postgresDBRepo) InsertShelf(shelf *models.Shelf) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	stmt := `
+		INSERT INTO shelves (user_id, name, description, is_public, share_token, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+	return m.DB.QueryRowContext(ctx, stmt,
+		shelf.UserID,
+		shelf.Name,
+		shelf.Description,
+		shelf.IsPublic,
+		shelf.ShareToken,
+		shelf.CreatedAt,
+		shelf.UpdatedAt,
+	).Scan(&shelf.ID)
+}
+
+// UpdateShelf updates the name, description and visibility of the shelf owned by the user
+func (m *postgresDBRepo) UpdateShelf(shelf *models.Shelf) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	stmt := `
+		UPDATE shelves
+		SET name = $3, description = $4, is_public = $5, updated_at = $6
+		WHERE id = $1 AND user_id = $2
+	`
+	_, err := m.DB.ExecContext(ctx, stmt, shelf.ID, shelf.UserID, shelf.Name, shelf.Description, shelf.IsPublic, shelf.UpdatedAt)
+	return err
+}
+
+// DeleteShelf removes the shelf owned by the user with its books
+func (m *postgresDBRepo) DeleteShelf(id, user_id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	_, err := m.DB.ExecContext(ctx, `DELETE FROM shelves WHERE id = $1 AND user_id = $2`, id, user_id)
+	return err
+}
+
+// ShelfNameExists returns true if the user has another shelf with the name
+func (m *postgresDBRepo) ShelfNameExists(user_id int, name string, exclude_id int) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM shelves WHERE user_id = $1 AND LOWER(name) = LOWER($2) AND id <> $3)`
+	if err := m.DB.QueryRowContext(ctx, query, user_id, name, exclude_id).Scan(&exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// GetShelfByID returns the shelf of the id with its book count
+func (m *postgresDBRepo) GetShelfByID(id int) (*models.Shelf, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `SELECT ` + shelfColumns + ` FROM shelves AS s JOIN users AS u ON u.id = s.user_id WHERE s.id = $1`
+	return scanShelf(m.DB.QueryRowContext(ctx, query, id))
+}
+
+// GetShelfByToken returns the shelf of the share token with its book count
+func (m *postgresDBRepo) GetShelfByToken(token string) (*models.Shelf, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `SELECT ` + shelfColumns + ` FROM shelves AS s JOIN users AS u ON u.id = s.user_id WHERE s.share_token = $1`
+	return scanShelf(m.DB.QueryRowContext(ctx, query, token))
+}
+
+// GetShelvesByUserID returns the shelves of the user ordered by name.
+// HasBook is set on the shelves holding the book, book_id is zero when no book is checked.
+func (m *postgresDBRepo) GetShelvesByUserID(user_id, book_id int) ([]*models.Shelf, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `
+		SELECT ` + shelfColumns + `,
+			EXISTS (SELECT 1 FROM shelf_books AS sb WHERE sb.shelf_id = s.id AND sb.book_id = $2)
+		FROM shelves AS s
+		JOIN users AS u ON u.id = s.user_id
+		WHERE s.user_id = $1
+		ORDER BY LOWER(s.name)
+	`
+	rows, err := m.DB.QueryContext(ctx, query, user_id, book_id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	shelves := []*models.Shelf{}
+	for rows.Next() {
+		shelf := &models.Shelf{}
+		if err := rows.Scan(
+			&shelf.ID,
+			&shelf.UserID,
+			&shelf.Username,
+			&shelf.Name,
+			&shelf.Description,
+			&shelf.IsPublic,
+			&shelf.ShareToken,
+			&shelf.CreatedAt,
+			&shelf.UpdatedAt,
+			&shelf.BookCount,
+			&shelf.HasBook,
+		); err != nil {
+			return nil, err
+		}
+		shelves = append(shelves, shelf)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return shelves, nil
+}
+
+// AddBookToShelf puts the book at the end of the shelf. Adding a book already on the shelf does nothing.
+func (m *postgresDBRepo) AddBookToShelf(shelf_id, book_id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	stmt := `
+		INSERT INTO shelf_books (shelf_id, book_id, position, added_at)
+		SELECT $1, $2, COALESCE(MAX(position), 0) + 1, $3
+		FROM shelf_books
+		WHERE shelf_id = $1
+		ON CONFLICT (shelf_id, book_id) DO NOTHING
+	`
+	_, err := m.DB.ExecContext(ctx, stmt, shelf_id, book_id, time.Now())
+	return err
+}
+
+// RemoveBookFromShelf takes the book off the shelf
+func (m *postgresDBRepo) RemoveBookFromShelf(shelf_id, book_id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	_, err := m.DB.ExecContext(ctx, `DELETE FROM shelf_books WHERE shelf_id = $1 AND book_id = $2`, shelf_id, book_id)
+	return err
+}
+
+// MoveShelfBook swaps the position of the book with the book before it when up is true or after it otherwise.
+// Moving the first book up or the last book down does nothing.
+func (m *postgresDBRepo) MoveShelfBook(shelf_id, book_id int, up bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	var position int
+	if err := tx.QueryRowContext(ctx,
+		`SELECT position FROM shelf_books WHERE shelf_id = $1 AND book_id = $2 FOR UPDATE`,
+		shelf_id, book_id,
+	).Scan(&position); err != nil {
+		return err
+	}
+	query := `
+		SELECT book_id, position FROM shelf_books
+		WHERE shelf_id = $1 AND position > $2
+		ORDER BY position ASC
+		LIMIT 1
+		FOR UPDATE
+	`
+	if up {
+		query = `
+			SELECT book_id, position FROM shelf_books
+			WHERE shelf_id = $1 AND position < $2
+			ORDER BY position DESC
+			LIMIT 1
+			FOR UPDATE
+		`
+	}
+	var other_id, other_position int
+	if err := tx.QueryRowContext(ctx, query, shelf_id, position).Scan(&other_id, &other_position); err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+	stmt := `UPDATE shelf_books SET position = $3 WHERE shelf_id = $1 AND book_id = $2`
+	if _, err := tx.ExecContext(ctx, stmt, shelf_id, book_id, other_position); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, stmt, shelf_id, other_id, position); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetShelfBooks returns the books on the shelf filtered by the title or isbn.
+// The books are in shelf order unless sort is asc or desc, which orders them by title.
+func (m *postgresDBRepo) GetShelfBooks(shelf_id, limit, page int, searchKey, sort string) (*models.BookApiFilter, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if limit <= 0 {
+		limit = 10
+	}
+	if page <= 0 {
+		page = 1
+	}
+	offset := (page - 1) * limit
+
+	filter := `
+		FROM shelf_books AS sb
+		JOIN books AS b ON b.id = sb.book_id
+		WHERE sb.shelf_id = $1 AND (b.title ILIKE $2 OR CAST(b.isbn AS TEXT) ILIKE $2)
+	`
+	search := "%" + searchKey + "%"
+	var count int
+	if err := m.DB.QueryRowContext(ctx, `SELECT COUNT(*) `+filter, shelf_id, search).Scan(&count); err != nil {
+		return nil, err
+	}
+	order := "sb.position ASC"
+	switch sort {
+	case "asc":
+		order = "b.title ASC"
+	case "desc":
+		order = "b.title DESC"
+	}
+	query := `SELECT b.id, b.title, b.isbn, COALESCE(b.cover, '') ` + filter + ` ORDER BY ` + order + ` LIMIT $3 OFFSET $4`
+	rows, err := m.DB.QueryContext(ctx, query, shelf_id, search, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	books := []*models.Book{}
+	for rows.Next() {
+		book := &models.Book{}
+		if err := rows.Scan(
+			&book.ID,
+			&book.Title,
+			&book.Isbn,
+			&book.Cover,
+		); err != nil {
+			return nil, err
+		}
+		books = append(books, book)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return &models.BookApiFilter{
+		Total:    count,
+		LastPage: m.CalculateLastPage(limit, count),
+		Page:     page,
+		Books:    books,
+	}, nil
+}
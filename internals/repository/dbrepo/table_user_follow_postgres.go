@@ -0,0 +1,146 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+)
+
+// GetUserByUsername returns the public fields of the user with the username
+func (m *postgresDBRepo) GetUserByUsername(username string) (*models.User, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `
+		SELECT id, username, access_level, created_at, updated_at
+		FROM users
+		WHERE username = $1
+	`
+	user := &models.User{}
+	if err := m.DB.QueryRowContext(ctx, query, username).Scan(
+		&user.ID,
+		&user.Username,
+		&user.AccessLevel,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// FollowUser makes the follower follow the followee. It returns false when the follower already follows the followee.
+func (m *postgresDBRepo) FollowUser(follower_id, followee_id int) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	stmt := `
+		INSERT INTO user_follows (follower_id, followee_id, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (follower_id, followee_id) DO NOTHING
+	`
+	res, err := m.DB.ExecContext(ctx, stmt, follower_id, followee_id, time.Now())
+	if err != nil {
+		return false, err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// UnfollowUser makes the follower stop following the followee
+func (m *postgresDBRepo) UnfollowUser(follower_id, followee_id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	_, err := m.DB.ExecContext(ctx, `DELETE FROM user_follows WHERE follower_id = $1 AND followee_id = $2`, follower_id, followee_id)
+	return err
+}
+
+// UserFollowExists returns true if the follower follows the followee
+func (m *postgresDBRepo) UserFollowExists(follower_id, followee_id int) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM user_follows WHERE follower_id = $1 AND followee_id = $2)`
+	if err := m.DB.QueryRowContext(ctx, query, follower_id, followee_id).Scan(&exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// UserFollowCounts returns the number of users following the user and the number of users the user follows
+func (m *postgresDBRepo) UserFollowCounts(user_id int) (followers, followings int, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM user_follows WHERE followee_id = $1),
+			(SELECT COUNT(*) FROM user_follows WHERE follower_id = $1)
+	`
+	err = m.DB.QueryRowContext(ctx, query, user_id).Scan(&followers, &followings)
+	return followers, followings, err
+}
+
+// GetPrivacySettings returns the privacy settings of the user, or the defaults when the user never changed them
+func (m *postgresDBRepo) GetPrivacySettings(user_id int) (*models.PrivacySettings, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `
+		SELECT user_id, activity_visibility, show_reviews, show_shelves, show_stats, show_followed_authors, updated_at
+		FROM privacy_settings
+		WHERE user_id = $1
+	`
+	settings := &models.PrivacySettings{}
+	err := m.DB.QueryRowContext(ctx, query, user_id).Scan(
+		&settings.UserID,
+		&settings.ActivityVisibility,
+		&settings.ShowReviews,
+		&settings.ShowShelves,
+		&settings.ShowStats,
+		&settings.ShowFollowedAuthors,
+		&settings.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return &models.PrivacySettings{
+			UserID:              user_id,
+			ActivityVisibility:  models.VisibilityPublic,
+			ShowReviews:         true,
+			ShowShelves:         true,
+			ShowStats:           true,
+			ShowFollowedAuthors: true,
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// UpdatePrivacySettings stores the privacy settings of the user
+func (m *postgresDBRepo) UpdatePrivacySettings(settings *models.PrivacySettings) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	stmt := `
+		INSERT INTO privacy_settings (user_id, activity_visibility, show_reviews, show_shelves, show_stats, show_followed_authors, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_id) DO UPDATE SET
+			activity_visibility = EXCLUDED.activity_visibility,
+			show_reviews = EXCLUDED.show_reviews,
+			show_shelves = EXCLUDED.show_shelves,
+			show_stats = EXCLUDED.show_stats,
+			show_followed_authors = EXCLUDED.show_followed_authors,
+			updated_at = EXCLUDED.updated_at
+	`
+	_, err := m.DB.ExecContext(ctx, stmt,
+		settings.UserID,
+		settings.ActivityVisibility,
+		settings.ShowReviews,
+		settings.ShowShelves,
+		settings.ShowStats,
+		settings.ShowFollowedAuthors,
+		settings.UpdatedAt,
+	)
+	return err
+}
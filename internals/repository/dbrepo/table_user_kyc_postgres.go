@@ -108,60 +108,58 @@ func (m *postgresDBRepo) UpdateDocument(front_path, back_path string, id int) er
 	return nil
 }
 
-func (m *postgresDBRepo) AdminKycUpdate(update *models.Kyc) error {
+func (m *postgresDBRepo) AdminKycUpdate(update *models.Kyc, mails ...*models.MailData) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 	stmt := `
 		UPDATE kycs 
 		SET first_name = $2, last_name = $3, gender = $4, phone = $5, address = $6, dob = $7, is_validated = $8, document_type = $9, document_number = $10, updated_at = $11
 		WHERE user_id=$1`
-	_, err := m.DB.ExecContext(
-		ctx,
-		stmt,
-		update.UserID,
-		update.FirstName,
-		update.LastName,
-		update.Gender,
-		update.Phone,
-		update.Address,
-		update.DateOfBirth,
-		update.IsValidated,
-		update.DocumentType,
-		update.DocumentNumber,
-		update.UpdatedAt,
-	)
-	if err != nil {
+	return m.execWithMails(ctx, mails, func(db execer) error {
+		_, err := db.ExecContext(
+			ctx,
+			stmt,
+			update.UserID,
+			update.FirstName,
+			update.LastName,
+			update.Gender,
+			update.Phone,
+			update.Address,
+			update.DateOfBirth,
+			update.IsValidated,
+			update.DocumentType,
+			update.DocumentNumber,
+			update.UpdatedAt,
+		)
 		return err
-	}
-	return nil
+	})
 }
 
-func (m *postgresDBRepo) PublicKycUpdate(update *models.Kyc) error {
+func (m *postgresDBRepo) PublicKycUpdate(update *models.Kyc, mails ...*models.MailData) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 	stmt := `
 		UPDATE kycs 
 		SET first_name = $2, last_name = $3, gender = $4, phone = $5, address = $6, dob = $7, document_type = $8, document_number = $9, document_front = $10, document_back = $11, is_validated = $12, updated_at = $13
 		WHERE id=$1`
-	_, err := m.DB.ExecContext(
-		ctx,
-		stmt,
-		update.ID,
-		update.FirstName,
-		update.LastName,
-		update.Gender,
-		update.Phone,
-		update.Address,
-		update.DateOfBirth,
-		update.DocumentType,
-		update.DocumentNumber,
-		update.DocumentFront,
-		update.DocumentBack,
-		update.IsValidated,
-		update.UpdatedAt,
-	)
-	if err != nil {
+	return m.execWithMails(ctx, mails, func(db execer) error {
+		_, err := db.ExecContext(
+			ctx,
+			stmt,
+			update.ID,
+			update.FirstName,
+			update.LastName,
+			update.Gender,
+			update.Phone,
+			update.Address,
+			update.DateOfBirth,
+			update.DocumentType,
+			update.DocumentNumber,
+			update.DocumentFront,
+			update.DocumentBack,
+			update.IsValidated,
+			update.UpdatedAt,
+		)
 		return err
-	}
-	return nil
+	})
 }
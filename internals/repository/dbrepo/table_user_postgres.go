@@ -5,8 +5,8 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/ishanshre/Book-Review-Platform/internals/helpers"
 	"github.com/ishanshre/Book-Review-Platform/internals/models"
-	"golang.org/x/crypto/bcrypt"
 )
 
 // AllUsers returns list of all the users with all access level
@@ -196,7 +196,7 @@ func (m *postgresDBRepo) UpdateUser(u *models.User) error {
 
 // InsertUser insert new user into database.
 // This method is used for new user sign up
-func (m *postgresDBRepo) InsertUser(u *models.User) error {
+func (m *postgresDBRepo) InsertUser(u *models.User, mails ...*models.MailData) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 	db, err := m.DB.BeginTx(ctx, nil)
@@ -234,6 +234,7 @@ func (m *postgresDBRepo) InsertUser(u *models.User) error {
 	if err := res.Scan(&id); err != nil {
 		return err
 	}
+	u.ID = id
 	kycquery := `
 		INSERT INTO kycs (user_id, first_name, last_name, gender, address, phone, profile_pic, dob, document_number, document_front, document_back, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 	`
@@ -260,6 +261,11 @@ func (m *postgresDBRepo) InsertUser(u *models.User) error {
 	if rows_affected == 0 {
 		return fmt.Errorf("no rows affected")
 	}
+	for _, msg := range mails {
+		if err = insertEmailOutbox(ctx, db, msg); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -329,15 +335,42 @@ func (m *postgresDBRepo) Authenticate(username, testPassword string) (int, int,
 	if err := row.Scan(&id, &hashedPassword, &access_level, &is_validated); err != nil {
 		return id, 2, false, err
 	}
-	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(testPassword))
-	if err == bcrypt.ErrMismatchedHashAndPassword {
-		return 0, 2, false, fmt.Errorf("incorrect password: %s", bcrypt.ErrMismatchedHashAndPassword)
+	err := helpers.CheckPassword(hashedPassword, testPassword)
+	if err == helpers.ErrMismatchedPassword {
+		return 0, 2, false, fmt.Errorf("incorrect password: %s", helpers.ErrMismatchedPassword)
 	} else if err != nil {
 		return 0, 2, false, err
 	}
+
+	// transparently upgrade the hash when it was created with an outdated algorithm or cost
+	if helpers.PasswordNeedsRehash(hashedPassword) {
+		if err := m.rehashPassword(id, testPassword, hashedPassword); err != nil {
+			m.App.ErrorLog.Println(err)
+		}
+	}
 	return id, access_level, is_validated, nil
 }
 
+// rehashPassword hashes the plain password with the current hasher and stores it.
+// The hash is only replaced while it is still the old hash, so a password changed in the meantime is kept.
+func (m *postgresDBRepo) rehashPassword(id int, password, old_hash string) error {
+	hashed, err := helpers.EncryptPassword(password)
+	if err != nil {
+		return fmt.Errorf("could not rehash password of user %d: %s", id, err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	stmt := `
+		UPDATE users
+		SET password = $2
+		WHERE id = $1 AND password = $3
+	`
+	if _, err := m.DB.ExecContext(ctx, stmt, id, hashed, old_hash); err != nil {
+		return fmt.Errorf("could not store rehashed password of user %d: %s", id, err)
+	}
+	return nil
+}
+
 // Get information for personal profile page
 func (m *postgresDBRepo) GetProfilePersonal(id int) (*models.User, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
@@ -394,7 +427,7 @@ func (m *postgresDBRepo) EmailExists(email string) (bool, error) {
 }
 
 // ChangePassword chnage the password using email
-func (m *postgresDBRepo) ChangePassword(password, email string) error {
+func (m *postgresDBRepo) ChangePassword(password, email string, mails ...*models.MailData) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 	stmt := `
@@ -402,15 +435,17 @@ func (m *postgresDBRepo) ChangePassword(password, email string) error {
 		SET password = $2, updated_at = $3
 		WHERE email = $1
 	`
-	res, err := m.DB.ExecContext(ctx, stmt, email, password, time.Now())
-	if err != nil {
-		return err
-	}
-	rows_affected, _ := res.RowsAffected()
-	if rows_affected == 0 {
-		return fmt.Errorf("error in changing the password")
-	}
-	return nil
+	return m.execWithMails(ctx, mails, func(db execer) error {
+		res, err := db.ExecContext(ctx, stmt, email, password, time.Now())
+		if err != nil {
+			return err
+		}
+		rows_affected, _ := res.RowsAffected()
+		if rows_affected == 0 {
+			return fmt.Errorf("error in changing the password")
+		}
+		return nil
+	})
 }
 
 // UserListFilter
@@ -0,0 +1,123 @@
+package dbrepo
+
+import (
+	"context"
+	"time"
+
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+)
+
+// GetUserReviews returns the newest active reviews the user wrote on active books
+func (m *postgresDBRepo) GetUserReviews(user_id, limit int) ([]*models.FeedReview, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `
+		SELECT r.id, r.rating, r.body, u.username, b.title, b.isbn, r.created_at
+		FROM reviews AS r
+		JOIN books AS b ON b.id = r.book_id
+		JOIN users AS u ON u.id = r.user_id
+		WHERE r.user_id = $1 AND r.is_active AND b.is_active
+		ORDER BY r.created_at DESC, r.id DESC
+		LIMIT $2
+	`
+	rows, err := m.DB.QueryContext(ctx, query, user_id, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	reviews := []*models.FeedReview{}
+	for rows.Next() {
+		review := &models.FeedReview{}
+		if err := rows.Scan(
+			&review.ID,
+			&review.Rating,
+			&review.Body,
+			&review.Username,
+			&review.BookTitle,
+			&review.BookIsbn,
+			&review.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, review)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return reviews, nil
+}
+
+// GetPublicShelvesByUserID returns the public shelves of the user
+func (m *postgresDBRepo) GetPublicShelvesByUserID(user_id int) ([]*models.Shelf, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `
+		SELECT ` + shelfColumns + `
+		FROM shelves AS s
+		JOIN users AS u ON u.id = s.user_id
+		WHERE s.user_id = $1 AND s.is_public
+		ORDER BY LOWER(s.name)
+	`
+	rows, err := m.DB.QueryContext(ctx, query, user_id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	shelves := []*models.Shelf{}
+	for rows.Next() {
+		shelf, err := scanShelf(rows)
+		if err != nil {
+			return nil, err
+		}
+		shelves = append(shelves, shelf)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return shelves, nil
+}
+
+// GetReadingStats returns the books the user finished per year, newest year first, the number of their active reviews
+// with the average rating they gave, and their favourite genres.
+// A book read again is counted once in its year and once in the total.
+func (m *postgresDBRepo) GetReadingStats(user_id, genres int) (*models.ReadingStats, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	stats := &models.ReadingStats{
+		FinishedPerYear: []*models.YearCount{},
+	}
+	query := `
+		SELECT EXTRACT(YEAR FROM finished_at)::INTEGER AS year, COUNT(DISTINCT book_id)
+		FROM read_list_reads
+		WHERE user_id = $1
+		GROUP BY year
+		ORDER BY year DESC
+	`
+	rows, err := m.DB.QueryContext(ctx, query, user_id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		year := &models.YearCount{}
+		if err := rows.Scan(&year.Year, &year.Count); err != nil {
+			return nil, err
+		}
+		stats.FinishedPerYear = append(stats.FinishedPerYear, year)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	query = `SELECT COUNT(DISTINCT book_id) FROM read_list_reads WHERE user_id = $1`
+	if err := m.DB.QueryRowContext(ctx, query, user_id).Scan(&stats.TotalFinished); err != nil {
+		return nil, err
+	}
+	query = `SELECT COUNT(*), COALESCE(AVG(rating), 0) FROM reviews WHERE user_id = $1 AND is_active`
+	if err := m.DB.QueryRowContext(ctx, query, user_id).Scan(&stats.ReviewCount, &stats.AverageRating); err != nil {
+		return nil, err
+	}
+	if stats.FavouriteGenres, err = m.FavouriteGenres(user_id, genres); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
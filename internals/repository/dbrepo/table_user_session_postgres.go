@@ -0,0 +1,158 @@
+package dbrepo
+
+import (
+	"context"
+	"time"
+
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+)
+
+// SaveUserSession inserts a new session record.
+// If the token already exists only the last seen time, ip address and user agent are updated.
+func (m *postgresDBRepo) SaveUserSession(u *models.UserSession) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	stmt := `
+		INSERT INTO user_sessions (user_id, token, ip_address, user_agent, device, created_at, last_seen)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (token) DO UPDATE
+		SET ip_address = EXCLUDED.ip_address, user_agent = EXCLUDED.user_agent, device = EXCLUDED.device, last_seen = EXCLUDED.last_seen
+	`
+	_, err := m.DB.ExecContext(
+		ctx,
+		stmt,
+		u.UserID,
+		u.Token,
+		u.IpAddress,
+		u.UserAgent,
+		u.Device,
+		u.CreatedAt,
+		u.LastSeen,
+	)
+	return err
+}
+
+// TouchUserSession updates the last seen time, ip address and user agent of the session with the token.
+// It returns false when the session is no longer indexed because it was revoked or has expired, it is never inserted again.
+func (m *postgresDBRepo) TouchUserSession(u *models.UserSession) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	stmt := `
+		UPDATE user_sessions
+		SET ip_address = $2, user_agent = $3, device = $4, last_seen = $5
+		WHERE token = $1
+	`
+	result, err := m.DB.ExecContext(ctx, stmt, u.Token, u.IpAddress, u.UserAgent, u.Device, u.LastSeen)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// DeleteExpiredUserSessions deletes the session records created before the time, their sessions have expired
+func (m *postgresDBRepo) DeleteExpiredUserSessions(before time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	stmt := `DELETE FROM user_sessions WHERE created_at < $1`
+	_, err := m.DB.ExecContext(ctx, stmt, before)
+	return err
+}
+
+// GetUserSessionsByUserID returns all the active sessions of a user, most recently seen first
+func (m *postgresDBRepo) GetUserSessionsByUserID(user_id int) ([]*models.UserSession, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT id, user_id, token, ip_address, user_agent, device, created_at, last_seen
+		FROM user_sessions
+		WHERE user_id = $1
+		ORDER BY last_seen DESC
+	`
+	rows, err := m.DB.QueryContext(ctx, query, user_id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	sessions := []*models.UserSession{}
+	for rows.Next() {
+		session := &models.UserSession{}
+		if err := rows.Scan(
+			&session.ID,
+			&session.UserID,
+			&session.Token,
+			&session.IpAddress,
+			&session.UserAgent,
+			&session.Device,
+			&session.CreatedAt,
+			&session.LastSeen,
+		); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+// GetUserSessionByID returns a single session record using its id
+func (m *postgresDBRepo) GetUserSessionByID(id int) (*models.UserSession, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT id, user_id, token, ip_address, user_agent, device, created_at, last_seen
+		FROM user_sessions
+		WHERE id = $1
+	`
+	session := &models.UserSession{}
+	if err := m.DB.QueryRowContext(ctx, query, id).Scan(
+		&session.ID,
+		&session.UserID,
+		&session.Token,
+		&session.IpAddress,
+		&session.UserAgent,
+		&session.Device,
+		&session.CreatedAt,
+		&session.LastSeen,
+	); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// DeleteUserSession deletes the session record using its id
+func (m *postgresDBRepo) DeleteUserSession(id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	stmt := `DELETE FROM user_sessions WHERE id = $1`
+	_, err := m.DB.ExecContext(ctx, stmt, id)
+	return err
+}
+
+// DeleteUserSessionByToken deletes the session record using the session token
+func (m *postgresDBRepo) DeleteUserSessionByToken(token string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	stmt := `DELETE FROM user_sessions WHERE token = $1`
+	_, err := m.DB.ExecContext(ctx, stmt, token)
+	return err
+}
+
+// DeleteAllUserSessions deletes every session record of a user
+func (m *postgresDBRepo) DeleteAllUserSessions(user_id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	stmt := `DELETE FROM user_sessions WHERE user_id = $1`
+	_, err := m.DB.ExecContext(ctx, stmt, user_id)
+	return err
+}
@@ -0,0 +1,315 @@
+package dbrepo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+	"github.com/lib/pq"
+)
+
+// AllWebhooks returns all the webhooks
+func (m *postgresDBRepo) AllWebhooks() ([]*models.Webhook, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `
+		SELECT id, url, secret, events, is_active, created_at, updated_at
+		FROM webhooks
+		ORDER BY id
+	`
+	rows, err := m.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	webhooks := []*models.Webhook{}
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// GetWebhookByID returns the webhook
+func (m *postgresDBRepo) GetWebhookByID(id int) (*models.Webhook, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `
+		SELECT id, url, secret, events, is_active, created_at, updated_at
+		FROM webhooks
+		WHERE id = $1
+	`
+	return scanWebhook(m.DB.QueryRowContext(ctx, query, id))
+}
+
+// InsertWebhook adds a new webhook
+func (m *postgresDBRepo) InsertWebhook(u *models.Webhook) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	stmt := `
+		INSERT INTO webhooks (url, secret, events, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`
+	return m.DB.QueryRowContext(
+		ctx,
+		stmt,
+		u.Url,
+		u.Secret,
+		pq.Array(u.Events),
+		u.IsActive,
+		u.CreatedAt,
+		u.UpdatedAt,
+	).Scan(&u.ID)
+}
+
+// UpdateWebhook updates the url, secret, events and status of the webhook
+func (m *postgresDBRepo) UpdateWebhook(u *models.Webhook) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	stmt := `
+		UPDATE webhooks
+		SET url = $2, secret = $3, events = $4, is_active = $5, updated_at = $6
+		WHERE id = $1
+	`
+	res, err := m.DB.ExecContext(ctx, stmt, u.ID, u.Url, u.Secret, pq.Array(u.Events), u.IsActive, u.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	rows_affected, _ := res.RowsAffected()
+	if rows_affected == 0 {
+		return fmt.Errorf("webhook with id %d does not exists", u.ID)
+	}
+	return nil
+}
+
+// DeleteWebhook deletes the webhook along with its delivery log
+func (m *postgresDBRepo) DeleteWebhook(id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	stmt := `DELETE FROM webhooks WHERE id = $1`
+	res, err := m.DB.ExecContext(ctx, stmt, id)
+	if err != nil {
+		return err
+	}
+	rows_affected, _ := res.RowsAffected()
+	if rows_affected == 0 {
+		return fmt.Errorf("webhook with id %d does not exists", id)
+	}
+	return nil
+}
+
+// EnqueueWebhookEvent queues a delivery of the payload for every active webhook subscribed to the event
+func (m *postgresDBRepo) EnqueueWebhookEvent(event string, payload []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	stmt := `
+		INSERT INTO webhook_deliveries (webhook_id, event, payload, status, next_attempt_at, created_at)
+		SELECT id, $1::text, $2::jsonb, $3::text, $4::timestamptz, $4::timestamptz
+		FROM webhooks
+		WHERE is_active = TRUE AND $1::text = ANY(events)
+	`
+	_, err := m.DB.ExecContext(ctx, stmt, event, string(payload), models.WebhookDeliveryPending, time.Now())
+	return err
+}
+
+// EnqueueWebhookDelivery queues a delivery of the payload to a single webhook regardless of its events
+func (m *postgresDBRepo) EnqueueWebhookDelivery(webhook_id int, event string, payload []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	stmt := `
+		INSERT INTO webhook_deliveries (webhook_id, event, payload, status, next_attempt_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $5)
+	`
+	_, err := m.DB.ExecContext(ctx, stmt, webhook_id, event, string(payload), models.WebhookDeliveryPending, time.Now())
+	return err
+}
+
+// ClaimPendingWebhookDeliveries returns the pending deliveries of active webhooks that are due
+// and locks them for the lease duration, so that other workers do not pick them up while they are being sent.
+func (m *postgresDBRepo) ClaimPendingWebhookDeliveries(limit int, lease time.Duration) ([]*models.WebhookDelivery, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	query := `
+		UPDATE webhook_deliveries d
+		SET next_attempt_at = $3
+		FROM webhooks w
+		WHERE w.id = d.webhook_id AND d.id IN (
+			SELECT wd.id FROM webhook_deliveries wd
+			JOIN webhooks wh ON wh.id = wd.webhook_id
+			WHERE wd.status = $1 AND wd.next_attempt_at <= NOW() AND wh.is_active = TRUE
+			ORDER BY wd.next_attempt_at
+			LIMIT $2
+			FOR UPDATE OF wd SKIP LOCKED
+		)
+		RETURNING d.id, d.webhook_id, d.event, d.payload, d.status, d.attempts, d.max_attempts, d.response_status, d.response_body, d.last_error, d.next_attempt_at, d.created_at, d.delivered_at, w.url, w.secret
+	`
+	rows, err := m.DB.QueryContext(ctx, query, models.WebhookDeliveryPending, limit, time.Now().Add(lease))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	deliveries := []*models.WebhookDelivery{}
+	for rows.Next() {
+		delivery := &models.WebhookDelivery{}
+		if err := scanWebhookDelivery(rows, delivery, &delivery.Url, &delivery.Secret); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// MarkWebhookDelivered marks the delivery as delivered with the response of the endpoint
+func (m *postgresDBRepo) MarkWebhookDelivered(id, responseStatus int, responseBody string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	stmt := `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = attempts + 1, response_status = $3, response_body = $4, last_error = '', delivered_at = $5
+		WHERE id = $1
+	`
+	_, err := m.DB.ExecContext(ctx, stmt, id, models.WebhookDeliveryDelivered, responseStatus, responseBody, time.Now())
+	return err
+}
+
+// MarkWebhookDeliveryFailed records a failed attempt and schedules the next one.
+// The delivery is marked as failed once it has reached its max attempts.
+func (m *postgresDBRepo) MarkWebhookDeliveryFailed(id, responseStatus int, responseBody, lastError string, nextAttemptAt time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	stmt := `
+		UPDATE webhook_deliveries
+		SET
+			attempts = attempts + 1,
+			response_status = $2,
+			response_body = $3,
+			last_error = $4,
+			next_attempt_at = $5,
+			status = CASE WHEN attempts + 1 >= max_attempts THEN $6 ELSE status END
+		WHERE id = $1
+	`
+	_, err := m.DB.ExecContext(ctx, stmt, id, responseStatus, responseBody, lastError, nextAttemptAt, models.WebhookDeliveryFailed)
+	return err
+}
+
+// RedeliverWebhookDelivery puts the delivery of the webhook back to the queue with a fresh attempt count
+func (m *postgresDBRepo) RedeliverWebhookDelivery(webhook_id, id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	stmt := `
+		UPDATE webhook_deliveries
+		SET status = $3, attempts = 0, last_error = '', next_attempt_at = $4, delivered_at = NULL
+		WHERE id = $1 AND webhook_id = $2
+	`
+	res, err := m.DB.ExecContext(ctx, stmt, id, webhook_id, models.WebhookDeliveryPending, time.Now())
+	if err != nil {
+		return err
+	}
+	rows_affected, _ := res.RowsAffected()
+	if rows_affected == 0 {
+		return fmt.Errorf("webhook delivery with id %d does not exists", id)
+	}
+	return nil
+}
+
+// WebhookDeliveryFilter returns the filtered deliveries of the webhook with pagination.
+// The search key matches the event and empty status returns deliveries of every status.
+func (m *postgresDBRepo) WebhookDeliveryFilter(webhook_id, limit, page int, searchKey, status, sort string) (*models.WebhookDeliveryFilterApi, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	if limit <= 0 {
+		limit = 10
+	}
+	if page <= 0 {
+		page = 1
+	}
+	offset := (page - 1) * limit
+	sql := `
+		SELECT id, webhook_id, event, payload, status, attempts, max_attempts, response_status, response_body, last_error, next_attempt_at, created_at, delivered_at
+		FROM webhook_deliveries
+		WHERE webhook_id = $1 AND event ILIKE $2 AND ($3::text = '' OR status = $3::text)
+	`
+	countSql := `
+		SELECT COUNT(*)
+		FROM webhook_deliveries
+		WHERE webhook_id = $1 AND event ILIKE $2 AND ($3::text = '' OR status = $3::text)
+	`
+	if sort != "asc" {
+		sort = "desc"
+	}
+	sql = fmt.Sprintf("%s ORDER BY created_at %s LIMIT %d OFFSET %d", sql, sort, limit, offset)
+	search := fmt.Sprintf("%%%s%%", searchKey)
+	var count int
+	if err := m.DB.QueryRowContext(ctx, countSql, webhook_id, search, status).Scan(&count); err != nil {
+		return nil, err
+	}
+	rows, err := m.DB.QueryContext(ctx, sql, webhook_id, search, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	deliveries := []*models.WebhookDelivery{}
+	for rows.Next() {
+		delivery := &models.WebhookDelivery{}
+		if err := scanWebhookDelivery(rows, delivery); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	lastPage := m.CalculateLastPage(limit, count)
+	return &models.WebhookDeliveryFilterApi{
+		Total:      count,
+		Page:       page,
+		LastPage:   lastPage,
+		Deliveries: deliveries,
+	}, nil
+}
+
+// scanWebhook scans the webhooks columns into Webhook
+func scanWebhook(row scanner) (*models.Webhook, error) {
+	webhook := &models.Webhook{}
+	if err := row.Scan(
+		&webhook.ID,
+		&webhook.Url,
+		&webhook.Secret,
+		pq.Array(&webhook.Events),
+		&webhook.IsActive,
+		&webhook.CreatedAt,
+		&webhook.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// scanWebhookDelivery scans the webhook_deliveries columns into WebhookDelivery followed by the extra columns
+func scanWebhookDelivery(row scanner, delivery *models.WebhookDelivery, extra ...interface{}) error {
+	dest := []interface{}{
+		&delivery.ID,
+		&delivery.WebhookID,
+		&delivery.Event,
+		&delivery.Payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.MaxAttempts,
+		&delivery.ResponseStatus,
+		&delivery.ResponseBody,
+		&delivery.LastError,
+		&delivery.NextAttemptAt,
+		&delivery.CreatedAt,
+		&delivery.DeliveredAt,
+	}
+	return row.Scan(append(dest, extra...)...)
+}
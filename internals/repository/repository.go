@@ -1,8 +1,13 @@
 package repository
 
-import "github.com/ishanshre/Book-Review-Platform/internals/models"
+import (
+	"time"
 
-// DatabaseRepo consist of all the method available to us to use for database operations
+	"github.com/ishanshre/Book-Review-Platform/internals/models"
+)
+
+// DatabaseRepo consist of all the method available to us to use for database operations.
+// Methods accepting mails store them in the email outbox within the same transaction as the change.
 type DatabaseRepo interface {
 	// User/admin interfaces
 	AllUsers(limit, offset int) ([]*models.User, error)
@@ -18,7 +23,7 @@ type DatabaseRepo interface {
 
 	UpdateLastLogin(id int) error
 	Authenticate(username, testPassword string) (int, int, bool, error)
-	InsertUser(*models.User) error
+	InsertUser(u *models.User, mails ...*models.MailData) error
 	AdminInsertUser(*models.User) error
 
 	GetProfilePersonal(id int) (*models.User, error)
@@ -26,16 +31,26 @@ type DatabaseRepo interface {
 	UsernameExists(username string) (bool, error)
 	EmailExists(email string) (bool, error)
 
-	ChangePassword(password, email string) error
+	ChangePassword(password, email string, mails ...*models.MailData) error
 	UserListFilter(limit, page int, searchKey, sort string) (*models.AdminUserListApi, error)
 	TotalUserCount() int
 
+	// User sessions
+	SaveUserSession(u *models.UserSession) error
+	TouchUserSession(u *models.UserSession) (bool, error)
+	DeleteExpiredUserSessions(before time.Time) error
+	GetUserSessionsByUserID(user_id int) ([]*models.UserSession, error)
+	GetUserSessionByID(id int) (*models.UserSession, error)
+	DeleteUserSession(id int) error
+	DeleteUserSessionByToken(token string) error
+	DeleteAllUserSessions(user_id int) error
+
 	// User Kyc
 	GetKycByUserID(user_id int) (*models.Kyc, error)
 	GetUserWithKyc(id int) (*models.UserKycData, error)
 	UpdateDocument(front_path, back_path string, id int) error
-	AdminKycUpdate(update *models.Kyc) error
-	PublicKycUpdate(update *models.Kyc) error
+	AdminKycUpdate(update *models.Kyc, mails ...*models.MailData) error
+	PublicKycUpdate(update *models.Kyc, mails ...*models.MailData) error
 
 	// Genre interface
 	AllGenre() ([]*models.Genre, error)
@@ -135,7 +150,9 @@ type DatabaseRepo interface {
 	DeleteReadList(user_id, book_id int) error
 	UpdateReadList(u *models.ReadList, book_id, user_id int) error
 	ReadListCount(user_id int) (int, error)
-	GetAllBooksFromReadListByUserId(limit, page, user_id int, searchKey, sort string) (*models.BookApiFilter, error)
+	UpdateReadingProgress(u *models.ReadList, reads ...*models.ReadListRead) error
+	ReadListReadCount(user_id, book_id int) (int, error)
+	GetAllBooksFromReadListByUserId(limit, page, user_id int, searchKey, sort, status string) (*models.BookApiFilter, error)
 	ReadListFilter(limit, page int, searchKey, sort string) (*models.ReadListFilterApi, error)
 
 	// BuyList interface
@@ -167,23 +184,184 @@ type DatabaseRepo interface {
 	GetReviewByID(id int) (*models.Review, error)
 	GetReviewByUserID(id int) (*models.Review, error)
 	DeleteReview(id int) error
-	UpdateReview(u *models.Review) error
+	UpdateReview(u *models.Review, revisions ...*models.ReviewRevision) error
 	GetReviewsByBookID(bookID int) ([]*models.Review, error)
-	UpdateReviewBook(update *models.Review) error
+	UpdateReviewBook(update *models.Review, revisions ...*models.ReviewRevision) error
 	ReviewFilter(limit, page int, searchKey, sort string) (*models.ReviewFilterApi, error)
 	TotalReviewsCount() (int, error)
+	GetBookRating(book_id int) (*models.BookRating, error)
+	SetReviewActive(id int, is_active bool) error
+	PublishReview(id int) (bool, error)
+
+	// Review revision interface
+	GetReviewRevisions(review_id int) ([]*models.ReviewRevision, error)
+	GetReviewEditTimesByBookID(book_id int) (map[int]time.Time, error)
+
+	// Review vote interface
+	UpsertReviewVote(v *models.ReviewVote) error
+	DeleteReviewVote(review_id, user_id int) error
+	GetReviewVoteCount(review_id, user_id int) (*models.ReviewVoteCount, error)
+	GetReviewVoteCountsByBookID(book_id, user_id int) (map[int]*models.ReviewVoteCount, error)
+
+	// Review comment interface
+	InsertReviewComment(c *models.ReviewComment) error
+	GetReviewCommentByID(id int) (*models.ReviewComment, error)
+	GetReviewCommentsByReviewID(review_id int) ([]*models.ReviewComment, error)
+	UpdateReviewCommentBody(c *models.ReviewComment) error
+	MarkReviewCommentDeleted(id int) error
+	FlagReviewComment(id int) error
+	ModerateReviewComment(id int, is_active bool) error
+	SetReviewCommentActive(id int, is_active bool) error
+	DeleteReviewComment(id int) error
+	ReviewCommentFilter(limit, page int, searchKey, status, sort string) (*models.ReviewCommentFilterApi, error)
+
+	// Report interface
+	ReportExists(target_type string, target_id, reporter_id int) (bool, error)
+	InsertReport(r *models.Report) error
+	CountOpenReports(target_type string, target_id int) (int, error)
+	MarkReportsHidTarget(target_type string, target_id int) error
+	OpenReportsHidTarget(target_type string, target_id int) (bool, error)
+	GetReportByID(id int) (*models.Report, error)
+	ResolveReports(target_type string, target_id int, resolution, note string, resolved_by int) error
+	ReportFilter(limit, page int, searchKey, status, sort string) (*models.ReportFilterApi, error)
+
+	// Content filter interface
+	AllFilterWords() ([]*models.FilterWord, error)
+	FilterWordExists(word string) (bool, error)
+	InsertFilterWord(w *models.FilterWord) error
+	DeleteFilterWord(id int) error
+	InsertContentFilterLog(l *models.ContentFilterLog) error
+	RecentContentFilterContents(kind string, exclude_user_id int, since time.Time, limit int) ([]string, error)
+	ContentFilterLogFilter(limit, page int, searchKey, verdict, sort string) (*models.ContentFilterLogFilterApi, error)
 
 	// Contact interface
 	AllContacts() ([]*models.Contact, error)
 	GetContactByID(id int) (*models.Contact, error)
 	DeleteContact(id int) error
-	InsertContact(*models.Contact) error
+	InsertContact(u *models.Contact, mails ...*models.MailData) error
+	SetContactActive(id int, is_active bool) error
 
 	// request_books interface
-	InsertRequestedBook(i *models.RequestedBook) error
+	InsertRequestedBook(i *models.RequestedBook, mails ...*models.MailData) error
 	AllRequestBooks() ([]*models.RequestedBook, error)
 	DeleteRequestBooks(id int) error
 	GetRequestBookById(id int) (*models.RequestedBook, error)
 	RequestedBooksListFilter(limit, page int, searchKey, sort string) (*models.RequestedBookFilterApi, error)
-	UpdateBookRequestStatus(request_id int) error
+	UpdateBookRequestStatus(request_id int, mails ...*models.MailData) error
+
+	// Email outbox interface
+	EnqueueMail(msg *models.MailData) error
+	ClaimPendingEmails(limit int, lease time.Duration) ([]*models.EmailOutbox, error)
+	MarkEmailSent(id int) error
+	MarkEmailAttemptFailed(id int, lastError string, nextAttemptAt time.Time) error
+	ResendEmail(id int) error
+	GetEmailOutboxByID(id int) (*models.EmailOutbox, error)
+	EmailOutboxFilter(limit, page int, searchKey, status, sort string) (*models.EmailOutboxFilterApi, error)
+
+	// Notification interface
+	InsertNotification(n *models.Notification) error
+	InsertNotificationForAuthorFollowers(author_id int, n *models.Notification) error
+	GetNotificationsByUserID(user_id, limit, page int, unreadOnly bool) (*models.NotificationApi, error)
+	MarkNotificationRead(id, user_id int) error
+	MarkAllNotificationsRead(user_id int) error
+
+	// Webhook interface
+	AllWebhooks() ([]*models.Webhook, error)
+	GetWebhookByID(id int) (*models.Webhook, error)
+	InsertWebhook(u *models.Webhook) error
+	UpdateWebhook(u *models.Webhook) error
+	DeleteWebhook(id int) error
+	EnqueueWebhookEvent(event string, payload []byte) error
+	EnqueueWebhookDelivery(webhook_id int, event string, payload []byte) error
+	ClaimPendingWebhookDeliveries(limit int, lease time.Duration) ([]*models.WebhookDelivery, error)
+	MarkWebhookDelivered(id, responseStatus int, responseBody string) error
+	MarkWebhookDeliveryFailed(id, responseStatus int, responseBody, lastError string, nextAttemptAt time.Time) error
+	RedeliverWebhookDelivery(webhook_id, id int) error
+	WebhookDeliveryFilter(webhook_id, limit, page int, searchKey, status, sort string) (*models.WebhookDeliveryFilterApi, error)
+
+	// Digest interface
+	GetDigestPreference(user_id int) (*models.DigestPreference, error)
+	UpdateDigestFrequency(user_id int, frequency string) error
+	UnsubscribeDigest(token string) (bool, error)
+	DigestUnsubscribeTokenExists(token string) (bool, error)
+	DueDigestRecipients() ([]*models.DigestRecipient, error)
+	MarkDigestSent(user_id int, sent_at time.Time) error
+	GetDigest(user_id int, since time.Time) (*models.Digest, error)
+
+	// Shelf interface
+	InsertShelf(shelf *models.Shelf) error
+	UpdateShelf(shelf *models.Shelf) error
+	DeleteShelf(id, user_id int) error
+	ShelfNameExists(user_id int, name string, exclude_id int) (bool, error)
+	GetShelfByID(id int) (*models.Shelf, error)
+	GetShelfByToken(token string) (*models.Shelf, error)
+	GetShelvesByUserID(user_id, book_id int) ([]*models.Shelf, error)
+	AddBookToShelf(shelf_id, book_id int) error
+	RemoveBookFromShelf(shelf_id, book_id int) error
+	MoveShelfBook(shelf_id, book_id int, up bool) error
+	GetShelfBooks(shelf_id, limit, page int, searchKey, sort string) (*models.BookApiFilter, error)
+
+	// BookSimilarity interface
+	BookInteractions() ([]*models.BookInteraction, error)
+	ReplaceBookSimilarities(similarities []*models.BookSimilarity) error
+	GetSimilarBooks(book_id, limit int) ([]*models.Book, error)
+	GetRecommendations(user_id, limit int) ([]*models.RecommendedBook, error)
+	PopularBooks(user_id, limit int) ([]*models.RecommendedBook, error)
+
+	// BookContentSimilarity interface
+	BookFeatures() ([]*models.BookFeatures, error)
+	ReplaceBookContentSimilarities(book_id int, similarities []*models.BookSimilarity) error
+	ReplaceAllBookContentSimilarities(similarities []*models.BookSimilarity) error
+	GetContentSimilarBooks(book_id, limit int) ([]*models.RecommendedBook, error)
+
+	// HomeFeed interface
+	FeedAuthorBooks(user_id int, author_ids []int, limit int) ([]*models.FeedBook, error)
+	FavouriteGenres(user_id, limit int) ([]*models.Genre, error)
+	FeedGenreBooks(user_id int, genre_ids []int, limit int) ([]*models.FeedBook, error)
+	FeedShelfReviews(user_id, limit int) ([]*models.FeedReview, error)
+	ShelfOwnerIDs(book_id int) ([]int, error)
+
+	// UserFollow interface
+	GetUserByUsername(username string) (*models.User, error)
+	FollowUser(follower_id, followee_id int) (bool, error)
+	UnfollowUser(follower_id, followee_id int) error
+	UserFollowExists(follower_id, followee_id int) (bool, error)
+	UserFollowCounts(user_id int) (followers, followings int, err error)
+	GetPrivacySettings(user_id int) (*models.PrivacySettings, error)
+	UpdatePrivacySettings(settings *models.PrivacySettings) error
+
+	// Activity interface
+	InsertActivity(activity *models.Activity) error
+	GetFollowedActivities(user_id, limit, page int) (*models.ActivityApi, error)
+	GetUserActivities(user_id, limit, page int, owner bool) (*models.ActivityApi, error)
+
+	// Series interface
+	AllSeries() ([]*models.Series, error)
+	InsertSeries(s *models.Series) error
+	UpdateSeries(s *models.Series) error
+	DeleteSeries(id int) error
+	GetSeriesByID(id int) (*models.Series, error)
+	SeriesExists(name string, id int) (bool, error)
+	GetSeriesBooks(series_id int, all bool) ([]*models.SeriesBook, error)
+	InsertSeriesBook(book *models.SeriesBook) error
+	UpdateSeriesBookPosition(book *models.SeriesBook) error
+	DeleteSeriesBook(series_id, book_id int) error
+	SeriesBookExists(series_id, book_id int) (bool, error)
+	SeriesPositionTaken(series_id, book_id int, position float64) (bool, error)
+	GetBookSeries(book_id int) ([]*models.BookSeries, error)
+
+	// UserProfile interface
+	GetUserReviews(user_id, limit int) ([]*models.FeedReview, error)
+	GetPublicShelvesByUserID(user_id int) ([]*models.Shelf, error)
+	GetReadingStats(user_id, genres int) (*models.ReadingStats, error)
+
+	// ReadingGoal interface
+	GetReadingGoal(user_id, year int) (*models.ReadingGoal, error)
+	UpsertReadingGoal(goal *models.ReadingGoal) error
+	DeleteReadingGoal(user_id, year int) error
+	ReadingChallengeLeaderboard(year, limit, page int) (*models.ReadingChallengeApi, error)
+	ReadingGoalReminderRecipients(year int, since time.Time) ([]*models.ReadingGoalRecipient, error)
+	MarkReadingGoalReminded(id int, reminded_at time.Time) error
+	UnsubscribeReadingGoalReminders(token string) (bool, error)
+	ReadingGoalUnsubscribeTokenExists(token string) (bool, error)
 }
@@ -27,8 +27,9 @@ func Router(app *config.AppConfig) http.Handler {
 		AllowCredentials: true,
 		MaxAge:           300,
 	})))
-	mux.Use(middleware.SessionLoad) // load the session middleware
-	mux.Use(middleware.NoSurf)      // csrf middleware
+	mux.Use(middleware.SessionLoad)    // load the session middleware
+	mux.Use(handler.Repo.TrackSession) // refresh the session index
+	mux.Use(middleware.NoSurf)         // csrf middleware
 	mux.Use(chi_middlewares.Logger)
 
 	// Get route for Home page
@@ -44,6 +45,8 @@ func Router(app *config.AppConfig) http.Handler {
 	mux.Route("/books", func(mux chi.Router) {
 		mux.Get("/", handler.Repo.AllBooks)
 		mux.Get("/{isbn}", handler.Repo.BookDetailByISBN)
+		mux.Get("/{isbn}/events", handler.Repo.BookEvents)
+		mux.Get("/{isbn}/reviews/{review_id}/comments", handler.Repo.ReviewCommentsApi)
 		mux.Route("/", func(mux chi.Router) {
 			mux.Use(middleware.Auth)
 			mux.Use(middleware.KycValidated)
@@ -52,6 +55,10 @@ func Router(app *config.AppConfig) http.Handler {
 			mux.Post("/{isbn}/reviews/{review_id}/delete", handler.Repo.PostPublicDeleteReview)
 			mux.Get("/{isbn}/reviews/{review_id}/update", handler.Repo.PublicUpdateReview)
 			mux.Post("/{isbn}/reviews/{review_id}/update", handler.Repo.PostPublicUpdateReview)
+			mux.Post("/{isbn}/reviews/{review_id}/comments", handler.Repo.PostReviewCommentApi)
+			mux.Put("/{isbn}/reviews/{review_id}/comments/{comment_id}", handler.Repo.UpdateReviewCommentApi)
+			mux.Delete("/{isbn}/reviews/{review_id}/comments/{comment_id}", handler.Repo.DeleteReviewCommentApi)
+			mux.Post("/{isbn}/reviews/{review_id}/comments/{comment_id}/report", handler.Repo.ReportReviewCommentApi)
 		})
 	})
 
@@ -60,6 +67,7 @@ func Router(app *config.AppConfig) http.Handler {
 	// Api for clearing the messages
 	mux.Post("/api/clear/{type}", handler.Repo.ClearSessionMessage)
 	mux.Get("/api/books", handler.Repo.AllBooksFilterApi)
+	mux.Get("/api/books/{id}/similar", handler.Repo.SimilarBooksApi)
 	mux.Get("/api/populateData", handler.Repo.PopulateFakeData)
 	mux.Get("/api/authors", handler.Repo.AuthorFiltersApi)
 	mux.Get("/api/genres", handler.Repo.AllBooksFilterByGenreApi)
@@ -76,14 +84,33 @@ func Router(app *config.AppConfig) http.Handler {
 		mux.Get("/api/books/{id}/read", handler.Repo.BookReadListExistsApi)
 		mux.Post("/api/books/{id}/read", handler.Repo.AddtoReadListApi)
 		mux.Delete("/api/books/{id}/read", handler.Repo.RemoveFromReadListApi)
+		mux.Get("/api/books/{id}/reading", handler.Repo.ReadingProgressApi)
+		mux.Post("/api/books/{id}/reading/status", handler.Repo.PostReadingStatusApi)
+		mux.Post("/api/books/{id}/reading/progress", handler.Repo.PostReadingProgressApi)
 		mux.Get("/api/books/{id}/buy", handler.Repo.BookBuyListExistsApi)
 		mux.Post("/api/books/{id}/buy", handler.Repo.AddtoBuyListApi)
 		mux.Delete("/api/books/{id}/buy", handler.Repo.RemoveFromBuyListApi)
+		mux.Get("/api/shelves", handler.Repo.ShelvesApi)
+		mux.Get("/api/recommendations", handler.Repo.RecommendationsApi)
+		mux.Get("/api/users/{username}/follow", handler.Repo.UserFollowStatusApi)
+		mux.Post("/api/users/{username}/follow", handler.Repo.FollowUserApi)
+		mux.Delete("/api/users/{username}/follow", handler.Repo.UnfollowUserApi)
+		mux.Get("/activity", handler.Repo.ActivityFeed)
+		mux.Get("/api/activity", handler.Repo.ActivityFeedApi)
+		mux.Post("/api/books/{id}/shelves/{shelf_id}", handler.Repo.AddBookToShelfApi)
+		mux.Delete("/api/books/{id}/shelves/{shelf_id}", handler.Repo.RemoveBookFromShelfApi)
 		mux.Get("/user/logout", handler.Repo.Logout)
 		mux.Get("/read-list", handler.Repo.AllBooksFilterFromReadList)
 		mux.Get("/api/read-list", handler.Repo.AllBooksFilterFromReadListApi)
 		mux.Get("/buy-list", handler.Repo.AllBooksFilterFromBuyList)
 		mux.Get("/api/buy-list", handler.Repo.AllBooksFilterFromBuyListApi)
+		mux.Get("/api/notifications", handler.Repo.NotificationsApi)
+		mux.Post("/api/notifications/read-all", handler.Repo.MarkAllNotificationsReadApi)
+		mux.Post("/api/notifications/{id}/read", handler.Repo.MarkNotificationReadApi)
+		mux.Post("/api/reviews/preview", handler.Repo.ReviewPreviewApi)
+		mux.Post("/api/reviews/{id}/vote/{vote}", handler.Repo.ReviewVoteApi)
+		mux.Delete("/api/reviews/{id}/vote", handler.Repo.RemoveReviewVoteApi)
+		mux.Post("/api/reviews/{id}/report", handler.Repo.ReportReviewApi)
 	})
 
 	mux.Group(func(mux chi.Router) {
@@ -126,6 +153,27 @@ func Router(app *config.AppConfig) http.Handler {
 	fileServerMedia := http.FileServer(http.Dir("./media/"))
 	mux.Handle("/media/*", http.StripPrefix("/media", fileServerMedia))
 
+	// Digest unsubscribe router
+	mux.Get("/digest/unsubscribe/{token}", handler.Repo.DigestUnsubscribe)
+	mux.Post("/digest/unsubscribe/{token}", handler.Repo.PostDigestUnsubscribe)
+
+	// Shelf router
+	mux.Get("/shelves/{token}", handler.Repo.ShelfDetail)
+	mux.Get("/api/shelves/{token}/books", handler.Repo.ShelfBooksApi)
+
+	// Series router
+	mux.Get("/series/{id}", handler.Repo.SeriesDetail)
+
+	// User profile router
+	mux.Get("/users/{username}", handler.Repo.UserProfile)
+	mux.Get("/api/users/{username}/activity", handler.Repo.UserActivityApi)
+
+	// Reading challenge router
+	mux.Get("/challenge", handler.Repo.ReadingChallenge)
+	mux.Get("/api/challenge", handler.Repo.ReadingChallengeApi)
+	mux.Get("/reading-goal/unsubscribe/{token}", handler.Repo.ReadingGoalUnsubscribe)
+	mux.Post("/reading-goal/unsubscribe/{token}", handler.Repo.PostReadingGoalUnsubscribe)
+
 	// Contact Us router
 	mux.Get("/contact-us", handler.Repo.ContactUs)
 	mux.Post("/contact-us", handler.Repo.PostContactUs)
@@ -136,6 +184,17 @@ func Router(app *config.AppConfig) http.Handler {
 		mux.Get("/followings", handler.Repo.GetFollowingsListByUserIdApi)
 		mux.Post("/kyc", handler.Repo.PublicUpdateKYC)
 		mux.Post("/pic", handler.Repo.PostUserProfilePicUpdate)
+		mux.Post("/sessions/{id}/revoke", handler.Repo.PostRevokeUserSession)
+		mux.Post("/sessions/revoke-all", handler.Repo.PostRevokeAllUserSessions)
+		mux.Post("/digest", handler.Repo.PostDigestPreference)
+		mux.Post("/privacy", handler.Repo.PostPrivacySettings)
+		mux.Post("/reading-goal", handler.Repo.PostReadingGoal)
+		mux.Post("/reading-goal/delete", handler.Repo.PostDeleteReadingGoal)
+		mux.Get("/shelves", handler.Repo.Shelves)
+		mux.Post("/shelves/create", handler.Repo.PostCreateShelf)
+		mux.Post("/shelves/{id}/update", handler.Repo.PostUpdateShelf)
+		mux.Post("/shelves/{id}/delete", handler.Repo.PostDeleteShelf)
+		mux.Post("/shelves/{id}/books/{book_id}/move", handler.Repo.PostMoveShelfBook)
 	})
 
 	mux.Group(func(mux chi.Router) {
@@ -150,7 +209,19 @@ func Router(app *config.AppConfig) http.Handler {
 		mux.Get("/api/admin-buylists", handler.Repo.AdminAllBuyListApi)
 		mux.Get("/api/admin-followers", handler.Repo.AdminAllFollowerApi)
 		mux.Get("/api/admin-reviews", handler.Repo.AdminAllReviewApi)
+		mux.Get("/api/admin-review-comments", handler.Repo.AdminAllReviewCommentsApi)
+		mux.Get("/api/admin-content-filter-logs", handler.Repo.AdminContentFilterLogsApi)
 		mux.Get("/api/admin-requestedbooks", handler.Repo.AdminAllRequestedBookssApi)
+		mux.Get("/api/admin-emails", handler.Repo.AdminAllEmailOutboxApi)
+		mux.Get("/api/admin-webhook-deliveries", handler.Repo.AdminWebhookDeliveriesApi)
+	})
+
+	mux.Route("/moderation", func(mux chi.Router) {
+		mux.Use(middleware.Auth)
+		mux.Use(middleware.Moderator)
+		mux.Get("/reports", handler.Repo.ModerationReports)
+		mux.Get("/api/reports", handler.Repo.ModerationReportsApi)
+		mux.Post("/reports/{id}/resolve", handler.Repo.PostModerationResolveReport)
 	})
 
 	mux.Route("/admin", func(mux chi.Router) {
@@ -163,6 +234,7 @@ func Router(app *config.AppConfig) http.Handler {
 		mux.Post("/users/detail/{id}/profile", handler.Repo.PostAdminUserProfileUpdate)
 		mux.Post("/users/detail/{id}/document", handler.Repo.PostAdminUserDocumentUpdate)
 		mux.Post("/users/detail/{id}/kyc", handler.Repo.PostAdminKycUpdate)
+		mux.Post("/users/detail/{id}/sessions/revoke", handler.Repo.PostAdminRevokeUserSessions)
 
 		mux.Get("/users/create", handler.Repo.AdminUserAdd)
 		mux.Post("/users/create", handler.Repo.PostAdminUserAdd)
@@ -176,6 +248,16 @@ func Router(app *config.AppConfig) http.Handler {
 		mux.Post("/genres/detail/{id}", handler.Repo.PostAdminGetGenreByID)
 		mux.Post("/genres/detail/{id}/delete", handler.Repo.AdminDeleteGenre)
 
+		// admin series router
+		mux.Get("/series", handler.Repo.AdminAllSeries)
+		mux.Post("/series", handler.Repo.PostAdminAddSeries)
+		mux.Get("/series/detail/{id}", handler.Repo.AdminGetSeriesByID)
+		mux.Post("/series/detail/{id}", handler.Repo.PostAdminGetSeriesByID)
+		mux.Post("/series/detail/{id}/delete", handler.Repo.AdminDeleteSeries)
+		mux.Post("/series/detail/{id}/books", handler.Repo.PostAdminAddSeriesBook)
+		mux.Post("/series/detail/{id}/books/{book_id}", handler.Repo.PostAdminUpdateSeriesBook)
+		mux.Post("/series/detail/{id}/books/{book_id}/delete", handler.Repo.PostAdminDeleteSeriesBook)
+
 		// admin publisher router
 		mux.Get("/publishers", handler.Repo.AdminAllPublusher)
 		mux.Get("/publishers/detail/{id}", handler.Repo.AdminGetPublisherDetailByID)
@@ -255,16 +337,42 @@ func Router(app *config.AppConfig) http.Handler {
 		mux.Get("/reviews/detail/{review_id}", handler.Repo.AdminGetReviewByID)
 		mux.Post("/reviews/detail/{review_id}/delete", handler.Repo.PostAdminDeleteReview)
 		mux.Post("/reviews/detail/{review_id}/update", handler.Repo.PostAdminUpdateReview)
+		mux.Get("/reviews/detail/{review_id}/revisions", handler.Repo.AdminReviewRevisions)
+
+		// Review comment router
+		mux.Get("/review-comments", handler.Repo.AdminAllReviewComments)
+		mux.Post("/review-comments/detail/{id}/moderate", handler.Repo.PostAdminModerateReviewComment)
+		mux.Post("/review-comments/detail/{id}/delete", handler.Repo.PostAdminDeleteReviewComment)
 
 		// Contact router
 		mux.Get("/contacts", handler.Repo.AdminAllContacts)
 		mux.Post("/contacts/detail/{contact_id}/delete", handler.Repo.PostAdminDeleteContact)
 		mux.Get("/contacts/detail/{contact_id}", handler.Repo.AdminGetContactByID)
+		mux.Post("/contacts/detail/{contact_id}/approve", handler.Repo.PostAdminApproveContact)
+
+		// Content filter router
+		mux.Get("/content-filter", handler.Repo.AdminContentFilterWords)
+		mux.Post("/content-filter/words", handler.Repo.PostAdminAddFilterWord)
+		mux.Post("/content-filter/words/{id}/delete", handler.Repo.PostAdminDeleteFilterWord)
+		mux.Get("/content-filter/logs", handler.Repo.AdminContentFilterLogs)
 
 		// Request Book handler
 		mux.Get("/request-books", handler.Repo.AdminAllRequestBookList)
 		mux.Post("/request-books/detail/{id}/delete", handler.Repo.AdminDeleteRequestedBook)
 		mux.Post("/{user_id}/request-books/detail/{request_id}/update", handler.Repo.PostAdminUpdateRequestBookStatus)
+
+		// Email outbox router
+		mux.Get("/emails", handler.Repo.AdminAllEmailOutbox)
+		mux.Post("/emails/detail/{id}/resend", handler.Repo.PostAdminResendEmail)
+
+		// Webhook router
+		mux.Get("/webhooks", handler.Repo.AdminAllWebhooks)
+		mux.Post("/webhooks", handler.Repo.PostAdminAddWebhook)
+		mux.Get("/webhooks/detail/{id}", handler.Repo.AdminGetWebhookByID)
+		mux.Post("/webhooks/detail/{id}", handler.Repo.PostAdminUpdateWebhook)
+		mux.Post("/webhooks/detail/{id}/delete", handler.Repo.PostAdminDeleteWebhook)
+		mux.Post("/webhooks/detail/{id}/test", handler.Repo.PostAdminTestWebhook)
+		mux.Post("/webhooks/detail/{id}/deliveries/{delivery_id}/redeliver", handler.Repo.PostAdminRedeliverWebhook)
 	})
 	return mux
 }
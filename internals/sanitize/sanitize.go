@@ -0,0 +1,230 @@
+// Package sanitize cleans the user generated HTML with an allowlist of tags and attributes.
+// Disallowed tags are removed and their text is kept, except for script and style whose content is dropped.
+package sanitize
+
+import (
+	"html"
+	"strings"
+
+	"github.com/ishanshre/Book-Review-Platform/internals/markdown"
+)
+
+// allowedTags lists the tags kept by the sanitizer with their allowed attributes.
+// The attribute values are checked by allowedAttr.
+var allowedTags = map[string][]string{
+	"p":          nil,
+	"br":         nil,
+	"em":         nil,
+	"strong":     nil,
+	"code":       nil,
+	"ul":         nil,
+	"ol":         nil,
+	"li":         nil,
+	"blockquote": nil,
+	"a":          {"href"},
+	"span":       {"class"},
+}
+
+// voidTags are the allowed tags without a closing tag
+var voidTags = map[string]bool{"br": true}
+
+// droppedTags are removed along with their content
+var droppedTags = map[string]bool{"script": true, "style": true}
+
+// HTML returns the HTML with only the allowed tags and attributes.
+// Links get rel="nofollow noopener" and the unclosed tags are closed at the end.
+func HTML(src string) string {
+	var b strings.Builder
+	open := []string{}
+	for len(src) > 0 {
+		start := strings.IndexByte(src, '<')
+		if start < 0 {
+			b.WriteString(text(src))
+			break
+		}
+		b.WriteString(text(src[:start]))
+		src = src[start:]
+
+		// comments are dropped
+		if strings.HasPrefix(src, "<!--") {
+			end := strings.Index(src, "-->")
+			if end < 0 {
+				break
+			}
+			src = src[end+3:]
+			continue
+		}
+
+		end := strings.IndexByte(src, '>')
+		if end < 0 {
+			b.WriteString(text(src))
+			break
+		}
+		name, attrs, closing, ok := parseTag(src[1:end])
+		if !ok {
+			// not a tag, so the bracket is shown as text
+			b.WriteString("&lt;")
+			src = src[1:]
+			continue
+		}
+		src = src[end+1:]
+
+		if droppedTags[name] && !closing {
+			closeTag := "</" + name
+			if i := indexFold(src, closeTag); i >= 0 {
+				src = src[i:]
+				if j := strings.IndexByte(src, '>'); j >= 0 {
+					src = src[j+1:]
+				} else {
+					src = ""
+				}
+			} else {
+				src = ""
+			}
+			continue
+		}
+
+		allowed, ok := allowedTags[name]
+		if !ok {
+			continue
+		}
+		if closing {
+			// close the tags opened after the matching open tag, ignore the stray closing tags
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] != name {
+					continue
+				}
+				for j := len(open) - 1; j >= i; j-- {
+					b.WriteString("</" + open[j] + ">")
+				}
+				open = open[:i]
+				break
+			}
+			continue
+		}
+		b.WriteString("<" + name)
+		for _, attr := range allowed {
+			value, ok := attrs[attr]
+			if !ok || !allowedAttr(name, attr, value) {
+				continue
+			}
+			b.WriteString(" " + attr + `="` + html.EscapeString(value) + `"`)
+		}
+		if name == "a" {
+			b.WriteString(` rel="nofollow noopener"`)
+		}
+		b.WriteString(">")
+		if !voidTags[name] {
+			open = append(open, name)
+		}
+	}
+	for i := len(open) - 1; i >= 0; i-- {
+		b.WriteString("</" + open[i] + ">")
+	}
+	return b.String()
+}
+
+// allowedAttr checks the value of the attribute of the tag.
+// Links must be http, https or mailto and spans may only be spoilers.
+func allowedAttr(tag, attr, value string) bool {
+	switch {
+	case tag == "a" && attr == "href":
+		return markdown.AllowedURL(value)
+	case tag == "span" && attr == "class":
+		return value == "spoiler"
+	}
+	return false
+}
+
+// text escapes the text between the tags.
+// It is unescaped first so that the entities of the source are not escaped twice.
+func text(s string) string {
+	return html.EscapeString(html.UnescapeString(s))
+}
+
+// parseTag parses the content between the brackets of a tag into its lower cased name and attributes.
+// ok is false if the content is not a tag like in "a < b".
+func parseTag(s string) (name string, attrs map[string]string, closing, ok bool) {
+	if strings.HasPrefix(s, "/") {
+		closing = true
+		s = s[1:]
+	}
+	s = strings.TrimSuffix(s, "/")
+	i := 0
+	for i < len(s) && isNameByte(s[i]) {
+		i++
+	}
+	if i == 0 {
+		return "", nil, false, false
+	}
+	name = strings.ToLower(s[:i])
+	attrs = make(map[string]string)
+	s = s[i:]
+	for {
+		s = strings.TrimLeft(s, " \t\n\r/")
+		if s == "" {
+			break
+		}
+		j := 0
+		for j < len(s) && !strings.ContainsRune(" \t\n\r=/", rune(s[j])) {
+			j++
+		}
+		if j == 0 {
+			// skip the stray equal sign so that the loop moves on
+			s = s[1:]
+			continue
+		}
+		attr := strings.ToLower(s[:j])
+		s = strings.TrimLeft(s[j:], " \t\n\r")
+		value := ""
+		if strings.HasPrefix(s, "=") {
+			s = strings.TrimLeft(s[1:], " \t\n\r")
+			if s != "" && (s[0] == '"' || s[0] == '\'') {
+				quote := s[0]
+				end := strings.IndexByte(s[1:], quote)
+				if end < 0 {
+					end = len(s) - 1
+				}
+				value = s[1 : end+1]
+				s = s[min(end+2, len(s)):]
+			} else {
+				k := 0
+				for k < len(s) && !strings.ContainsRune(" \t\n\r", rune(s[k])) {
+					k++
+				}
+				value = s[:k]
+				s = s[k:]
+			}
+		}
+		attrs[attr] = html.UnescapeString(value)
+	}
+	return name, attrs, closing, true
+}
+
+// indexFold returns the index of the first case insensitive match of the ascii substr in s, or -1
+func indexFold(s, substr string) int {
+	for i := 0; i+len(substr) <= len(s); i++ {
+		if strings.EqualFold(s[i:i+len(substr)], substr) {
+			return i
+		}
+	}
+	return -1
+}
+
+// isNameByte returns true if the byte can be part of a tag name
+func isNameByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// min returns the smaller of the two numbers
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// Markdown renders the Markdown text and sanitizes the resulting HTML
+func Markdown(src string) string {
+	return HTML(markdown.Render(src))
+}
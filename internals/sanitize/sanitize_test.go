@@ -0,0 +1,94 @@
+package sanitize
+
+import "testing"
+
+func TestHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		// allowed markup
+		{"allowed tags", `<p>Hello <strong>world</strong></p>`, `<p>Hello <strong>world</strong></p>`},
+		{"void tag", `line<br/>break`, `line<br>break`},
+		{"http link", `<a href="https://example.com">x</a>`, `<a href="https://example.com" rel="nofollow noopener">x</a>`},
+		{"mailto link", `<a href="mailto:me@example.com">m</a>`, `<a href="mailto:me@example.com" rel="nofollow noopener">m</a>`},
+		{"spoiler", `<span class="spoiler">s</span>`, `<span class="spoiler">s</span>`},
+		{"other span class", `<span class="x">s</span>`, `<span>s</span>`},
+
+		// link schemes
+		{"javascript scheme", `<a href="javascript:alert(1)">x</a>`, `<a rel="nofollow noopener">x</a>`},
+		{"mixed case scheme", `<a href="JaVaScRiPt:alert(1)">x</a>`, `<a rel="nofollow noopener">x</a>`},
+		{"leading space scheme", `<a href=" javascript:alert(1)">x</a>`, `<a rel="nofollow noopener">x</a>`},
+		{"decimal entity scheme", `<a href="&#106;avascript:alert(1)">x</a>`, `<a rel="nofollow noopener">x</a>`},
+		{"hex entity scheme", `<a href="&#x6A;avascript:alert(1)">x</a>`, `<a rel="nofollow noopener">x</a>`},
+		{"tab entity in scheme", `<a href="java&#x09;script:alert(1)">x</a>`, `<a rel="nofollow noopener">x</a>`},
+		{"unquoted scheme", `<a href=javascript:alert(1)>x</a>`, `<a rel="nofollow noopener">x</a>`},
+		{"data scheme", `<a href="data:text/html;base64,PHNjcmlwdD4=">x</a>`, `<a rel="nofollow noopener">x</a>`},
+
+		// attributes
+		{"event handler", `<a href="https://example.com" onclick="alert(1)">x</a>`, `<a href="https://example.com" rel="nofollow noopener">x</a>`},
+		{"event handler on span", `<span class="spoiler" onmouseover="alert(1)">s</span>`, `<span class="spoiler">s</span>`},
+		{"quote breakout", `<a href='https://example.com/" onmouseover="alert(1)'>x</a>`, `<a href="https://example.com/&#34; onmouseover=&#34;alert(1)" rel="nofollow noopener">x</a>`},
+		{"encoded quote breakout", `<a href="https://example.com/?q=&quot;&gt;&lt;script&gt;">x</a>`, `<a href="https://example.com/?q=&#34;&gt;&lt;script&gt;" rel="nofollow noopener">x</a>`},
+		{"bracket in attribute", `<a href="https://example.com/x>y">x</a>`, `<a href="https://example.com/x" rel="nofollow noopener">y&#34;&gt;x</a>`},
+		{"disallowed tag with handler", `<img src=x onerror=alert(1)>`, ``},
+
+		// comments
+		{"comment", `a<!-- <b>hidden</b> -->b`, `ab`},
+		{"comment hiding script", `a<!-- <script>alert(1)</script> -->b`, `ab`},
+		{"unclosed comment", `a<!-- <script>alert(1)</script>`, `a`},
+
+		// script and style
+		{"script", `<script>alert(1)</script>after`, `after`},
+		{"upper case script", `<SCRIPT>alert(1)</SCRIPT>after`, `after`},
+		{"unclosed script", `before<script>alert(1)`, `before`},
+		{"style", `<style>body{display:none}</style>text`, `text`},
+		{"split script", `<scr<script>ipt>alert(1)</script>`, `ipt&gt;alert(1)`},
+		{"script in svg", `<svg><script>alert(1)</script></svg>`, ``},
+		{"iframe", `<iframe src="https://example.com"></iframe>x`, `x`},
+
+		// nesting
+		{"disallowed parent", `<div><p><em>deep</em></p></div>`, `<p><em>deep</em></p>`},
+		{"misnested tags", `<p><em>deep <strong>nest</em></p>`, `<p><em>deep <strong>nest</strong></em></p>`},
+		{"unclosed tag", `<p>unclosed`, `<p>unclosed</p>`},
+		{"stray closing tag", `</p>stray`, `stray`},
+
+		// text
+		{"bracket as text", `a < b > c`, `a &lt; b &gt; c`},
+		{"escaped tag", `&lt;script&gt;`, `&lt;script&gt;`},
+		{"entities are not escaped twice", `&amp;amp;`, `&amp;amp;`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HTML(tt.src); got != tt.want {
+				t.Errorf("HTML(%q)\n got: %s\nwant: %s", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"emphasis and spoiler", `**bold** and *em* and ||spoiler||`, "<p><strong>bold</strong> and <em>em</em> and <span class=\"spoiler\">spoiler</span></p>\n"},
+		{"link", `[x](https://example.com)`, "<p><a href=\"https://example.com\" rel=\"nofollow noopener\">x</a></p>\n"},
+		{"javascript link", `[x](javascript:alert(1))`, "<p>[x](javascript:alert(1))</p>\n"},
+		{"entity encoded link", `[x](&#106;avascript:alert(1))`, "<p>[x](&amp;#106;avascript:alert(1))</p>\n"},
+		{"quote breakout in link", `[x](https://example.com/"onmouseover="alert(1))`, "<p><a href=\"https://example.com/&#34;onmouseover=&#34;alert(1\" rel=\"nofollow noopener\">x</a>)</p>\n"},
+		{"raw script", `<script>alert(1)</script>`, "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>\n"},
+		{"raw comment", `<!-- hidden -->`, "<p>&lt;!-- hidden --&gt;</p>\n"},
+		{"html in code", "`<b>code</b>`", "<p><code>&lt;b&gt;code&lt;/b&gt;</code></p>\n"},
+		{"html in quote", "> quote <i>x</i>\n> - item", "<blockquote><p>quote &lt;i&gt;x&lt;/i&gt;</p>\n<ul><li>item</li></ul>\n</blockquote>\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Markdown(tt.src); got != tt.want {
+				t.Errorf("Markdown(%q)\n got: %s\nwant: %s", tt.src, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+// Package textdiff computes the word level difference between two texts
+package textdiff
+
+import (
+	"strings"
+	"unicode"
+)
+
+// kinds of the diff operations
+const (
+	Equal  = "equal"
+	Insert = "insert"
+	Delete = "delete"
+)
+
+// Op is a run of text kept, inserted or deleted by the change
+type Op struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// Words returns the operations changing the text before into the text after.
+// The texts are compared word by word and the whitespace is kept with the word before it.
+func Words(before, after string) []Op {
+	a, b := split(before), split(after)
+
+	// the common prefix and suffix are kept out of the longest common subsequence search
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := []Op{}
+	ops = appendOp(ops, Equal, a[:prefix]...)
+	ops = diff(ops, a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])
+	ops = appendOp(ops, Equal, a[len(a)-suffix:]...)
+	return ops
+}
+
+// diff appends the operations changing the words a into the words b following their longest common subsequence.
+// It splits a in half and b where the common subsequences of the halves are the longest (Hirschberg),
+// so it only keeps two rows of lengths instead of the whole table.
+func diff(ops []Op, a, b []string) []Op {
+	if len(a) == 0 {
+		return appendOp(ops, Insert, b...)
+	}
+	if len(b) == 0 {
+		return appendOp(ops, Delete, a...)
+	}
+	if len(a) == 1 {
+		for j := range b {
+			if b[j] == a[0] {
+				ops = appendOp(ops, Insert, b[:j]...)
+				ops = appendOp(ops, Equal, a[0])
+				return appendOp(ops, Insert, b[j+1:]...)
+			}
+		}
+		ops = appendOp(ops, Delete, a[0])
+		return appendOp(ops, Insert, b...)
+	}
+	mid := len(a) / 2
+	head := lcsPrefixes(a[:mid], b)
+	tail := lcsSuffixes(a[mid:], b)
+	split := 0
+	for j := range head {
+		if head[j]+tail[j] > head[split]+tail[split] {
+			split = j
+		}
+	}
+	ops = diff(ops, a[:mid], b[:split])
+	return diff(ops, a[mid:], b[split:])
+}
+
+// lcsPrefixes returns the lengths of the longest common subsequences of a and every prefix b[:j]
+func lcsPrefixes(a, b []string) []int32 {
+	prev, cur := make([]int32, len(b)+1), make([]int32, len(b)+1)
+	for i := range a {
+		for j := range b {
+			if a[i] == b[j] {
+				cur[j+1] = prev[j] + 1
+			} else if prev[j+1] >= cur[j] {
+				cur[j+1] = prev[j+1]
+			} else {
+				cur[j+1] = cur[j]
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev
+}
+
+// lcsSuffixes returns the lengths of the longest common subsequences of a and every suffix b[j:]
+func lcsSuffixes(a, b []string) []int32 {
+	prev, cur := make([]int32, len(b)+1), make([]int32, len(b)+1)
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				cur[j] = prev[j+1] + 1
+			} else if prev[j] >= cur[j+1] {
+				cur[j] = prev[j]
+			} else {
+				cur[j] = cur[j+1]
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev
+}
+
+// appendOp adds the words to the last operation when it is of the same type
+func appendOp(ops []Op, kind string, words ...string) []Op {
+	if len(words) == 0 {
+		return ops
+	}
+	text := strings.Join(words, "")
+	if len(ops) > 0 && ops[len(ops)-1].Type == kind {
+		ops[len(ops)-1].Text += text
+		return ops
+	}
+	return append(ops, Op{Type: kind, Text: text})
+}
+
+// split cuts the text into words followed by their whitespace
+func split(text string) []string {
+	words := []string{}
+	start := 0
+	space := false
+	for i, c := range text {
+		if unicode.IsSpace(c) {
+			space = true
+			continue
+		}
+		if space {
+			words = append(words, text[start:i])
+			start = i
+			space = false
+		}
+	}
+	if start < len(text) {
+		words = append(words, text[start:])
+	}
+	return words
+}
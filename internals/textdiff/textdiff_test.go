@@ -0,0 +1,44 @@
+package textdiff
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWords(t *testing.T) {
+	tests := []struct {
+		name   string
+		before string
+		after  string
+		want   []Op
+	}{
+		// empty input
+		{"both empty", "", "", []Op{}},
+		{"empty before", "", "new text", []Op{{Insert, "new text"}}},
+		{"empty after", "old text", "", []Op{{Delete, "old text"}}},
+
+		// identical input
+		{"identical", "the same text", "the same text", []Op{{Equal, "the same text"}}},
+		{"identical with newlines", "one\ntwo\n\nthree", "one\ntwo\n\nthree", []Op{{Equal, "one\ntwo\n\nthree"}}},
+
+		// insert only
+		{"insert at start", "brown fox", "quick brown fox", []Op{{Insert, "quick "}, {Equal, "brown fox"}}},
+		{"insert in middle", "the fox", "the quick brown fox", []Op{{Equal, "the "}, {Insert, "quick brown "}, {Equal, "fox"}}},
+		{"insert at end", "the quick ", "the quick fox", []Op{{Equal, "the quick "}, {Insert, "fox"}}},
+
+		// delete only
+		{"delete at start", "quick brown fox", "brown fox", []Op{{Delete, "quick "}, {Equal, "brown fox"}}},
+		{"delete in middle", "the quick brown fox", "the fox", []Op{{Equal, "the "}, {Delete, "quick brown "}, {Equal, "fox"}}},
+		{"delete at end", "the quick fox", "the quick ", []Op{{Equal, "the quick "}, {Delete, "fox"}}},
+
+		// replace
+		{"replace word", "the quick fox", "the slow fox", []Op{{Equal, "the "}, {Delete, "quick "}, {Insert, "slow "}, {Equal, "fox"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Words(tt.before, tt.after); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Words(%q, %q)\n got: %v\nwant: %v", tt.before, tt.after, got, tt.want)
+			}
+		})
+	}
+}
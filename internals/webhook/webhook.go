@@ -0,0 +1,96 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers sent with every delivery
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// maxResponseBody is the number of bytes of the response body kept in the delivery log
+const maxResponseBody = 1024
+
+// Sign returns the signature header value of the payload.
+// Format: t=<unix timestamp>,v1=<hex encoded HMAC-SHA256 of "<timestamp>.<payload>" using the secret>
+func Sign(secret string, timestamp int64, payload []byte) string {
+	return fmt.Sprintf("t=%d,v1=%s", timestamp, signature(secret, timestamp, payload))
+}
+
+// Verify reports whether the signature header matches the payload and is not older than the tolerance.
+// Receivers written in Go can use it to verify the deliveries.
+func Verify(secret, header string, payload []byte, tolerance time.Duration) bool {
+	var timestamp int64
+	var sig string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "t":
+			timestamp, _ = strconv.ParseInt(value, 10, 64)
+		case "v1":
+			sig = value
+		}
+	}
+	if timestamp == 0 || sig == "" {
+		return false
+	}
+	if tolerance > 0 && time.Since(time.Unix(timestamp, 0)) > tolerance {
+		return false
+	}
+	return hmac.Equal([]byte(sig), []byte(signature(secret, timestamp, payload)))
+}
+
+// signature returns the hex encoded HMAC-SHA256 of the timestamp and payload
+func signature(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Client posts the signed payloads to the webhook endpoints
+type Client struct {
+	HTTP *http.Client
+}
+
+// NewClient returns a client with the given request timeout
+func NewClient(timeout time.Duration) *Client {
+	return &Client{HTTP: &http.Client{Timeout: timeout}}
+}
+
+// Deliver posts the payload to the url and returns the response status and the beginning of the response body.
+// Any response other than 2xx is returned as an error.
+func (c *Client) Deliver(url, secret, event string, delivery_id int, payload []byte) (int, string, error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "BookWorm-Webhook/1.0")
+	req.Header.Set(HeaderEvent, event)
+	req.Header.Set(HeaderDelivery, strconv.Itoa(delivery_id))
+	req.Header.Set(HeaderSignature, Sign(secret, time.Now().Unix(), payload))
+
+	res, err := c.HTTP.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer res.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(res.Body, maxResponseBody))
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, strings.ToValidUTF8(string(body), ""), fmt.Errorf("endpoint responded with status %d", res.StatusCode)
+	}
+	return res.StatusCode, strings.ToValidUTF8(string(body), ""), nil
+}
@@ -0,0 +1,12 @@
+CREATE TABLE "user_sessions" (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    token VARCHAR(255) UNIQUE NOT NULL,
+    ip_address VARCHAR(45),
+    user_agent VARCHAR(500),
+    device VARCHAR(100),
+    created_at TIMESTAMPTZ,
+    last_seen TIMESTAMPTZ,
+    CONSTRAINT fk_user_sessions_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_user_sessions_user_id ON user_sessions(user_id);
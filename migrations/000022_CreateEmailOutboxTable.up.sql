@@ -0,0 +1,18 @@
+CREATE TABLE "email_outbox" (
+    id SERIAL PRIMARY KEY,
+    to_email VARCHAR(255) NOT NULL,
+    from_email VARCHAR(255) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    template VARCHAR(100) NOT NULL DEFAULT '',
+    data JSONB NOT NULL DEFAULT '{}',
+    content TEXT NOT NULL DEFAULT '',
+    plain_content TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL DEFAULT 5,
+    last_error TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    sent_at TIMESTAMPTZ
+);
+CREATE INDEX idx_email_outbox_status_next_attempt_at ON email_outbox(status, next_attempt_at);
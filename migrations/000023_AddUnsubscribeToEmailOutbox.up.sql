@@ -0,0 +1 @@
+ALTER TABLE "email_outbox" ADD COLUMN unsubscribe VARCHAR(500) NOT NULL DEFAULT '';
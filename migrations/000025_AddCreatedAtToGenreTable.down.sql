@@ -0,0 +1 @@
+ALTER TABLE "genres" DROP COLUMN created_at;
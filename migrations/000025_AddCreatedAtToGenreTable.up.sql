@@ -0,0 +1,2 @@
+ALTER TABLE "genres" ADD COLUMN created_at TIMESTAMPTZ;
+ALTER TABLE "genres" ALTER COLUMN created_at SET DEFAULT NOW();
@@ -0,0 +1,9 @@
+CREATE TABLE "digest_preferences" (
+    user_id INTEGER PRIMARY KEY,
+    frequency VARCHAR(10) NOT NULL DEFAULT 'never',
+    unsubscribe_token VARCHAR(64) UNIQUE NOT NULL,
+    last_sent_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    CONSTRAINT digest_frequency_check CHECK (frequency IN ('never', 'daily', 'weekly', 'monthly')),
+    CONSTRAINT fk_digest_preferences_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,10 @@
+CREATE TABLE "review_votes" (
+    id SERIAL PRIMARY KEY,
+    review_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    is_helpful BOOLEAN NOT NULL,
+    voted_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT uc_review_vote_user UNIQUE (review_id, user_id),
+    CONSTRAINT fk_review_vote_review FOREIGN KEY (review_id) REFERENCES reviews(id) ON DELETE CASCADE,
+    CONSTRAINT fk_review_vote_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
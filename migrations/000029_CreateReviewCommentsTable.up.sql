@@ -0,0 +1,18 @@
+CREATE TABLE "review_comments" (
+    id SERIAL PRIMARY KEY,
+    review_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    parent_id INTEGER,
+    body VARCHAR(5000) NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    is_flagged BOOLEAN NOT NULL DEFAULT false,
+    is_deleted BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_review_comment_review FOREIGN KEY (review_id) REFERENCES reviews(id) ON DELETE CASCADE,
+    CONSTRAINT fk_review_comment_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_review_comment_parent FOREIGN KEY (parent_id) REFERENCES review_comments(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_review_comments_review_id ON review_comments (review_id);
+CREATE INDEX idx_review_comments_flagged ON review_comments (is_flagged) WHERE is_flagged;
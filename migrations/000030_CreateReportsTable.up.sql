@@ -0,0 +1,26 @@
+CREATE TABLE "reports" (
+    id SERIAL PRIMARY KEY,
+    target_type VARCHAR(10) NOT NULL,
+    target_id INTEGER NOT NULL,
+    target_user_id INTEGER,
+    target_body VARCHAR(10000) NOT NULL DEFAULT '',
+    reporter_id INTEGER NOT NULL,
+    reason VARCHAR(20) NOT NULL,
+    details VARCHAR(1000) NOT NULL DEFAULT '',
+    status VARCHAR(10) NOT NULL DEFAULT 'open',
+    resolution VARCHAR(10) NOT NULL DEFAULT '',
+    resolution_note VARCHAR(1000) NOT NULL DEFAULT '',
+    resolved_by INTEGER,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    resolved_at TIMESTAMPTZ,
+    CONSTRAINT report_target_type_check CHECK (target_type IN ('review', 'comment')),
+    CONSTRAINT report_reason_check CHECK (reason IN ('spam', 'offensive', 'harassment', 'spoiler', 'off_topic', 'other')),
+    CONSTRAINT report_status_check CHECK (status IN ('open', 'resolved')),
+    CONSTRAINT report_resolution_check CHECK (resolution IN ('', 'dismissed', 'hidden', 'deleted', 'warned')),
+    CONSTRAINT uc_report_reporter UNIQUE (target_type, target_id, reporter_id),
+    CONSTRAINT fk_report_target_user FOREIGN KEY (target_user_id) REFERENCES users(id) ON DELETE SET NULL,
+    CONSTRAINT fk_report_reporter FOREIGN KEY (reporter_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_report_resolved_by FOREIGN KEY (resolved_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_reports_open_target ON reports (target_type, target_id) WHERE status = 'open';
@@ -0,0 +1,3 @@
+ALTER TABLE "contacts" DROP COLUMN is_active;
+DROP TABLE "content_filter_logs";
+DROP TABLE "filter_words";
@@ -0,0 +1,26 @@
+CREATE TABLE "filter_words" (
+    id SERIAL PRIMARY KEY,
+    word VARCHAR(100) NOT NULL UNIQUE,
+    verdict VARCHAR(10) NOT NULL DEFAULT 'hold',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT filter_word_verdict_check CHECK (verdict IN ('hold', 'reject'))
+);
+
+CREATE TABLE "content_filter_logs" (
+    id SERIAL PRIMARY KEY,
+    kind VARCHAR(10) NOT NULL,
+    target_id INTEGER,
+    user_id INTEGER,
+    verdict VARCHAR(10) NOT NULL,
+    reasons VARCHAR(1000) NOT NULL DEFAULT '',
+    content VARCHAR(10000) NOT NULL DEFAULT '',
+    ip_address VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT content_filter_log_kind_check CHECK (kind IN ('review', 'contact')),
+    CONSTRAINT content_filter_log_verdict_check CHECK (verdict IN ('allow', 'hold', 'reject')),
+    CONSTRAINT fk_content_filter_log_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_content_filter_logs_kind_created_at ON content_filter_logs (kind, created_at);
+
+ALTER TABLE "contacts" ADD COLUMN is_active BOOLEAN NOT NULL DEFAULT true;
@@ -0,0 +1,14 @@
+CREATE TABLE "review_revisions" (
+    id SERIAL PRIMARY KEY,
+    review_id INTEGER NOT NULL,
+    revision INTEGER NOT NULL,
+    rating NUMERIC(2,1) NOT NULL,
+    body VARCHAR(10000) NOT NULL,
+    editor_id INTEGER,
+    editor_role VARCHAR(10) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT review_revision_editor_role_check CHECK (editor_role IN ('author', 'admin')),
+    CONSTRAINT uc_review_revision UNIQUE (review_id, revision),
+    CONSTRAINT fk_review_revision_review FOREIGN KEY (review_id) REFERENCES reviews(id) ON DELETE CASCADE,
+    CONSTRAINT fk_review_revision_editor FOREIGN KEY (editor_id) REFERENCES users(id) ON DELETE SET NULL
+);
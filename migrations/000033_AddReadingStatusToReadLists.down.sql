@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS "read_list_reads";
+
+ALTER TABLE "read_lists"
+    DROP CONSTRAINT IF EXISTS read_list_current_page_check,
+    DROP CONSTRAINT IF EXISTS read_list_status_check,
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS finished_at,
+    DROP COLUMN IF EXISTS started_at,
+    DROP COLUMN IF EXISTS current_page,
+    DROP COLUMN IF EXISTS status;
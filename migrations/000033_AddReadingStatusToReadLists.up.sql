@@ -0,0 +1,20 @@
+ALTER TABLE "read_lists"
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'want_to_read',
+    ADD COLUMN current_page INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN started_at TIMESTAMPTZ,
+    ADD COLUMN finished_at TIMESTAMPTZ,
+    ADD COLUMN updated_at TIMESTAMPTZ,
+    ADD CONSTRAINT read_list_status_check CHECK (status IN ('want_to_read', 'currently_reading', 'finished', 'abandoned')),
+    ADD CONSTRAINT read_list_current_page_check CHECK (current_page >= 0);
+
+CREATE TABLE "read_list_reads" (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    book_id INTEGER NOT NULL,
+    started_at TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_read_list_read_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_read_list_read_book FOREIGN KEY (book_id) REFERENCES books(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_read_list_reads_user_finished ON read_list_reads (user_id, finished_at);
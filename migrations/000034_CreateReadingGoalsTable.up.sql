@@ -0,0 +1,16 @@
+CREATE TABLE "reading_goals" (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    year INTEGER NOT NULL,
+    target INTEGER NOT NULL,
+    remind BOOLEAN NOT NULL DEFAULT FALSE,
+    unsubscribe_token VARCHAR(64) NOT NULL UNIQUE,
+    last_reminded_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT reading_goal_target_check CHECK (target > 0),
+    CONSTRAINT uc_reading_goal_user_year UNIQUE (user_id, year),
+    CONSTRAINT fk_reading_goal_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_reading_goals_year ON reading_goals (year);
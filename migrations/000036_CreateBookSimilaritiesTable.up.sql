@@ -0,0 +1,13 @@
+CREATE TABLE "book_similarities" (
+    book_id INTEGER NOT NULL,
+    similar_book_id INTEGER NOT NULL,
+    score DOUBLE PRECISION NOT NULL,
+    support INTEGER NOT NULL,
+    computed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (book_id, similar_book_id),
+    CONSTRAINT book_similarity_self_check CHECK (book_id <> similar_book_id),
+    CONSTRAINT fk_book_similarity_book FOREIGN KEY (book_id) REFERENCES books(id) ON DELETE CASCADE,
+    CONSTRAINT fk_book_similarity_similar_book FOREIGN KEY (similar_book_id) REFERENCES books(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_book_similarities_score ON book_similarities (book_id, score DESC);
@@ -0,0 +1,12 @@
+CREATE TABLE "book_content_similarities" (
+    book_id INTEGER NOT NULL,
+    similar_book_id INTEGER NOT NULL,
+    score DOUBLE PRECISION NOT NULL,
+    computed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (book_id, similar_book_id),
+    CONSTRAINT book_content_similarity_self_check CHECK (book_id <> similar_book_id),
+    CONSTRAINT fk_book_content_similarity_book FOREIGN KEY (book_id) REFERENCES books(id) ON DELETE CASCADE,
+    CONSTRAINT fk_book_content_similarity_similar_book FOREIGN KEY (similar_book_id) REFERENCES books(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_book_content_similarities_score ON book_content_similarities (book_id, score DESC);
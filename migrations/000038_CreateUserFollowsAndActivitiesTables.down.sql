@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "activities";
+DROP TABLE IF EXISTS "privacy_settings";
+DROP TABLE IF EXISTS "user_follows";
\ No newline at end of file
@@ -0,0 +1,38 @@
+CREATE TABLE "user_follows" (
+    follower_id INTEGER NOT NULL,
+    followee_id INTEGER NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (follower_id, followee_id),
+    CONSTRAINT user_follow_self_check CHECK (follower_id <> followee_id),
+    CONSTRAINT fk_user_follows_follower_id FOREIGN KEY (follower_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_user_follows_followee_id FOREIGN KEY (followee_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_user_follows_followee_id ON user_follows (followee_id);
+
+CREATE TABLE "privacy_settings" (
+    user_id INTEGER PRIMARY KEY,
+    activity_visibility VARCHAR(10) NOT NULL DEFAULT 'public',
+    updated_at TIMESTAMPTZ,
+    CONSTRAINT privacy_activity_visibility_check CHECK (activity_visibility IN ('public', 'followers', 'private')),
+    CONSTRAINT fk_privacy_settings_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE "activities" (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    book_id INTEGER,
+    review_id INTEGER,
+    shelf_id INTEGER,
+    author_id INTEGER,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT activity_type_check CHECK (type IN ('review_posted', 'book_finished', 'shelf_created', 'author_followed')),
+    CONSTRAINT fk_activities_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_activities_book_id FOREIGN KEY (book_id) REFERENCES books(id) ON DELETE CASCADE,
+    CONSTRAINT fk_activities_review_id FOREIGN KEY (review_id) REFERENCES reviews(id) ON DELETE CASCADE,
+    CONSTRAINT fk_activities_shelf_id FOREIGN KEY (shelf_id) REFERENCES shelves(id) ON DELETE CASCADE,
+    CONSTRAINT fk_activities_author_id FOREIGN KEY (author_id) REFERENCES authors(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_activities_user_id_created_at ON activities (user_id, created_at DESC);
@@ -0,0 +1,5 @@
+ALTER TABLE "privacy_settings"
+    DROP COLUMN IF EXISTS show_reviews,
+    DROP COLUMN IF EXISTS show_shelves,
+    DROP COLUMN IF EXISTS show_stats,
+    DROP COLUMN IF EXISTS show_followed_authors;
@@ -0,0 +1,5 @@
+ALTER TABLE "privacy_settings"
+    ADD COLUMN show_reviews BOOLEAN NOT NULL DEFAULT TRUE,
+    ADD COLUMN show_shelves BOOLEAN NOT NULL DEFAULT TRUE,
+    ADD COLUMN show_stats BOOLEAN NOT NULL DEFAULT TRUE,
+    ADD COLUMN show_followed_authors BOOLEAN NOT NULL DEFAULT TRUE;
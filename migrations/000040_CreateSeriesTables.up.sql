@@ -0,0 +1,20 @@
+CREATE TABLE "series" (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE "book_series" (
+    series_id INTEGER NOT NULL,
+    book_id INTEGER NOT NULL,
+    position NUMERIC(8, 2) NOT NULL,
+    PRIMARY KEY (series_id, book_id),
+    CONSTRAINT book_series_position_check CHECK (position > 0),
+    CONSTRAINT book_series_position_unique UNIQUE (series_id, position),
+    CONSTRAINT fk_book_series_series FOREIGN KEY (series_id) REFERENCES series(id) ON DELETE CASCADE,
+    CONSTRAINT fk_book_series_book FOREIGN KEY (book_id) REFERENCES books(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_book_series_book ON book_series (book_id);
@@ -0,0 +1,3 @@
+ALTER TABLE "privacy_settings"
+    DROP CONSTRAINT IF EXISTS privacy_activity_visibility_check,
+    ADD CONSTRAINT privacy_activity_visibility_check CHECK (activity_visibility IN ('public', 'followers', 'private'));
@@ -0,0 +1,5 @@
+UPDATE "privacy_settings" SET activity_visibility = 'private' WHERE activity_visibility = 'followers';
+
+ALTER TABLE "privacy_settings"
+    DROP CONSTRAINT IF EXISTS privacy_activity_visibility_check,
+    ADD CONSTRAINT privacy_activity_visibility_check CHECK (activity_visibility IN ('public', 'private'));
@@ -0,0 +1 @@
+ALTER TABLE "email_outbox" ALTER COLUMN subject TYPE VARCHAR(255) USING LEFT(subject, 255);
@@ -0,0 +1 @@
+ALTER TABLE "reports" ADD COLUMN hid_target BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,3 @@
+ALTER TABLE "reviews" ADD COLUMN published BOOLEAN NOT NULL DEFAULT false;
+UPDATE "reviews" SET published = true
+WHERE is_active OR id IN (SELECT review_id FROM activities WHERE type = 'review_posted' AND review_id IS NOT NULL);
@@ -0,0 +1,80 @@
+// Notification bell in the header. It is wrapped in a function so it does not clash with the page scripts.
+(() => {
+    const csrfToken = document.querySelector('meta[name="csrf_token"]').getAttribute('content')
+    const toggle = document.getElementById("notification-toggle")
+    const dropdown = document.getElementById("notification-dropdown")
+    const list = document.getElementById("notification-list")
+    const count = document.getElementById("notification-count")
+    const readAll = document.getElementById("notification-read-all")
+
+    const post = async (url) => {
+        await fetch(url, {
+            method: 'POST',
+            headers: {
+                "Content-Type": "application/json",
+                "X-CSRF-Token": csrfToken,
+            }
+        })
+    }
+
+    const showCount = (unread) => {
+        if (unread > 0) {
+            count.innerText = unread > 99 ? "99+" : unread
+            count.classList.remove("d-none")
+        } else {
+            count.classList.add("d-none")
+        }
+    }
+
+    const load = async () => {
+        const response = await fetch(`/api/notifications?limit=10`)
+        const payload = await response.json()
+        const data = payload.data
+        showCount(data.unread)
+        if (data.notifications.length === 0) {
+            list.innerHTML = `<li>No notifications yet</li>`
+            return
+        }
+        // the message is added as a text node so it is never parsed as html
+        list.replaceChildren(...data.notifications.map((n) => {
+            const item = document.createElement("li")
+            if (!n.is_read) {
+                item.classList.add("unread")
+            }
+            item.dataset.id = n.id
+            item.dataset.link = n.link
+            const time = document.createElement("small")
+            time.textContent = new Date(n.created_at).toLocaleString()
+            item.append(n.message, " ", time)
+            return item
+        }))
+    }
+
+    list.addEventListener("click", async (e) => {
+        const item = e.target.closest("li[data-id]")
+        if (!item) {
+            return
+        }
+        if (item.classList.contains("unread")) {
+            await post(`/api/notifications/${item.dataset.id}/read`)
+        }
+        // only links inside the site are followed
+        const link = item.dataset.link
+        if (link && link.startsWith("/") && !link.startsWith("//")) {
+            window.location.href = link
+            return
+        }
+        load()
+    })
+
+    toggle.addEventListener("click", () => {
+        dropdown.classList.toggle("d-none")
+    })
+
+    readAll.addEventListener("click", async () => {
+        await post(`/api/notifications/read-all`)
+        load()
+    })
+
+    load()
+})()
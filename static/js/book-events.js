@@ -0,0 +1,78 @@
+// live updates of the reviews and the average rating of the book detail page
+(() => {
+    const reviews = document.getElementById("reviews")
+    const averageRating = document.getElementById("average-rating")
+    if (!reviews || !window.EventSource) {
+        return
+    }
+
+    const escapeHtml = (value) => String(value)
+        .replace(/&/g, "&amp;")
+        .replace(/</g, "&lt;")
+        .replace(/>/g, "&gt;")
+        .replace(/"/g, "&quot;")
+        .replace(/'/g, "&#39;")
+
+    const reviewBox = (review) => {
+        const box = document.createElement("div")
+        box.className = "d-flex d-flex-col d-gap review-box"
+        box.id = `review-${review.id}`
+        box.innerHTML = `
+            <div class="d-flex justify-between">
+                <div>
+                    <p><strong>Rating: </strong><span class="review-rating">${escapeHtml(review.rating)}</span></p>
+                    <p><strong>By </strong>@${escapeHtml(review.username)}</p>
+                    <p><strong>Review: </strong></p>
+                    <div class="review-body">${review.body_html}</div>
+                </div>
+                <div>
+                    <p><strong>Created at: Just now</strong></p>
+                    <p><strong>Updated at: <span class="review-updated">Just now</span></strong></p>
+                    <p class="review-edited${review.edited ? "" : " d-none"}"><em>Edited</em></p>
+                </div>
+            </div>`
+        return box
+    }
+
+    const source = new EventSource(`/books/${reviews.dataset.isbn}/events`)
+
+    source.addEventListener("review.created", (e) => {
+        const review = JSON.parse(e.data)
+        if (document.getElementById(`review-${review.id}`)) {
+            return
+        }
+        reviews.appendChild(reviewBox(review))
+    })
+
+    source.addEventListener("review.updated", (e) => {
+        const review = JSON.parse(e.data)
+        const box = document.getElementById(`review-${review.id}`)
+        if (!box) {
+            reviews.appendChild(reviewBox(review))
+            return
+        }
+        if (review.edited) {
+            box.querySelector(".review-edited").classList.remove("d-none")
+        }
+        box.querySelector(".review-rating").textContent = review.rating
+        // the body is rendered from Markdown and sanitized by the server
+        box.querySelector(".review-body").innerHTML = review.body_html
+        box.querySelector(".review-updated").textContent = "Just now"
+    })
+
+    // deleted, hidden and held reviews are removed
+    source.addEventListener("review.deleted", (e) => {
+        const review = JSON.parse(e.data)
+        const box = document.getElementById(`review-${review.id}`)
+        if (box) {
+            box.remove()
+        }
+    })
+
+    source.addEventListener("rating.updated", (e) => {
+        const rating = JSON.parse(e.data)
+        if (averageRating) {
+            averageRating.textContent = rating.average
+        }
+    })
+})()
@@ -0,0 +1,109 @@
+// reading status and progress widget on the book detail page
+(() => {
+    const widget = document.getElementById("reading-progress")
+    if (!widget) {
+        return
+    }
+    const bookId = document.getElementById("book_id").value
+    const csrfToken = document.querySelector('meta[name="csrf_token"]').getAttribute('content')
+    const statusSelect = document.getElementById("reading-status")
+    const pageForm = document.getElementById("reading-page-form")
+    const pageInput = document.getElementById("current-page")
+    const bar = document.getElementById("reading-bar")
+    const errorText = document.getElementById("reading-error")
+    const dates = document.getElementById("reading-dates")
+    const count = document.getElementById("reading-count")
+    const againButton = document.getElementById("reading-again")
+    const addReadList = document.getElementById("addReadList")
+    const removeReadList = document.getElementById("removeReadList")
+
+    const show = (element, visible) => {
+        element.classList.toggle("d-none", !visible)
+    }
+
+    const dateOnly = (value) => new Date(value).toLocaleDateString()
+
+    const render = (progress) => {
+        show(errorText, false)
+        if (!progress.in_read_list) {
+            statusSelect.value = ""
+            show(pageForm, false)
+            show(bar, false)
+            show(dates, false)
+            show(count, false)
+            show(againButton, false)
+            return
+        }
+        statusSelect.value = progress.status
+        pageInput.value = progress.current_page
+        bar.value = progress.percent
+        show(pageForm, progress.status === "currently_reading" || progress.status === "abandoned")
+        show(bar, progress.status !== "want_to_read")
+
+        let text = []
+        if (progress.started_at) {
+            text.push(`Started ${dateOnly(progress.started_at)}`)
+        }
+        if (progress.finished_at) {
+            text.push(`Finished ${dateOnly(progress.finished_at)}`)
+        }
+        dates.textContent = text.join(", ")
+        show(dates, text.length > 0)
+
+        count.textContent = progress.read_count === 1 ? "Read once" : `Read ${progress.read_count} times`
+        show(count, progress.read_count > 0)
+        show(againButton, progress.status === "finished")
+
+        if (addReadList && removeReadList) {
+            show(addReadList, false)
+            show(removeReadList, true)
+        }
+    }
+
+    const request = async (url, options) => {
+        const response = await fetch(url, options)
+        const data = await response.json().catch(() => ({}))
+        if (!response.ok) {
+            errorText.textContent = data.errors ? Object.values(data.errors).flat().join(", ") : (data.message || "Something went wrong")
+            show(errorText, true)
+            return
+        }
+        render(data.data)
+    }
+
+    const post = (path, params) => request(`/api/books/${bookId}/reading/${path}`, {
+        method: "POST",
+        headers: {
+            "Content-Type": "application/x-www-form-urlencoded",
+            "X-CSRF-Token": csrfToken,
+        },
+        body: new URLSearchParams(params),
+    })
+
+    statusSelect.addEventListener("change", () => {
+        post("status", { status: statusSelect.value })
+    })
+
+    againButton.addEventListener("click", () => {
+        post("status", { status: "currently_reading" })
+    })
+
+    pageForm.addEventListener("submit", (event) => {
+        event.preventDefault()
+        post("progress", { current_page: pageInput.value })
+    })
+
+    // books added to the read list start as want to read and removing them also removes their reading progress
+    if (addReadList) {
+        addReadList.addEventListener("click", () => {
+            render({ in_read_list: true, status: "want_to_read", current_page: 0, percent: 0, read_count: 0 })
+        })
+    }
+    if (removeReadList) {
+        removeReadList.addEventListener("click", () => {
+            render({ in_read_list: false })
+        })
+    }
+
+    request(`/api/books/${bookId}/reading`)
+})()
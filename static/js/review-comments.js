@@ -0,0 +1,123 @@
+// threaded comments on the reviews of the book detail page
+(() => {
+    const reviews = document.getElementById("reviews")
+    if (!reviews) {
+        return
+    }
+    const csrfToken = document.querySelector('meta[name="csrf_token"]').getAttribute('content')
+    const isbn = reviews.dataset.isbn
+    const userID = parseInt(reviews.dataset.userId || "0")
+
+    const escapeHtml = (text) => {
+        const div = document.createElement("div")
+        div.textContent = text
+        return div.innerHTML
+    }
+
+    const request = async (url, method, values) => {
+        const options = {
+            method: method,
+            headers: {
+                "X-CSRF-Token": csrfToken,
+            }
+        }
+        if (values) {
+            options.body = new URLSearchParams(values)
+        }
+        const response = await fetch(url, options)
+        const data = await response.json()
+        if (!response.ok) {
+            alert(data.message || "Something went wrong")
+            return null
+        }
+        return data
+    }
+
+    const commentsURL = (reviewID) => `/books/${isbn}/reviews/${reviewID}/comments`
+
+    const renderComments = (box, comments) => {
+        if (!comments || comments.length === 0) {
+            return ""
+        }
+        return `<ul class="comment-list">${comments.map((comment) => {
+            let actions = ""
+            if (userID && comment.user_id) {
+                actions += `<button type="button" data-action="reply" data-id="${comment.id}">Reply</button>`
+                if (comment.user_id === userID) {
+                    actions += `<button type="button" data-action="edit" data-id="${comment.id}">Edit</button>`
+                    actions += `<button type="button" data-action="delete" data-id="${comment.id}">Delete</button>`
+                } else {
+                    actions += `<button type="button" class="report-toggle" data-url="${commentsURL(box.dataset.reviewId)}/${comment.id}/report">Report</button>`
+                }
+            }
+            const body = comment.user_id
+                ? `<p><strong>@${escapeHtml(comment.username)}</strong> <small>${new Date(comment.created_at).toLocaleString()}</small></p>
+                   <p class="comment-body" data-id="${comment.id}">${escapeHtml(comment.body)}</p>`
+                : `<p><em>This comment was removed</em></p>`
+            return `<li>${body}<div class="d-flex d-gap">${actions}</div>${renderComments(box, comment.replies)}</li>`
+        }).join("")}</ul>`
+    }
+
+    const loadComments = async (box) => {
+        const thread = box.querySelector(".comments-thread")
+        const response = await fetch(commentsURL(box.dataset.reviewId))
+        const data = await response.json()
+        thread.innerHTML = renderComments(box, data.data) || "<p>No comments yet</p>"
+    }
+
+    document.querySelectorAll(".review-comments").forEach((box) => {
+        const reviewID = box.dataset.reviewId
+        const thread = box.querySelector(".comments-thread")
+        const form = box.querySelector(".comment-form")
+
+        box.querySelector(".comments-toggle").addEventListener("click", () => {
+            thread.classList.toggle("d-none")
+            if (form) {
+                form.classList.toggle("d-none")
+            }
+            if (!thread.classList.contains("d-none")) {
+                loadComments(box)
+            }
+        })
+
+        if (form) {
+            form.addEventListener("submit", async (e) => {
+                e.preventDefault()
+                const values = { body: form.body.value }
+                if (form.dataset.parentId) {
+                    values.parent_id = form.dataset.parentId
+                }
+                if (await request(commentsURL(reviewID), "POST", values)) {
+                    form.reset()
+                    delete form.dataset.parentId
+                    form.body.placeholder = "Write a comment..."
+                    loadComments(box)
+                }
+            })
+        }
+
+        thread.addEventListener("click", async (e) => {
+            const button = e.target.closest("button[data-action]")
+            if (!button) {
+                return
+            }
+            const id = button.dataset.id
+            const url = `${commentsURL(reviewID)}/${id}`
+            if (button.dataset.action === "reply") {
+                form.dataset.parentId = id
+                form.body.placeholder = "Write a reply..."
+                form.body.focus()
+            } else if (button.dataset.action === "edit") {
+                const current = thread.querySelector(`.comment-body[data-id="${id}"]`).textContent
+                const body = prompt("Edit your comment", current)
+                if (body !== null && body !== current && await request(url, "PUT", { body: body })) {
+                    loadComments(box)
+                }
+            } else if (button.dataset.action === "delete") {
+                if (confirm("Do you want to delete this comment?") && await request(url, "DELETE")) {
+                    loadComments(box)
+                }
+            }
+        })
+    })
+})()
@@ -0,0 +1,29 @@
+// preview of the Markdown review body on the create and update review forms
+(() => {
+    const body = document.getElementById("body")
+    const button = document.getElementById("preview-button")
+    const preview = document.getElementById("review-preview")
+    if (!body || !button || !preview) {
+        return
+    }
+    const csrfToken = document.querySelector('meta[name="csrf_token"]').getAttribute('content')
+
+    button.addEventListener("click", async () => {
+        const response = await fetch("/api/reviews/preview", {
+            method: "POST",
+            headers: {
+                "Content-Type": "application/x-www-form-urlencoded",
+                "X-CSRF-Token": csrfToken,
+            },
+            body: new URLSearchParams({ body: body.value }),
+        })
+        const data = await response.json().catch(() => ({}))
+        if (!response.ok) {
+            preview.textContent = data.errors ? Object.values(data.errors).flat().join(", ") : "The preview could not be loaded"
+        } else {
+            // the preview is rendered from Markdown and sanitized by the server
+            preview.innerHTML = data.data.html
+        }
+        preview.classList.remove("d-none")
+    })
+})()
@@ -0,0 +1,38 @@
+// reports of the reviews and comments of the book detail page sent to the moderators
+(() => {
+    const template = document.getElementById("report-form-template")
+    if (!template) {
+        return
+    }
+    const csrfToken = document.querySelector('meta[name="csrf_token"]').getAttribute('content')
+
+    document.addEventListener("click", (e) => {
+        const button = e.target.closest("button.report-toggle")
+        if (!button) {
+            return
+        }
+        const next = button.nextElementSibling
+        if (next && next.classList.contains("report-form")) {
+            next.remove()
+            return
+        }
+        const form = template.content.firstElementChild.cloneNode(true)
+        form.addEventListener("submit", async (e) => {
+            e.preventDefault()
+            const response = await fetch(button.dataset.url, {
+                method: 'POST',
+                headers: {
+                    "X-CSRF-Token": csrfToken,
+                },
+                body: new URLSearchParams(new FormData(form)),
+            })
+            const data = await response.json()
+            alert(data.message || "Something went wrong")
+            if (response.ok || response.status === 409) {
+                form.remove()
+                button.disabled = true
+            }
+        })
+        button.after(form)
+    })
+})()
@@ -0,0 +1,36 @@
+// helpful and unhelpful votes on the reviews of the book detail page
+(() => {
+    const csrfToken = document.querySelector('meta[name="csrf_token"]').getAttribute('content')
+
+    const vote = async (reviewID, vote, remove) => {
+        const url = remove ? `/api/reviews/${reviewID}/vote` : `/api/reviews/${reviewID}/vote/${vote}`
+        const response = await fetch(url, {
+            method: remove ? 'DELETE' : 'POST',
+            headers: {
+                "Content-Type": "application/json",
+                "X-CSRF-Token": csrfToken,
+            }
+        })
+        if (!response.ok) {
+            return null
+        }
+        const data = await response.json()
+        return data.data
+    }
+
+    document.querySelectorAll(".review-votes").forEach((votes) => {
+        votes.querySelectorAll("button[data-vote]").forEach((button) => {
+            button.addEventListener("click", async () => {
+                const count = await vote(votes.dataset.reviewId, button.dataset.vote, button.classList.contains("voted"))
+                if (!count) {
+                    return
+                }
+                votes.querySelector(".helpful-count").textContent = count.helpful
+                votes.querySelector(".unhelpful-count").textContent = count.unhelpful
+                votes.querySelectorAll("button[data-vote]").forEach((b) => {
+                    b.classList.toggle("voted", b.dataset.vote === count.user_vote)
+                })
+            })
+        })
+    })
+})()
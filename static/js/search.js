@@ -19,7 +19,33 @@ const getData = async (searchType, search, order, limit) => {
         const content = response.json();
         return content;
     } else if (parts[1] === "read-list") {
-        const response = await fetch(`${protocol}//${host}/api/read-list?search=${search}&sort=${order}&limit=${limit}&page=${currentPage}`)
+        let status = document.getElementById("status").value
+        const response = await fetch(`${protocol}//${host}/api/read-list?search=${search}&sort=${order}&limit=${limit}&page=${currentPage}&status=${status}`)
+        const content = response.json();
+        return content;
+    } else if (searchType === "admin-emails") {
+        let status = document.getElementById("status").value
+        const response = await fetch(`${protocol}//${host}/api/${searchType}?search=${search}&sort=${order}&limit=${limit}&page=${currentPage}&status=${status}`)
+        const content = response.json();
+        return content;
+    } else if (searchType === "moderation-reports") {
+        let status = document.getElementById("status").value
+        const response = await fetch(`${protocol}//${host}/moderation/api/reports?search=${search}&sort=${order}&limit=${limit}&page=${currentPage}&status=${status}`)
+        const content = response.json();
+        return content;
+    } else if (searchType === "admin-review-comments" || searchType === "admin-content-filter-logs") {
+        let status = document.getElementById("status").value
+        const response = await fetch(`${protocol}//${host}/api/${searchType}?search=${search}&sort=${order}&limit=${limit}&page=${currentPage}&status=${status}`)
+        const content = response.json();
+        return content;
+    } else if (searchType === "admin-webhook-deliveries") {
+        let status = document.getElementById("status").value
+        let webhookId = document.getElementById("webhook-id").value
+        const response = await fetch(`${protocol}//${host}/api/${searchType}?search=${search}&sort=${order}&limit=${limit}&page=${currentPage}&status=${status}&webhook_id=${webhookId}`)
+        const content = response.json();
+        return content;
+    } else if (parts[1] === "shelves") {
+        const response = await fetch(`${protocol}//${host}/api/shelves/${parts[2]}/books?search=${search}&sort=${order}&limit=${limit}&page=${currentPage}`)
         const content = response.json();
         return content;
     } else if (parts[1] === "buy-list") {
@@ -35,6 +61,13 @@ const getData = async (searchType, search, order, limit) => {
 
 let displayDiv = document.getElementById("displayDiv")
 
+// escapeHtml escapes the text received from external sources before adding it to the page
+const escapeHtml = (text) => {
+    const div = document.createElement("div")
+    div.textContent = text
+    return div.innerHTML
+}
+
 const display = async () => {
     let searchType = document.getElementById("search-type").value
     let search = await document.getElementById("search-book").value;
@@ -407,6 +440,222 @@ const display = async () => {
             return actionButton
         }).join("")
         displayDiv.innerHTML = displayItems
+    } else if (searchType === "admin-emails") {
+        let emails = data.emails;
+        let displayItems = emails.map((obj)=> {
+            const { id, to, subject, status, attempts, max_attempts, last_error, created_at, sent_at } = obj
+            let actionButton = `
+            <tr>
+                <td>${id}</td>
+                <td>${escapeHtml(to)}</td>
+                <td>${escapeHtml(subject)}</td>
+                <td>${escapeHtml(status)}</td>
+                <td>${attempts}/${max_attempts}</td>
+                <td>${escapeHtml(last_error)}</td>
+                <td>${created_at}</td>
+                <td>${sent_at ? sent_at : "-"}</td>
+                <td>
+                    <div class="action-icons">
+            `
+            if (status !== "pending") {
+                actionButton += `
+                <button ><img width="19px" height="19px" src="/static/images/edit-icon.png" alt="resend-icon" onclick="openModal('resend-${id}')" /></button>
+
+                <div class="jw-modal" id="resend-${id}">
+                    <div class="jw-modal-body">
+                        <form action="/admin/emails/detail/${id}/resend" method="post">
+                            <input type="hidden" name="csrf_token" id="csrf_token" value="${csrfToken}">
+                            <p>Do you want to resend this email?</p>
+                            <input type="submit" value="Resend Email" class="del-button">
+                            <button type="button" onclick="closeModal()" class="add-button">No</button>
+                        </form>
+                    </div>
+                </div>
+                `
+            }
+            actionButton += `
+                    </div>
+                </td>
+            </tr>
+            `
+            return actionButton
+        }).join("")
+        displayDiv.innerHTML = displayItems
+    } else if (searchType === "admin-webhook-deliveries") {
+        let webhookId = document.getElementById("webhook-id").value
+        let deliveries = data.deliveries;
+        let displayItems = deliveries.map((obj)=> {
+            const { id, event, status, attempts, max_attempts, response_status, response_body, last_error, created_at, delivered_at } = obj
+            let actionButton = `
+            <tr>
+                <td>${id}</td>
+                <td>${event}</td>
+                <td>${status}</td>
+                <td>${attempts}/${max_attempts}</td>
+                <td>${response_status ? response_status : "-"} ${escapeHtml(response_body)}</td>
+                <td>${escapeHtml(last_error)}</td>
+                <td>${created_at}</td>
+                <td>${delivered_at ? delivered_at : "-"}</td>
+                <td>
+                    <div class="action-icons">
+            `
+            if (status !== "pending") {
+                actionButton += `
+                <button ><img width="19px" height="19px" src="/static/images/edit-icon.png" alt="redeliver-icon" onclick="openModal('redeliver-${id}')" /></button>
+
+                <div class="jw-modal" id="redeliver-${id}">
+                    <div class="jw-modal-body">
+                        <form action="/admin/webhooks/detail/${webhookId}/deliveries/${id}/redeliver" method="post">
+                            <input type="hidden" name="csrf_token" id="csrf_token" value="${csrfToken}">
+                            <p>Do you want to redeliver this event?</p>
+                            <input type="submit" value="Redeliver" class="del-button">
+                            <button type="button" onclick="closeModal()" class="add-button">No</button>
+                        </form>
+                    </div>
+                </div>
+                `
+            }
+            actionButton += `
+                    </div>
+                </td>
+            </tr>
+            `
+            return actionButton
+        }).join("")
+        displayDiv.innerHTML = displayItems
+    } else if (searchType === "admin-review-comments") {
+        let comments = data.comments;
+        let displayItems = comments.map((obj)=> {
+            const { id, review_id, book_title, username, parent_id, body, is_active, is_flagged, is_deleted, created_at } = obj
+            let status = []
+            if (is_flagged) {
+                status.push("flagged")
+            }
+            if (!is_active) {
+                status.push("hidden")
+            }
+            if (is_deleted) {
+                status.push("deleted")
+            }
+            let actionButton = `
+            <tr>
+                <td>${id}</td>
+                <td><a href="/admin/reviews/detail/${review_id}">${review_id}</a></td>
+                <td>${escapeHtml(book_title)}</td>
+                <td>${escapeHtml(username)}</td>
+                <td>${parent_id ? `<small>reply to #${parent_id}</small><br>` : ""}${escapeHtml(body)}</td>
+                <td>${status.length ? status.join(", ") : "visible"}</td>
+                <td>${created_at}</td>
+                <td>
+                    <div class="action-icons">
+            `
+            if (!is_deleted) {
+                actionButton += `
+                <form action="/admin/review-comments/detail/${id}/moderate" method="post">
+                    <input type="hidden" name="csrf_token" value="${csrfToken}">
+                    <input type="hidden" name="is_active" value="${is_active && !is_flagged ? "false" : "true"}">
+                    <button type="submit" class="add-button">${is_active && !is_flagged ? "Hide" : "Approve"}</button>
+                </form>
+                `
+                if (is_flagged && is_active) {
+                    actionButton += `
+                    <form action="/admin/review-comments/detail/${id}/moderate" method="post">
+                        <input type="hidden" name="csrf_token" value="${csrfToken}">
+                        <input type="hidden" name="is_active" value="false">
+                        <button type="submit" class="del-button">Hide</button>
+                    </form>
+                    `
+                }
+            }
+            actionButton += `
+                <button ><img width="19px" height="19px" src="/static/images/del-icon.png" alt="del-icon" onclick="openModal('delete-${id}')" /></button>
+
+                <div class="jw-modal" id="delete-${id}">
+                    <div class="jw-modal-body">
+                        <form action="/admin/review-comments/detail/${id}/delete" method="post">
+                            <input type="hidden" name="csrf_token" id="csrf_token" value="${csrfToken}">
+                            <p>Do you want to delete this comment and its replies?</p>
+                            <input type="submit" value="Delete Record" class="del-button">
+                            <button type="button" onclick="closeModal()" class="add-button">No</button>
+                        </form>
+                    </div>
+                </div>
+                    </div>
+                </td>
+            </tr>
+            `
+            return actionButton
+        }).join("")
+        displayDiv.innerHTML = displayItems
+    } else if (searchType === "admin-content-filter-logs") {
+        let logs = data.logs;
+        let displayItems = logs.map((obj)=> {
+            const { id, kind, target_id, username, ip_address, content, verdict, reasons, created_at } = obj
+            let target = `${kind}`
+            if (target_id && kind === "review") {
+                target = `<a href="/admin/reviews/detail/${target_id}">review #${target_id}</a>`
+            } else if (target_id && kind === "contact") {
+                target = `<a href="/admin/contacts/detail/${target_id}">contact #${target_id}</a>`
+            }
+            return `
+            <tr>
+                <td>${id}</td>
+                <td>${target}</td>
+                <td>${username ? escapeHtml(username) : "-"}</td>
+                <td>${escapeHtml(ip_address)}</td>
+                <td>${escapeHtml(content)}</td>
+                <td>${verdict}</td>
+                <td>${reasons ? escapeHtml(reasons) : "-"}</td>
+                <td>${created_at}</td>
+            </tr>
+            `
+        }).join("")
+        displayDiv.innerHTML = displayItems
+    } else if (searchType === "moderation-reports") {
+        let reports = data.reports;
+        let resolutions = document.getElementById("resolutions").innerHTML
+        let displayItems = reports.map((obj)=> {
+            const { id, target_type, target_id, target_username, target_body, reporter_username, reason, details, status, resolution, resolution_note, resolved_by_username, open_reports, created_at } = obj
+            let actionButton = `
+            <tr>
+                <td>${id}</td>
+                <td>${target_type} #${target_id}</td>
+                <td>${target_username ? escapeHtml(target_username) : "-"}</td>
+                <td>${escapeHtml(target_body)}</td>
+                <td>${reason}${details ? `<br><small>${escapeHtml(details)}</small>` : ""}</td>
+                <td>${escapeHtml(reporter_username)}</td>
+                <td>${open_reports}</td>
+                <td>${status === "open" ? status : `${resolution} by ${escapeHtml(resolved_by_username || "-")}${resolution_note ? `<br><small>${escapeHtml(resolution_note)}</small>` : ""}`}</td>
+                <td>${created_at}</td>
+                <td>
+                    <div class="action-icons">
+            `
+            if (status === "open") {
+                actionButton += `
+                <button type="button" class="add-button" onclick="openModal('resolve-${id}')">Resolve</button>
+
+                <div class="jw-modal" id="resolve-${id}">
+                    <div class="jw-modal-body">
+                        <form action="/moderation/reports/${id}/resolve" method="post">
+                            <input type="hidden" name="csrf_token" value="${csrfToken}">
+                            <p>Resolve every open report of this ${target_type}</p>
+                            <select name="resolution">${resolutions}</select>
+                            <textarea name="note" maxlength="1000" placeholder="Resolution note"></textarea>
+                            <input type="submit" value="Resolve" class="del-button">
+                            <button type="button" onclick="closeModal()" class="add-button">Cancel</button>
+                        </form>
+                    </div>
+                </div>
+                `
+            }
+            actionButton += `
+                    </div>
+                </td>
+            </tr>
+            `
+            return actionButton
+        }).join("")
+        displayDiv.innerHTML = displayItems
     }
     paginationNumbers.innerHTML = ''
     const getPaginationNumbers = () => {
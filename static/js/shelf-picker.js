@@ -0,0 +1,52 @@
+// shelf picker on the book detail page, a checkbox for every shelf of the user
+(() => {
+    const picker = document.getElementById("shelf-picker")
+    if (!picker) {
+        return
+    }
+    const bookId = document.getElementById("book_id").value
+    const csrfToken = document.querySelector('meta[name="csrf_token"]').getAttribute('content')
+    const options = document.getElementById("shelf-options")
+    const errorText = document.getElementById("shelf-error")
+
+    const toggle = async (checkbox) => {
+        errorText.classList.add("d-none")
+        const response = await fetch(`/api/books/${bookId}/shelves/${checkbox.value}`, {
+            method: checkbox.checked ? "POST" : "DELETE",
+            headers: {
+                "X-CSRF-Token": csrfToken,
+            },
+        })
+        if (!response.ok) {
+            const data = await response.json().catch(() => ({}))
+            errorText.textContent = data.message || "Something went wrong"
+            errorText.classList.remove("d-none")
+            checkbox.checked = !checkbox.checked
+        }
+    }
+
+    const load = async () => {
+        const response = await fetch(`/api/shelves?book_id=${bookId}`)
+        if (!response.ok) {
+            return
+        }
+        const payload = await response.json()
+        const shelves = payload.data || []
+        if (shelves.length === 0) {
+            options.textContent = "You have no shelves yet."
+            return
+        }
+        options.replaceChildren(...shelves.map((shelf) => {
+            const label = document.createElement("label")
+            const checkbox = document.createElement("input")
+            checkbox.type = "checkbox"
+            checkbox.value = shelf.id
+            checkbox.checked = shelf.has_book
+            checkbox.addEventListener("change", () => toggle(checkbox))
+            label.append(checkbox, ` ${shelf.name}`)
+            return label
+        }))
+    }
+
+    load()
+})()
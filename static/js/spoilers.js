@@ -0,0 +1,9 @@
+// spoilers of the reviews are hidden until clicked
+(() => {
+    document.addEventListener("click", (e) => {
+        const spoiler = e.target.closest(".spoiler")
+        if (spoiler) {
+            spoiler.classList.toggle("revealed")
+        }
+    })
+})()
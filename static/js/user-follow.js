@@ -0,0 +1,36 @@
+// follow button on the public profile of a user
+(() => {
+    const usernameInput = document.getElementById("profile-username")
+    if (!usernameInput) {
+        return
+    }
+    const username = encodeURIComponent(usernameInput.value)
+    const csrfToken = document.querySelector('meta[name="csrf_token"]').getAttribute('content')
+    const follow = document.getElementById("user-follow")
+    const unfollow = document.getElementById("user-unfollow")
+    const followers = document.getElementById("user-followers")
+
+    const show = (status) => {
+        follow.classList.toggle("d-none", status.following)
+        unfollow.classList.toggle("d-none", !status.following)
+        followers.textContent = status.followers
+    }
+
+    const request = async (method) => {
+        const response = await fetch(`/api/users/${username}/follow`, {
+            method: method,
+            headers: {
+                "X-CSRF-Token": csrfToken,
+            },
+        })
+        if (!response.ok) {
+            return
+        }
+        const payload = await response.json()
+        show(payload.data)
+    }
+
+    follow.addEventListener("click", () => request("POST"))
+    unfollow.addEventListener("click", () => request("DELETE"))
+    request("GET")
+})()